@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/iamgilwell/aura/internal/ai"
+	"github.com/iamgilwell/aura/internal/config"
+	"github.com/iamgilwell/aura/internal/monitor"
+	"github.com/iamgilwell/aura/internal/power"
+)
+
+var askCmd = &cobra.Command{
+	Use:   "ask <request>",
+	Short: "Query or act on processes using natural language",
+	Long: `Translates a natural-language request such as "stop all the stale node
+dev servers older than a day" or "why is my laptop hot?" into a structured
+process query. Proposed actions are validated by the safety manager and are
+never executed without explicit confirmation.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runAsk,
+}
+
+func runAsk(cmd *cobra.Command, args []string) error {
+	cfg := config.Global
+
+	if !cfg.AI.Enabled || cfg.Anthropic.APIKey == "" {
+		return fmt.Errorf("aura ask requires AI to be enabled and ANTHROPIC_API_KEY to be set")
+	}
+
+	auditor, err := newAuditor(cfg)
+	if err != nil {
+		return fmt.Errorf("creating auditor: %w", err)
+	}
+	defer auditor.Close()
+
+	safetyMgr, err := newSafetyManager(cfg, cfg.Safety.ConsentLevel)
+	if err != nil {
+		return err
+	}
+	procMgr := newProcessManager(cfg, safetyMgr)
+	auditHooks(procMgr, auditor)
+
+	powerCalc := power.NewCalculator(cfg.Power.CPUWattPerPercent, cfg.Power.MemoryWattPerMB, cfg.Power.DiskWattPerMBps)
+
+	cache := ai.NewCache(cfg.AI.CacheSize, cfg.AI.CacheTTL)
+	aiEngine := ai.NewEngine(cfg.Anthropic.APIKey, cfg.Anthropic.Model, cache, cfg.AI.ConfidenceThreshold, safetyMgr.Settings().Aggressiveness)
+
+	mon := monitor.NewProcessMonitor(
+		cfg.Monitoring.ScanInterval,
+		cfg.Monitoring.HistorySize,
+		cfg.Safety.ProtectedProcs,
+	)
+	mon.SetPins(safetyMgr.Pins())
+	mon.SetContainerNames(procMgr.ContainerName)
+	procs := mon.Snapshot(500 * time.Millisecond)
+	procMgr.UpdateProtection(procs)
+
+	// Snoozed and kept processes are neither sent to the AI nor acted on
+	snoozeStore := newSnoozeStore(cfg)
+	snoozes, err := snoozeStore.List()
+	if err != nil {
+		return fmt.Errorf("reading snoozed processes: %w", err)
+	}
+	procs = snoozes.Filter(procs)
+
+	request := strings.Join(args, " ")
+	plan, err := aiEngine.Interpret(context.Background(), request, procs, mon.SystemMetrics())
+	if err != nil {
+		return fmt.Errorf("interpreting request: %w", err)
+	}
+	auditor.LogEvent("ask", fmt.Sprintf("request=%q intent=%s action=%s", request, plan.Intent, plan.Action))
+
+	if plan.Answer != "" {
+		fmt.Println(plan.Answer)
+		fmt.Println()
+	}
+
+	targets := plan.Query.Filter(procs)
+	if plan.Intent == ai.IntentExplain && len(targets) == 0 {
+		return nil
+	}
+	if len(targets) == 0 {
+		fmt.Println("No matching processes.")
+		return nil
+	}
+
+	var allowed []*monitor.ProcessInfo
+	fmt.Printf("%7s %-20s %-10s %6s %8s %-10s %s\n", "PID", "NAME", "USER", "CPU%", "MEM(MB)", "AGE", "STATUS")
+	for _, p := range targets {
+		status := ""
+		if plan.Intent == ai.IntentAction {
+			// The verdict covers every member of the termination scope
+			if v := procMgr.TerminationVerdict(p); v.Allowed {
+				allowed = append(allowed, p)
+				status = "will " + string(plan.Action)
+			} else {
+				status = "blocked: " + v.Reason
+			}
+		}
+		fmt.Printf("%7d %-20s %-10s %6.1f %8.1f %-10s %s\n",
+			p.PID, p.Name, p.User, p.CPU, p.MemoryMB,
+			time.Since(p.StartTime).Truncate(time.Minute), status)
+	}
+
+	if plan.Intent != ai.IntentAction || len(allowed) == 0 {
+		return nil
+	}
+
+	fmt.Printf("\n%s %d process(es)? Type 'yes' to confirm: ", plan.Action, len(allowed))
+	reader := bufio.NewReader(os.Stdin)
+	answer, _ := reader.ReadString('\n')
+	if strings.TrimSpace(strings.ToLower(answer)) != "yes" {
+		fmt.Println("Aborted, no processes were touched.")
+		auditor.LogEvent("ask_declined", fmt.Sprintf("request=%q targets=%d", request, len(allowed)))
+		return nil
+	}
+
+	// A snooze may have been added while waiting for the answer
+	if snoozes, err = snoozeStore.List(); err != nil {
+		return fmt.Errorf("reading snoozed processes: %w", err)
+	}
+
+	reason := fmt.Sprintf("ask: %s", request)
+	for _, p := range allowed {
+		if s, ok := snoozes.Find(p); ok {
+			fmt.Printf("Skipping %s (PID %d): %s %s\n", p.Name, p.PID, s.Target(), s.Expiry())
+			continue
+		}
+		result, err := procMgr.SafeTerminate(p, false)
+		if err != nil {
+			fmt.Printf("Failed to terminate PID %d: %v\n", p.PID, err)
+			continue
+		}
+		id, _ := logTermination(auditor, powerCalc, result, reason)
+		if cfg.Safety.DryRun {
+			fmt.Printf("Would terminate %s (PID %d)%s\n", p.Name, p.PID, result.Extent())
+			continue
+		}
+		hint := undoHint(result, id)
+		if err := saveRestore(procMgr, id, result); err != nil {
+			fmt.Printf("Warning: could not save restore record for PID %d: %v\n", p.PID, err)
+			hint = ""
+		}
+		fmt.Printf("%s %s (PID %d)%s, %s%s\n", terminated(result), p.Name, p.PID, result.Extent(), result.Outcome(), hint)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/iamgilwell/aura/internal/config"
+	"github.com/iamgilwell/aura/internal/power"
+	"github.com/iamgilwell/aura/internal/safety"
+)
+
+var containerCmd = &cobra.Command{
+	Use:   "container <pid> [stop|pause|signal]",
+	Short: "Stop or pause the container running a process",
+	Long: `Killing a process inside a container leaves the container half broken or
+makes its restart policy start it again. Without an action, shows the
+container running the process and what a termination would do under
+safety.container_action. With an action, terminates the process through
+the runtime API at safety.container_socket:
+
+  stop    stop the container: its stop signal, then SIGKILL
+  pause   freeze every process of the container
+  signal  signal the process as usual, ignoring the container
+
+Every process of the container is checked against the safety rules first.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runContainer,
+}
+
+func runContainer(cmd *cobra.Command, args []string) error {
+	cfg := config.Global
+
+	pid, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid PID %q", args[0])
+	}
+
+	safetyMgr, err := newSafetyManager(cfg, cfg.Safety.ConsentLevel)
+	if err != nil {
+		return err
+	}
+	procMgr := newProcessManager(cfg, safetyMgr)
+	proc, err := scanProcess(cfg, safetyMgr, procMgr, pid)
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 1 {
+		if proc.Container == "" {
+			fmt.Printf("%s (PID %d) is not running in a container.\n", proc.Name, proc.PID)
+			return nil
+		}
+		fmt.Printf("Process:   %s (PID %d)\n", proc.Name, proc.PID)
+		fmt.Printf("Container: %s\n", proc.Container)
+		if proc.ContainerName != "" {
+			fmt.Printf("Name:      %s\n", proc.ContainerName)
+		}
+		if members, err := procMgr.Members(proc, safety.ScopeContainer); err == nil {
+			fmt.Printf("Members:   %d processes\n", len(members))
+		}
+		if action, err := procMgr.ContainerPlan(proc); err != nil {
+			fmt.Printf("Planned:   none (%v)\n", err)
+		} else if action == safety.ContainerSignal {
+			fmt.Println("Planned:   signal the process")
+		} else {
+			fmt.Printf("Planned:   %s the container\n", action)
+		}
+		return nil
+	}
+
+	action, err := safety.ParseContainerAction(args[1])
+	if err != nil {
+		return err
+	}
+
+	auditor, err := newAuditor(cfg)
+	if err != nil {
+		return fmt.Errorf("creating auditor: %w", err)
+	}
+	defer auditor.Close()
+	auditHooks(procMgr, auditor)
+
+	t, err := procMgr.StartContainerAction(context.Background(), proc, action)
+	if err != nil {
+		auditor.LogBlocked(proc.PID, proc.Name, err.Error(), "")
+		return err
+	}
+	result, err := t.Wait()
+	if err != nil {
+		return fmt.Errorf("%s of %s (PID %d): %w", action, proc.Name, proc.PID, err)
+	}
+
+	powerCalc := power.NewCalculator(cfg.Power.CPUWattPerPercent, cfg.Power.MemoryWattPerMB, cfg.Power.DiskWattPerMBps)
+	id, _ := logTermination(auditor, powerCalc, result, fmt.Sprintf("container %s", action))
+	if cfg.Safety.DryRun {
+		fmt.Printf("Would %s %s (PID %d)%s%s\n", result.Verb(), proc.Name, proc.PID, result.Extent(), result.ActionVia())
+		return nil
+	}
+	hint := undoHint(result, id)
+	if err := saveRestore(procMgr, id, result); err != nil {
+		fmt.Printf("Warning: could not save restore record: %v\n", err)
+		hint = ""
+	}
+	fmt.Printf("%s %s (PID %d)%s, %s%s\n", terminated(result), proc.Name, proc.PID, result.Extent(), result.Outcome(), hint)
+	return nil
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/iamgilwell/aura/internal/ai"
+	"github.com/iamgilwell/aura/internal/config"
+	"github.com/iamgilwell/aura/internal/notification"
+)
+
+// newDecider wraps the remote engine with the local scorer and shadow
+// engine when they are configured. It returns a nil Decider if neither the
+// engine nor a local model is available. The returned function must be
+// called on shutdown to flush the shadow report.
+func newDecider(cfg *config.Config, engine *ai.Engine, notifier *notification.Notifier) (ai.Decider, func()) {
+	var decider ai.Decider
+	if engine != nil {
+		decider = engine
+	}
+
+	if cfg.AI.LocalModel != "" {
+		model, err := ai.LoadLocalModel(cfg.AI.LocalModel)
+		if err != nil {
+			notifier.Warn(fmt.Sprintf("Local model disabled: %v", err))
+		} else {
+			notifier.Info(fmt.Sprintf("Local model loaded (%d samples, %.0f%% training accuracy)",
+				model.Samples, model.Accuracy*100))
+			decider = ai.NewScreenedDecider(model, decider, cfg.AI.LocalKeepBelow, cfg.AI.LocalTerminateAbove)
+		}
+	}
+
+	if decider == nil || !cfg.AI.Shadow.Enabled {
+		return decider, func() {}
+	}
+
+	shadow, err := newShadowEngine(cfg)
+	if err != nil {
+		notifier.Warn(fmt.Sprintf("Shadow mode disabled: %v", err))
+		return decider, func() {}
+	}
+	report, err := ai.NewShadowReport(cfg.AI.Shadow.ReportFile)
+	if err != nil {
+		notifier.Warn(fmt.Sprintf("Shadow mode disabled: %v", err))
+		return decider, func() {}
+	}
+
+	notifier.Info(fmt.Sprintf("Shadow mode enabled, reporting to %s", cfg.AI.Shadow.ReportFile))
+	sd := ai.NewShadowDecider(decider, shadow, report, cfg.AI.Shadow.Timeout)
+	return sd, func() {
+		stats := report.Stats()
+		_ = sd.Close()
+		notifier.Info(fmt.Sprintf("Shadow mode: %d compared, %.0f%% agreement, %d errors%s",
+			stats.Total, stats.AgreementRate()*100, stats.Errors, formatPairs(stats.Pairs)))
+	}
+}
+
+// newShadowEngine builds the decider evaluated in shadow mode.
+func newShadowEngine(cfg *config.Config) (ai.Decider, error) {
+	sc := cfg.AI.Shadow
+
+	if sc.LocalModel != "" {
+		model, err := ai.LoadLocalModel(sc.LocalModel)
+		if err != nil {
+			return nil, err
+		}
+		return ai.NewScreenedDecider(model, nil, cfg.AI.LocalKeepBelow, cfg.AI.LocalTerminateAbove), nil
+	}
+
+	if cfg.Anthropic.APIKey == "" {
+		return nil, fmt.Errorf("shadow engine requires ANTHROPIC_API_KEY or shadow.local_model")
+	}
+
+	model := sc.Model
+	if model == "" {
+		model = cfg.Anthropic.Model
+	}
+	aggressiveness := sc.Aggressiveness
+	if aggressiveness == 0 {
+		aggressiveness = cfg.AI.Aggressiveness
+	}
+
+	// The shadow engine gets its own cache so it never sees primary decisions
+	cache := ai.NewCache(cfg.AI.CacheSize, cfg.AI.CacheTTL)
+	engine := ai.NewEngine(cfg.Anthropic.APIKey, model, cache, cfg.AI.ConfidenceThreshold, aggressiveness)
+
+	if sc.PromptFile != "" {
+		prompt, err := os.ReadFile(sc.PromptFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading shadow prompt: %w", err)
+		}
+		engine.SetSystemPrompt(string(prompt))
+	}
+	return engine, nil
+}
+
+func formatPairs(pairs map[string]int) string {
+	if len(pairs) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(pairs))
+	for k := range pairs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	out := " ("
+	for i, k := range keys {
+		if i > 0 {
+			out += ", "
+		}
+		out += fmt.Sprintf("%s: %d", k, pairs[k])
+	}
+	return out + ")"
+}
@@ -29,14 +29,20 @@ func runInteractive(cmd *cobra.Command, args []string) error {
 	}
 	defer notifier.Close()
 
-	auditor, err := notification.NewAuditor(cfg.Notifications.AuditFile)
+	auditor, err := newAuditor(cfg)
 	if err != nil {
 		return err
 	}
 	defer auditor.Close()
 
-	safetyMgr := safety.NewManager(cfg.Safety.ProtectedProcs, cfg.Safety.NeverTerminate, cfg.Safety.ConsentLevel)
-	procMgr := process.NewManager(safetyMgr, cfg.Safety.TerminateTimeout)
+	safetyMgr, err := newSafetyManager(cfg, cfg.Safety.ConsentLevel)
+	if err != nil {
+		return err
+	}
+	procMgr := newProcessManager(cfg, safetyMgr)
+	auditHooks(procMgr, auditor)
+	auditSchedule(safetyMgr, auditor)
+	tracker := process.NewOutcomeTracker(cfg.Safety.RespawnWindow, cfg.Safety.RespawnSuppress)
 
 	cache := ai.NewCache(cfg.AI.CacheSize, cfg.AI.CacheTTL)
 	var aiEngine *ai.Engine
@@ -44,15 +50,22 @@ func runInteractive(cmd *cobra.Command, args []string) error {
 		aiEngine = ai.NewEngine(cfg.Anthropic.APIKey, cfg.Anthropic.Model, cache, cfg.AI.ConfidenceThreshold, cfg.AI.Aggressiveness)
 	}
 
+	decider, closeDecider := newDecider(cfg, aiEngine, notifier)
+	defer closeDecider()
+
 	powerCalc := power.NewCalculator(cfg.Power.CPUWattPerPercent, cfg.Power.MemoryWattPerMB, cfg.Power.DiskWattPerMBps)
 	powerMetrics := power.NewMetrics()
+	powerMetrics.SetDryRun(cfg.Safety.DryRun)
 
 	mon := monitor.NewProcessMonitor(
 		cfg.Monitoring.ScanInterval,
 		cfg.Monitoring.HistorySize,
 		cfg.Safety.ProtectedProcs,
 	)
+	mon.SetPins(safetyMgr.Pins())
+	mon.SetContainerNames(procMgr.ContainerName)
 
-	app := ui.NewApp(cfg, mon, aiEngine, safetyMgr, procMgr, powerCalc, powerMetrics, notifier, auditor)
+	app := ui.NewApp(cfg, mon, aiEngine, decider, safetyMgr, procMgr, tracker, newPendingQueue(cfg), newSnoozeStore(cfg), powerCalc, powerMetrics, notifier, auditor,
+		func() (safety.Settings, bool) { return applySchedule(cfg, safetyMgr, aiEngine, auditor) })
 	return app.Run()
 }
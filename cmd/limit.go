@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/iamgilwell/aura/internal/config"
+	"github.com/iamgilwell/aura/internal/safety"
+)
+
+var limitFlags safety.Limits
+
+var limitCmd = &cobra.Command{
+	Use:   "limit [<pid>]",
+	Short: "Limit the resources of a process instead of terminating it",
+	Long: `Lowers the soft resource limits of a running process with prlimit and
+raises its oom_score_adj, so it runs on but cannot take the machine down:
+
+  --address-space-mb  RLIMIT_AS: allocations beyond it fail
+  --cpu-time          RLIMIT_CPU: CPU time it may still use before SIGXCPU
+  --open-files        RLIMIT_NOFILE: opening more files fails
+  --oom-score-adj     -1000 to 1000: higher makes the kernel OOM killer pick it first
+
+Without flags, applies the limits of the policy rule matching the process or
+safety.throttle_limits. Hard limits are left alone, and the original values
+are kept in safety.limits_dir until "aura unlimit" restores them. Without
+arguments, lists the processes Aura limited.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runLimit,
+}
+
+var unlimitCmd = &cobra.Command{
+	Use:   "unlimit <pid>",
+	Short: "Restore the resource limits a process had before Aura limited it",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runUnlimit,
+}
+
+func init() {
+	limitCmd.Flags().Float64Var(&limitFlags.AddressSpaceMB, "address-space-mb", 0, "address space limit in megabytes")
+	limitCmd.Flags().DurationVar(&limitFlags.CPUTime, "cpu-time", 0, "further CPU time allowed, e.g. 10m")
+	limitCmd.Flags().IntVar(&limitFlags.OpenFiles, "open-files", 0, "open file descriptor limit")
+	limitCmd.Flags().IntVar(&limitFlags.OOMScoreAdj, "oom-score-adj", 0, "OOM score adjustment, -1000 to 1000")
+}
+
+func runLimit(cmd *cobra.Command, args []string) error {
+	cfg := config.Global
+
+	safetyMgr, err := newSafetyManager(cfg, cfg.Safety.ConsentLevel)
+	if err != nil {
+		return err
+	}
+	procMgr := newProcessManager(cfg, safetyMgr)
+
+	if len(args) == 0 {
+		recs, err := procMgr.LimitStore().List()
+		if err != nil {
+			return err
+		}
+		if len(recs) == 0 {
+			fmt.Println("No processes are limited.")
+			return nil
+		}
+		fmt.Printf("%7s %-20s %-19s %-40s %s\n", "PID", "NAME", "LIMITED", "LIMITS", "ORIGINAL")
+		for _, r := range recs {
+			fmt.Printf("%7d %-20s %-19s %-40s %s\n",
+				r.PID, r.Name, r.LimitedAt.Format(time.DateTime), r.Applied, r.Originals())
+		}
+		return nil
+	}
+
+	pid, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid PID %q", args[0])
+	}
+	proc, err := scanProcess(cfg, safetyMgr, procMgr, pid)
+	if err != nil {
+		return err
+	}
+
+	limits := limitFlags
+	if limits.IsZero() {
+		if limits = procMgr.LimitsFor(proc); limits.IsZero() {
+			return fmt.Errorf("no limits for %s (PID %d): pass them as flags, or set safety.throttle_limits or the limits of a policy rule", proc.Name, proc.PID)
+		}
+	}
+
+	auditor, err := newAuditor(cfg)
+	if err != nil {
+		return fmt.Errorf("creating auditor: %w", err)
+	}
+	defer auditor.Close()
+	auditHooks(procMgr, auditor)
+
+	rec, err := procMgr.SafeLimit(proc, limits)
+	if rec == nil {
+		auditor.LogBlocked(proc.PID, proc.Name, err.Error(), "")
+		return err
+	}
+	auditor.LogAction(safety.ActionLimit.Past(), proc, "manual limit: "+limits.String())
+	if cfg.Safety.DryRun {
+		fmt.Printf("Would limit %s (PID %d): %s\n", proc.Name, proc.PID, limits)
+		return nil
+	}
+	fmt.Printf("Limited %s (PID %d): %s; revert with: aura unlimit %d\n", proc.Name, proc.PID, rec.Applied, proc.PID)
+	if err != nil {
+		return fmt.Errorf("some limits were not applied: %w", err)
+	}
+	return nil
+}
+
+func runUnlimit(cmd *cobra.Command, args []string) error {
+	cfg := config.Global
+
+	pid, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid PID %q", args[0])
+	}
+
+	safetyMgr, err := newSafetyManager(cfg, cfg.Safety.ConsentLevel)
+	if err != nil {
+		return err
+	}
+	procMgr := newProcessManager(cfg, safetyMgr)
+
+	auditor, err := newAuditor(cfg)
+	if err != nil {
+		return fmt.Errorf("creating auditor: %w", err)
+	}
+	defer auditor.Close()
+	auditHooks(procMgr, auditor)
+
+	rec, err := procMgr.RevertLimits(pid)
+	if rec == nil {
+		return err
+	}
+	if cfg.Safety.DryRun {
+		fmt.Printf("Would restore %s (PID %d) to %s\n", rec.Name, rec.PID, rec.Originals())
+		return nil
+	}
+	fmt.Printf("Restored %s (PID %d) to %s\n", rec.Name, rec.PID, rec.Originals())
+	if err != nil {
+		return fmt.Errorf("some values were not restored: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/iamgilwell/aura/internal/config"
+	"github.com/iamgilwell/aura/internal/monitor"
+	"github.com/iamgilwell/aura/internal/notification"
+	"github.com/iamgilwell/aura/internal/power"
+	"github.com/iamgilwell/aura/internal/process"
+	"github.com/iamgilwell/aura/internal/safety"
+)
+
+var oomVictims bool
+
+var oomCmd = &cobra.Command{
+	Use:   "oom",
+	Short: "Terminate processes before the kernel OOM killer has to",
+	Long: `Watches memory pressure (/proc/pressure/memory) and available memory
+every oom.interval. At the warn level the pressure is reported and audited;
+at the kill level the process with the highest oom_score, raised for memory
+still growing, is terminated with oom.escalation, skipping protected,
+snoozed and respawning processes and those needing consent. Terminations
+count against safety.budgets, and oom.cooldown passes between them. Under
+the default consent_level 2 only processes the consent matrix or a policy
+rule lets Aura terminate automatically are candidates.
+
+yolo runs the responder as well when oom.enabled is set. With --victims,
+shows the current pressure and the processes that would be terminated
+first, then exits.`,
+	RunE: runOOM,
+}
+
+func init() {
+	oomCmd.Flags().BoolVar(&oomVictims, "victims", false, "show the memory pressure and the first victims, then exit")
+}
+
+func runOOM(cmd *cobra.Command, args []string) error {
+	cfg := config.Global
+
+	safetyMgr, err := newSafetyManager(cfg, cfg.Safety.ConsentLevel)
+	if err != nil {
+		return err
+	}
+	procMgr := newProcessManager(cfg, safetyMgr)
+
+	mon := monitor.NewProcessMonitor(
+		cfg.Monitoring.ScanInterval,
+		cfg.Monitoring.HistorySize,
+		cfg.Safety.ProtectedProcs,
+	)
+	mon.SetPins(safetyMgr.Pins())
+	mon.SetContainerNames(procMgr.ContainerName)
+
+	responder, err := newOOMResponder(cfg, procMgr, mon.Processes)
+	if err != nil {
+		return err
+	}
+	tracker := process.NewOutcomeTracker(cfg.Safety.RespawnWindow, cfg.Safety.RespawnSuppress)
+	responder.SetSkip(oomSkip(tracker, newSnoozeStore(cfg)))
+
+	if oomVictims {
+		procMgr.UpdateProtection(mon.Snapshot(500 * time.Millisecond))
+		p, err := monitor.ReadMemoryPressure()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Pressure: %s (%s)\n", p, responder.LevelOf(p))
+		victims := responder.Victims()
+		if len(victims) == 0 {
+			fmt.Println("No process may be terminated.")
+			return nil
+		}
+		if len(victims) > 10 {
+			victims = victims[:10]
+		}
+		fmt.Printf("%7s %-20s %9s %10s %8s\n", "PID", "NAME", "OOM_SCORE", "MEMORY", "GROWTH")
+		for _, v := range victims {
+			fmt.Printf("%7d %-20s %9d %8.1fMB %+7.1f%%\n",
+				v.Proc.PID, v.Proc.Name, v.OOMScore, v.Proc.MemoryMB, v.Proc.MemoryTrend)
+		}
+		return nil
+	}
+
+	notifier, err := notification.NewNotifier(cfg.Notifications.LogFile, cfg.Notifications.ColorEnabled, cfg.Notifications.Verbose)
+	if err != nil {
+		return fmt.Errorf("creating notifier: %w", err)
+	}
+	defer notifier.Close()
+
+	auditor, err := newAuditor(cfg)
+	if err != nil {
+		return fmt.Errorf("creating auditor: %w", err)
+	}
+	defer auditor.Close()
+	auditHooks(procMgr, auditor)
+	if s := auditSchedule(safetyMgr, auditor); len(s.Windows) > 0 {
+		notifier.Info("Now " + s.String())
+	}
+	auditOOM(cfg, responder, safetyMgr, procMgr, tracker, auditor, notifier)
+
+	mon.OnUpdate(func(procs []*monitor.ProcessInfo, _ *monitor.SystemMetrics) {
+		if s, changed := applySchedule(cfg, safetyMgr, nil, auditor); changed {
+			notifier.Info("Now " + s.String())
+		}
+		procMgr.UpdateProtection(procs)
+		for _, ev := range tracker.Observe(procs) {
+			auditor.LogOutcome(ev.TerminationID, ev.Name, string(ev.Outcome), ev.NewPID, ev.After)
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigCh
+		notifier.Info("Shutting down the OOM responder...")
+		cancel()
+	}()
+
+	notifier.Info(fmt.Sprintf("Watching memory pressure every %s", cfg.OOM.Interval))
+	go mon.Start(ctx)
+	return responder.Run(ctx, cfg.OOM.Interval)
+}
+
+// newOOMResponder builds the early-OOM responder from the configuration,
+// choosing victims among the processes returned by procs.
+func newOOMResponder(cfg *config.Config, procMgr *process.Manager, procs func() []*monitor.ProcessInfo) (*process.OOMResponder, error) {
+	esc, err := safety.ParseEscalation(cfg.OOM.Escalation)
+	if err != nil {
+		return nil, fmt.Errorf("oom.escalation: %w", err)
+	}
+	if cfg.OOM.Interval <= 0 {
+		return nil, fmt.Errorf("oom.interval must be positive")
+	}
+	r := process.NewOOMResponder(procMgr, process.OOMThreshold(cfg.OOM.Warn), process.OOMThreshold(cfg.OOM.Kill), procs)
+	r.SetCooldown(cfg.OOM.Cooldown)
+	r.SetEscalation(esc)
+	return r, nil
+}
+
+// oomSkip leaves snoozed processes and programs that respawned after an
+// earlier termination to the kernel.
+func oomSkip(tracker *process.OutcomeTracker, snoozeStore *safety.SnoozeStore) func(*monitor.ProcessInfo) (bool, string) {
+	return func(proc *monitor.ProcessInfo) (bool, string) {
+		if suppressed, why := tracker.Suppressed(proc); suppressed {
+			return true, why
+		}
+		snoozes, _ := snoozeStore.List()
+		if s, ok := snoozes.Find(proc); ok {
+			return true, fmt.Sprintf("%s %s", s.Target(), s.Expiry())
+		}
+		return false, ""
+	}
+}
+
+// auditOOM records the pressure level changes and terminations of the
+// responder, tracking whether the terminated processes come back.
+func auditOOM(cfg *config.Config, responder *process.OOMResponder, safetyMgr *safety.Manager, procMgr *process.Manager,
+	tracker *process.OutcomeTracker, auditor *notification.Auditor, notifier *notification.Notifier) {
+	powerCalc := power.NewCalculator(cfg.Power.CPUWattPerPercent, cfg.Power.MemoryWattPerMB, cfg.Power.DiskWattPerMBps)
+	tripped := false
+
+	responder.OnEvent(func(ev process.OOMEvent) {
+		switch {
+		case ev.Victim == nil && ev.Err == nil:
+			msg := fmt.Sprintf("Memory pressure %s: %s", ev.Level, ev.Pressure)
+			auditor.LogEvent("memory_pressure", msg)
+			if ev.Level == process.OOMNormal {
+				notifier.Info(msg)
+			} else {
+				notifier.Warn(msg)
+			}
+			return
+		case ev.Victim == nil:
+			notifier.Error(fmt.Sprintf("Memory pressure %s: %v", ev.Level, ev.Err))
+			return
+		}
+
+		proc := ev.Victim.Proc
+		if ev.Err != nil {
+			notifier.Error(fmt.Sprintf("Failed to terminate %s (PID %d) under memory pressure: %v", proc.Name, proc.PID, ev.Err))
+			auditor.LogBlocked(proc.PID, proc.Name, ev.Err.Error(), "")
+			if reason := safetyMgr.Tripped(); reason != "" && !tripped {
+				tripped = true
+				notifier.Error(fmt.Sprintf("!!! TERMINATION BUDGET TRIPPED: %s — switching to monitor-only mode !!!", reason))
+				auditor.LogEvent("budget_tripped", reason)
+			}
+			return
+		}
+
+		reason := fmt.Sprintf("early OOM: %s, oom_score %d", ev.Pressure, ev.Victim.OOMScore)
+		notifier.Warn(fmt.Sprintf("Terminating: %s (PID %d) - %s", proc.Name, proc.PID, reason))
+		go func(t *process.Termination) {
+			result, err := t.Wait()
+			if !t.Acted() {
+				safetyMgr.RefundAutomatic(proc)
+			}
+			if err != nil {
+				notifier.Error(fmt.Sprintf("Failed to terminate PID %d: %v", proc.PID, err))
+				return
+			}
+			id, _ := logTermination(auditor, powerCalc, result, reason)
+			if cfg.Safety.DryRun {
+				notifier.Warn(fmt.Sprintf("Would terminate: %s (PID %d)%s - %s", proc.Name, proc.PID, result.Extent(), reason))
+				return
+			}
+			tracker.Track(id, proc)
+			hint := undoHint(result, id)
+			if err := saveRestore(procMgr, id, result); err != nil {
+				notifier.Warn(fmt.Sprintf("Could not save restore record for PID %d: %v", proc.PID, err))
+				hint = ""
+			}
+			notifier.Info(fmt.Sprintf("Terminated PID %d%s (%s)%s", proc.PID, result.Extent(), result.Outcome(), hint))
+		}(ev.Termination)
+	})
+}
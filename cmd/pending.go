@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/iamgilwell/aura/internal/config"
+	"github.com/iamgilwell/aura/internal/monitor"
+	"github.com/iamgilwell/aura/internal/notification"
+	"github.com/iamgilwell/aura/internal/power"
+	"github.com/iamgilwell/aura/internal/safety"
+)
+
+var pendingCmd = &cobra.Command{
+	Use:   "pending",
+	Short: "List actions waiting for approval",
+	Long: `Lists terminations and throttles that require consent and were parked by
+yolo mode or the TUI. Approve them with "aura approve <id>" or reject them with
+"aura deny <id>"; unanswered requests expire after safety.pending_ttl.`,
+	RunE: runPending,
+}
+
+var approveCmd = &cobra.Command{
+	Use:   "approve <id>",
+	Short: "Approve a pending action",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runApprove,
+}
+
+var denyCmd = &cobra.Command{
+	Use:   "deny <id>",
+	Short: "Reject a pending action",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runDeny,
+}
+
+func runPending(cmd *cobra.Command, args []string) error {
+	cfg := config.Global
+
+	auditor, err := newAuditor(cfg)
+	if err != nil {
+		return fmt.Errorf("creating auditor: %w", err)
+	}
+	defer auditor.Close()
+
+	queue := newPendingQueue(cfg)
+	notification.ExpirePending(queue, auditor)
+	items, err := queue.List()
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		fmt.Println("No actions are waiting for approval.")
+		return nil
+	}
+
+	fmt.Printf("%-8s %-9s %7s %-20s %-10s %-11s %-9s %s\n", "ID", "ACTION", "PID", "NAME", "USER", "SOURCE", "EXPIRES", "REASON")
+	for _, it := range items {
+		fmt.Printf("%-8s %-9s %7d %-20s %-10s %-11s %-9s %s\n",
+			it.ID, it.Intervention(), it.PID, it.Name, it.User, it.Source,
+			time.Until(it.ExpiresAt).Truncate(time.Minute), it.Reason)
+	}
+	return nil
+}
+
+func runApprove(cmd *cobra.Command, args []string) error {
+	cfg := config.Global
+
+	auditor, err := newAuditor(cfg)
+	if err != nil {
+		return fmt.Errorf("creating auditor: %w", err)
+	}
+	defer auditor.Close()
+
+	queue := newPendingQueue(cfg)
+	item, err := takePending(queue, args[0], auditor)
+	if err != nil {
+		return err
+	}
+	auditor.LogApproval("approved", item.ID, item.PID, item.Name, item.Reason)
+
+	safetyMgr, err := newSafetyManager(cfg, cfg.Safety.ConsentLevel)
+	if err != nil {
+		return requeuePending(queue, item, err)
+	}
+	procMgr := newProcessManager(cfg, safetyMgr)
+	auditHooks(procMgr, auditor)
+
+	mon := monitor.NewProcessMonitor(
+		cfg.Monitoring.ScanInterval,
+		cfg.Monitoring.HistorySize,
+		cfg.Safety.ProtectedProcs,
+	)
+	mon.SetPins(safetyMgr.Pins())
+	mon.SetContainerNames(procMgr.ContainerName)
+	procs := mon.Snapshot(500 * time.Millisecond)
+	procMgr.UpdateProtection(procs)
+
+	var proc *monitor.ProcessInfo
+	for _, p := range procs {
+		if item.Matches(p) {
+			proc = p
+			break
+		}
+	}
+	if proc == nil {
+		return fmt.Errorf("%s (PID %d) is no longer running", item.Name, item.PID)
+	}
+
+	action := item.Intervention()
+	if action != safety.ActionTerminate {
+		if err := procMgr.SafeApply(proc, action); err != nil {
+			auditor.LogBlocked(proc.PID, proc.Name, err.Error(), "")
+			return requeuePending(queue, item, fmt.Errorf("%s of %s (PID %d): %w", action, proc.Name, proc.PID, err))
+		}
+		auditor.LogAction(action.Past(), proc, "approved: "+item.Reason)
+		if cfg.Safety.DryRun {
+			fmt.Printf("Would apply %s to %s (PID %d)\n", action, proc.Name, proc.PID)
+		} else {
+			fmt.Printf("Applied %s to %s (PID %d)\n", action, proc.Name, proc.PID)
+		}
+		return nil
+	}
+
+	result, err := procMgr.SafeTerminate(proc, false)
+	if err != nil {
+		auditor.LogBlocked(proc.PID, proc.Name, err.Error(), "")
+		return requeuePending(queue, item, fmt.Errorf("%s of %s (PID %d): %w", action, proc.Name, proc.PID, err))
+	}
+	powerCalc := power.NewCalculator(cfg.Power.CPUWattPerPercent, cfg.Power.MemoryWattPerMB, cfg.Power.DiskWattPerMBps)
+	id, _ := logTermination(auditor, powerCalc, result, "approved: "+item.Reason)
+	if cfg.Safety.DryRun {
+		fmt.Printf("Would terminate %s (PID %d)%s\n", proc.Name, proc.PID, result.Extent())
+		return nil
+	}
+	hint := undoHint(result, id)
+	if err := saveRestore(procMgr, id, result); err != nil {
+		fmt.Printf("Warning: could not save restore record: %v\n", err)
+		hint = ""
+	}
+	fmt.Printf("%s %s (PID %d)%s, %s%s\n", terminated(result), proc.Name, proc.PID, result.Extent(), result.Outcome(), hint)
+	return nil
+}
+
+func runDeny(cmd *cobra.Command, args []string) error {
+	cfg := config.Global
+
+	auditor, err := newAuditor(cfg)
+	if err != nil {
+		return fmt.Errorf("creating auditor: %w", err)
+	}
+	defer auditor.Close()
+
+	item, err := takePending(newPendingQueue(cfg), args[0], auditor)
+	if err != nil {
+		return err
+	}
+	auditor.LogApproval("denied", item.ID, item.PID, item.Name, item.Reason)
+	fmt.Printf("Denied %s of %s (PID %d)\n", item.Intervention(), item.Name, item.PID)
+	return nil
+}
+
+// takePending removes an item from the queue, auditing it if it expired.
+func takePending(queue *safety.PendingQueue, id string, auditor *notification.Auditor) (safety.PendingItem, error) {
+	item, err := queue.Take(id)
+	if errors.Is(err, safety.ErrPendingExpired) {
+		auditor.LogApproval("approval_expired", item.ID, item.PID, item.Name, item.Reason)
+		return item, fmt.Errorf("approval %s expired at %s", item.ID, item.ExpiresAt.Format("15:04:05"))
+	}
+	return item, err
+}
+
+// requeuePending puts an approved item whose action failed back in the
+// queue, so the approval is not lost, and returns err saying so.
+func requeuePending(queue *safety.PendingQueue, item safety.PendingItem, err error) error {
+	if qerr := queue.Requeue(item); qerr != nil {
+		return fmt.Errorf("%w (could not keep %s pending: %v)", err, item.ID, qerr)
+	}
+	return fmt.Errorf("%w; %s is still pending", err, item.ID)
+}
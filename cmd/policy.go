@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/iamgilwell/aura/internal/config"
+	"github.com/iamgilwell/aura/internal/monitor"
+	"github.com/iamgilwell/aura/internal/safety"
+)
+
+var policyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Inspect the safety policy",
+}
+
+var policyTestCmd = &cobra.Command{
+	Use:   "test <pid>",
+	Short: "Explain the safety verdict for a running process",
+	Long: `Evaluates a running process against the built-in protections, the policy
+rules from safety.policy_file and the protected process list, and shows which
+rule decided and why earlier rules did not match.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPolicyTest,
+}
+
+func init() {
+	policyCmd.AddCommand(policyTestCmd)
+}
+
+func runPolicyTest(cmd *cobra.Command, args []string) error {
+	cfg := config.Global
+
+	pid, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid PID %q", args[0])
+	}
+
+	safetyMgr, err := newSafetyManager(cfg, cfg.Safety.ConsentLevel)
+	if err != nil {
+		return err
+	}
+	schedule := safetyMgr.Settings()
+
+	procMgr := newProcessManager(cfg, safetyMgr)
+	mon := monitor.NewProcessMonitor(
+		cfg.Monitoring.ScanInterval,
+		cfg.Monitoring.HistorySize,
+		cfg.Safety.ProtectedProcs,
+	)
+	mon.SetPins(safetyMgr.Pins())
+	mon.SetContainerNames(procMgr.ContainerName)
+	procs := mon.Snapshot(500 * time.Millisecond)
+	procMgr.UpdateProtection(procs)
+
+	var proc *monitor.ProcessInfo
+	for _, p := range procs {
+		if p.PID == pid {
+			proc = p
+			break
+		}
+	}
+	if proc == nil {
+		return fmt.Errorf("no process with PID %d", pid)
+	}
+
+	fmt.Printf("Process:  %s (PID %d)\n", proc.Name, proc.PID)
+	fmt.Printf("  Exe:      %s\n", proc.Exe)
+	fmt.Printf("  Cmdline:  %s\n", proc.Cmdline)
+	fmt.Printf("  User:     %s (UID %d)\n", proc.User, proc.UID)
+	fmt.Printf("  Category: %s\n", proc.Category)
+	fmt.Printf("  Cgroup:   %s\n", proc.Cgroup)
+	fmt.Printf("  Unit:     %s\n", proc.Unit)
+	if proc.Container != "" {
+		fmt.Printf("  Container: %s (%s)\n", proc.ContainerLabel(), proc.Container)
+	}
+	fmt.Printf("  Session:  %d (TTY %d)\n", proc.Session, proc.TTY)
+	fmt.Printf("  Age:      %s\n", time.Since(proc.StartTime).Truncate(time.Second))
+	fmt.Printf("  CPU:      %.1f%%  Memory: %.1f%% (%.1f MB)\n", proc.CPU, proc.Memory, proc.MemoryMB)
+	if len(proc.Listeners) > 0 || len(proc.Connections) > 0 {
+		listeners := make([]string, len(proc.Listeners))
+		for i, s := range proc.Listeners {
+			listeners[i] = s.String()
+		}
+		fmt.Printf("  Sockets:  listening [%s], %d established, %d unix connected\n", strings.Join(listeners, " "),
+			proc.NetConnections(), len(proc.Connections)-proc.NetConnections())
+	}
+	switch status, info := safetyMgr.Pins().Verify(proc); status {
+	case monitor.PinVerified:
+		fmt.Printf("  Pin:      verified (%s)\n", info)
+	case monitor.PinMismatch:
+		fmt.Printf("  Pin:      SUSPICIOUS — %s\n", info)
+	case monitor.PinUnverifiable:
+		fmt.Printf("  Pin:      unverifiable — %s\n", info)
+	}
+	fmt.Println()
+
+	if policy := safetyMgr.Policy(); policy != nil {
+		fmt.Println("Policy rules:")
+		for _, t := range policy.Trace(proc) {
+			if t.Matched {
+				fmt.Printf("  ✓ %-24s %s\n", t.Rule.Name, t.Rule.Effect)
+			} else {
+				fmt.Printf("  ✗ %-24s %s does not match\n", t.Rule.Name, t.Mismatch)
+			}
+		}
+		fmt.Println()
+	} else {
+		fmt.Println("No policy file configured (safety.policy_file).")
+		fmt.Println()
+	}
+
+	verdict := safetyMgr.Evaluate(proc)
+	switch {
+	case !verdict.Allowed:
+		fmt.Printf("Verdict:  BLOCKED — %s\n", verdict.Reason)
+	case safetyMgr.NeedsConfirmation(proc):
+		fmt.Printf("Verdict:  ALLOWED WITH CONFIRMATION — %s\n", verdict.Reason)
+	default:
+		fmt.Printf("Verdict:  ALLOWED — %s\n", verdict.Reason)
+	}
+
+	if risk, ok := safetyMgr.DataRisk(proc); ok && risk.Possible() {
+		fmt.Printf("Data:     %s\n", risk)
+	}
+	if len(verdict.Hooks) > 0 {
+		fmt.Printf("Hooks:    %s\n", strings.Join(verdict.Hooks, ", "))
+	}
+	if verdict.Escalation != nil {
+		fmt.Printf("Signals:  %s\n", verdict.Escalation)
+	}
+	if scope := procMgr.ScopeOf(proc); scope != safety.ScopeProcess {
+		if members, err := procMgr.Members(proc, scope); err != nil {
+			fmt.Printf("Scope:    %s — %v\n", scope, err)
+		} else {
+			fmt.Printf("Scope:    %s, %d other processes go with it\n", scope, len(members)-1)
+		}
+	}
+	container := safety.ContainerSignal
+	if proc.Container != "" {
+		action, err := procMgr.ContainerPlan(proc)
+		switch {
+		case err != nil:
+			fmt.Printf("Container: %s — %v\n", proc.ContainerLabel(), err)
+		case action == safety.ContainerSignal:
+			fmt.Printf("Container: %s, signal the process\n", proc.ContainerLabel())
+		default:
+			fmt.Printf("Container: %s, %s the container\n", proc.ContainerLabel(), action)
+		}
+		container = action
+	}
+	if proc.Unit != "" && container == safety.ContainerSignal {
+		action, unit, err := procMgr.UnitPlan(proc)
+		switch {
+		case err != nil:
+			fmt.Printf("Unit:     %s — %v\n", proc.Unit, err)
+		case unit.Restart != "":
+			fmt.Printf("Unit:     %s (Restart=%s), %s\n", proc.Unit, unit.Restart, unitPlanText(action, unit))
+		default:
+			fmt.Printf("Unit:     %s, %s\n", proc.Unit, unitPlanText(action, unit))
+		}
+	}
+	if limits := procMgr.LimitsFor(proc); !limits.IsZero() {
+		fmt.Printf("Limits:   %s\n", limits)
+	}
+	if len(schedule.Windows) > 0 {
+		fmt.Printf("Schedule: %s\n", strings.Join(schedule.Windows, ", "))
+	}
+	fmt.Printf("Consent (level %d):", safetyMgr.ConsentLevel())
+	for _, action := range safety.Actions {
+		fmt.Printf(" %s=%s", action, safetyMgr.Consent(proc, action))
+	}
+	fmt.Println()
+	return nil
+}
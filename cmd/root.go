@@ -12,6 +12,7 @@ import (
 var (
 	cfgFile string
 	verbose bool
+	dryRun  bool
 )
 
 var rootCmd = &cobra.Command{
@@ -35,6 +36,7 @@ func init() {
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default: ./config.yaml)")
 	rootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "enable verbose output")
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "evaluate and audit actions without touching any process")
 
 	rootCmd.AddCommand(monitorCmd)
 	rootCmd.AddCommand(yoloCmd)
@@ -43,6 +45,21 @@ func init() {
 	rootCmd.AddCommand(startCmd)
 	rootCmd.AddCommand(stopCmd)
 	rootCmd.AddCommand(logsCmd)
+	rootCmd.AddCommand(askCmd)
+	rootCmd.AddCommand(summaryCmd)
+	rootCmd.AddCommand(trainCmd)
+	rootCmd.AddCommand(policyCmd)
+	rootCmd.AddCommand(pendingCmd)
+	rootCmd.AddCommand(approveCmd)
+	rootCmd.AddCommand(denyCmd)
+	rootCmd.AddCommand(undoCmd)
+	rootCmd.AddCommand(snoozeCmd)
+	rootCmd.AddCommand(unsnoozeCmd)
+	rootCmd.AddCommand(unitCmd)
+	rootCmd.AddCommand(containerCmd)
+	rootCmd.AddCommand(limitCmd)
+	rootCmd.AddCommand(unlimitCmd)
+	rootCmd.AddCommand(oomCmd)
 }
 
 func initConfig() {
@@ -54,5 +71,8 @@ func initConfig() {
 	if verbose {
 		cfg.Notifications.Verbose = true
 	}
+	if dryRun {
+		cfg.Safety.DryRun = true
+	}
 	config.Global = cfg
 }
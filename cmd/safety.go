@@ -0,0 +1,315 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/iamgilwell/aura/internal/ai"
+	"github.com/iamgilwell/aura/internal/config"
+	"github.com/iamgilwell/aura/internal/monitor"
+	"github.com/iamgilwell/aura/internal/notification"
+	"github.com/iamgilwell/aura/internal/power"
+	"github.com/iamgilwell/aura/internal/process"
+	"github.com/iamgilwell/aura/internal/safety"
+)
+
+// newSafetyManager builds the safety manager from the configuration,
+// loading the consent matrix, the data guard, the budgets, the schedules,
+// and the policy file and executable pins if set, and checking the
+// termination scope, the escalation ladder, the unit and container actions,
+// the throttle limits, and the termination hooks and schedules the policy
+// refers to. The pins should also be passed to the process monitor with
+// SetPins.
+//
+// The schedule windows active now are applied before it returns, so every
+// command runs at their consent level; long-running loops re-apply them on
+// each scan.
+func newSafetyManager(cfg *config.Config, consentLevel int) (*safety.Manager, error) {
+	mgr := safety.NewManager(cfg.Safety.ProtectedProcs, cfg.Safety.NeverTerminate, consentLevel)
+	if len(cfg.Safety.ConsentMatrix) > 0 {
+		matrix, err := safety.ParseMatrix(cfg.Safety.ConsentMatrix)
+		if err != nil {
+			return nil, err
+		}
+		mgr.SetConsentMatrix(matrix)
+	}
+	if cfg.Safety.DataGuard.Enabled {
+		mgr.SetDataGuard(&safety.DataGuard{WriteRate: cfg.Safety.DataGuard.WriteRateKB * 1024})
+	}
+	mgr.SetBudget(safety.Budget{
+		MaxPerMinute: cfg.Safety.Budgets.MaxPerMinute,
+		MaxPerHour:   cfg.Safety.Budgets.MaxPerHour,
+		MaxPerUser:   cfg.Safety.Budgets.MaxPerUser,
+		MaxMemoryMB:  cfg.Safety.Budgets.MaxMemoryMB,
+		MemoryWindow: cfg.Safety.Budgets.MemoryWindow,
+		MaxExeShare:  cfg.Safety.Budgets.MaxExeShare,
+	})
+
+	if len(cfg.Safety.Pins) > 0 {
+		pins := make([]monitor.Pin, 0, len(cfg.Safety.Pins))
+		for _, p := range cfg.Safety.Pins {
+			if p.Name == "" && p.Exe == "" {
+				return nil, fmt.Errorf("safety pin needs a name or exe")
+			}
+			pins = append(pins, monitor.Pin{Name: p.Name, Exe: p.Exe, SHA256: p.SHA256})
+		}
+		mgr.SetPins(monitor.NewPinSet(pins))
+	}
+
+	schedule, err := newSchedule(cfg)
+	if err != nil {
+		return nil, err
+	}
+	mgr.SetSchedule(schedule)
+
+	if _, err := safety.ParseScope(cfg.Safety.TerminationScope); err != nil {
+		return nil, fmt.Errorf("safety.termination_scope: %w", err)
+	}
+	if _, err := safety.ParseEscalation(cfg.Safety.Escalation); err != nil {
+		return nil, fmt.Errorf("safety.escalation: %w", err)
+	}
+	if _, err := safety.ParseUnitAction(cfg.Safety.UnitAction); err != nil {
+		return nil, fmt.Errorf("safety.unit_action: %w", err)
+	}
+	if action, err := safety.ParseContainerAction(cfg.Safety.ContainerAction); err != nil {
+		return nil, fmt.Errorf("safety.container_action: %w", err)
+	} else if action != safety.ContainerSignal && cfg.Safety.ContainerSocket == "" {
+		return nil, fmt.Errorf("safety.container_action %q needs safety.container_socket", action)
+	}
+	if err := safety.Limits(cfg.Safety.ThrottleLimits).Validate(); err != nil {
+		return nil, fmt.Errorf("safety.throttle_limits: %w", err)
+	}
+
+	hooks := make(map[string]bool, len(cfg.Safety.Hooks))
+	for _, h := range terminationHooks(cfg) {
+		if err := h.Validate(); err != nil {
+			return nil, err
+		}
+		hooks[h.Name] = true
+	}
+
+	if cfg.Safety.PolicyFile != "" {
+		policy, err := safety.LoadPolicy(cfg.Safety.PolicyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading safety policy: %w", err)
+		}
+		for _, rule := range policy.Rules {
+			for _, name := range rule.Hooks {
+				if !hooks[name] {
+					return nil, fmt.Errorf("rule %q: unknown hook %q", rule.Name, name)
+				}
+			}
+			if rule.Schedule != "" && !schedule.Has(rule.Schedule) {
+				return nil, fmt.Errorf("rule %q: unknown schedule %q", rule.Name, rule.Schedule)
+			}
+		}
+		mgr.SetPolicy(policy)
+	}
+	mgr.ApplySchedule(time.Now(), cfg.AI.Aggressiveness)
+	return mgr, nil
+}
+
+// newSchedule converts the configured maintenance windows. It returns nil
+// if none are configured.
+func newSchedule(cfg *config.Config) (*safety.Schedule, error) {
+	if len(cfg.Safety.Schedules) == 0 {
+		return nil, nil
+	}
+	windows := make([]safety.Window, 0, len(cfg.Safety.Schedules))
+	for _, sc := range cfg.Safety.Schedules {
+		cron, err := safety.ParseCron(sc.Cron, sc.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("schedule %q: %w", sc.Name, err)
+		}
+		windows = append(windows, safety.Window{
+			Name:           sc.Name,
+			Cron:           cron,
+			ConsentLevel:   sc.ConsentLevel,
+			Aggressiveness: sc.Aggressiveness,
+		})
+	}
+	return safety.NewSchedule(windows)
+}
+
+// applySchedule re-applies the schedule windows active now to safetyMgr and
+// the aggressiveness of engine, which may be nil; the configured
+// aggressiveness applies outside any window. It returns the settings in
+// effect and whether the active windows changed, in which case the change
+// is audited.
+func applySchedule(cfg *config.Config, safetyMgr *safety.Manager, engine *ai.Engine, auditor *notification.Auditor) (safety.Settings, bool) {
+	s, changed := safetyMgr.ApplySchedule(time.Now(), cfg.AI.Aggressiveness)
+	if engine != nil {
+		engine.SetAggressiveness(s.Aggressiveness)
+	}
+	if changed {
+		auditor.LogEvent("schedule", s.String())
+	}
+	return s, changed
+}
+
+// auditSchedule audits the windows newSafetyManager found active, which
+// applySchedule only reports once they change. It returns the settings in
+// effect.
+func auditSchedule(safetyMgr *safety.Manager, auditor *notification.Auditor) safety.Settings {
+	s := safetyMgr.Settings()
+	if len(s.Windows) > 0 {
+		auditor.LogEvent("schedule", s.String())
+	}
+	return s
+}
+
+// terminationHooks converts the configured hooks.
+func terminationHooks(cfg *config.Config) []process.Hook {
+	hooks := make([]process.Hook, 0, len(cfg.Safety.Hooks))
+	for _, h := range cfg.Safety.Hooks {
+		hooks = append(hooks, process.Hook{
+			Name:    h.Name,
+			Stage:   process.HookStage(h.Stage),
+			Exec:    h.Exec,
+			URL:     h.URL,
+			Method:  h.Method,
+			Timeout: h.Timeout,
+			Veto:    h.Veto,
+		})
+	}
+	return hooks
+}
+
+// newProcessManager builds the process manager with tree protection,
+// restore and limit records, the termination scope, the escalation ladder,
+// the unit and container actions, the throttle limits and dry-run mode
+// configured. Call UpdateProtection after every scan, and SaveRestore after
+// logging each termination; its ContainerName suits the process monitor's
+// SetContainerNames.
+func newProcessManager(cfg *config.Config, safetyMgr *safety.Manager) *process.Manager {
+	mgr := process.NewManager(safetyMgr, cfg.Safety.TerminateTimeout)
+	mgr.ProtectTrees(cfg.Safety.ProtectDescendants, cfg.Safety.ProtectOwnSession)
+	if cfg.Safety.RestoreDir != "" {
+		mgr.SetRestoreStore(process.NewRestoreStore(cfg.Safety.RestoreDir, cfg.Safety.RestoreRetention))
+	}
+	mgr.SetHooks(terminationHooks(cfg))
+	mgr.SetDryRun(cfg.Safety.DryRun)
+	mgr.SetScope(safety.Scope(cfg.Safety.TerminationScope))
+	esc, _ := safety.ParseEscalation(cfg.Safety.Escalation) // checked by newSafetyManager
+	mgr.SetEscalation(esc)
+	mgr.SetUnitAction(safety.UnitAction(cfg.Safety.UnitAction))
+	if cfg.Safety.ContainerSocket != "" {
+		mgr.SetContainerClient(process.NewContainerClient(cfg.Safety.ContainerSocket))
+	}
+	mgr.SetContainerAction(safety.ContainerAction(cfg.Safety.ContainerAction))
+	mgr.SetLimitStore(process.NewLimitStore(cfg.Safety.LimitsDir))
+	mgr.SetThrottleLimits(safety.Limits(cfg.Safety.ThrottleLimits))
+	return mgr
+}
+
+// scanProcess scans the running processes once, updating the protection
+// of procMgr, and returns the one with the given PID.
+func scanProcess(cfg *config.Config, safetyMgr *safety.Manager, procMgr *process.Manager, pid int) (*monitor.ProcessInfo, error) {
+	mon := monitor.NewProcessMonitor(
+		cfg.Monitoring.ScanInterval,
+		cfg.Monitoring.HistorySize,
+		cfg.Safety.ProtectedProcs,
+	)
+	mon.SetPins(safetyMgr.Pins())
+	mon.SetContainerNames(procMgr.ContainerName)
+	procs := mon.Snapshot(0)
+	procMgr.UpdateProtection(procs)
+	for _, p := range procs {
+		if p.PID == pid {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("no process with PID %d", pid)
+}
+
+// logTermination audits a termination as one entry covering every member
+// of its scope and returns the entry ID and the estimated savings. A paused
+// container is audited as container_paused and saves nothing: its processes
+// keep their memory.
+func logTermination(auditor *notification.Auditor, powerCalc *power.Calculator, result *process.TerminationResult, reason string) (string, float64) {
+	if result.Paused() {
+		return auditor.LogContainerPause(result.Target(), notification.TerminationDetailsOf(result), reason), 0
+	}
+	savings := powerCalc.EstimateGroupSavings(result.Members)
+	id := auditor.LogScopedTermination(result.Target(), notification.TerminationDetailsOf(result), reason, savings)
+	return id, savings
+}
+
+// saveRestore stores the restore record of a termination for "aura undo".
+// A paused container has nothing to relaunch.
+func saveRestore(procMgr *process.Manager, id string, result *process.TerminationResult) error {
+	if result.Paused() {
+		return nil
+	}
+	return procMgr.SaveRestore(id, result.Target())
+}
+
+// terminated returns the verb reporting result: "Paused" for a paused
+// container, else "Terminated".
+func terminated(result *process.TerminationResult) string {
+	if result.Paused() {
+		return "Paused"
+	}
+	return "Terminated"
+}
+
+// undoHint tells how to reverse a termination with audit ID id.
+func undoHint(result *process.TerminationResult, id string) string {
+	switch {
+	case result.UnitAction == safety.UnitStop && result.Unit.User:
+		return "; start it again with: systemctl --user start " + result.Unit.Name
+	case result.UnitAction == safety.UnitStop:
+		return "; start it again with: systemctl start " + result.Unit.Name
+	case result.UnitAction != "":
+		return ""
+	case result.ContainerAction == safety.ContainerStop:
+		return "; start it again with: docker start " + result.Target().ContainerLabel()
+	case result.ContainerAction == safety.ContainerPause:
+		return "; resume it with: docker unpause " + result.Target().ContainerLabel()
+	}
+	return "; undo with: aura undo " + id
+}
+
+// newAuditor opens the audit trail, recording simulated actions under their
+// would_* names in dry-run mode.
+func newAuditor(cfg *config.Config) (*notification.Auditor, error) {
+	auditor, err := notification.NewAuditor(cfg.Notifications.AuditFile)
+	if err != nil {
+		return nil, err
+	}
+	auditor.SetDryRun(cfg.Safety.DryRun)
+	return auditor, nil
+}
+
+// auditHooks records the result of every termination hook and every
+// change of resource limits.
+func auditHooks(procMgr *process.Manager, auditor *notification.Auditor) {
+	procMgr.OnHookResult(func(r process.HookResult) {
+		details := fmt.Sprintf("duration=%s", r.Duration.Truncate(time.Millisecond))
+		if r.Err != nil {
+			details += fmt.Sprintf(" error=%q", r.Err.Error())
+		}
+		if r.Output != "" {
+			details += fmt.Sprintf(" output=%q", r.Output)
+		}
+		auditor.LogHook(string(r.Stage), r.Hook, r.PID, r.Name, r.Outcome(), details)
+	})
+	procMgr.OnLimits(func(c process.LimitChange) {
+		rec := c.Record
+		details := fmt.Sprintf("applied=%q original=%q", rec.Applied.String(), rec.Originals())
+		if c.Err != nil {
+			details += fmt.Sprintf(" error=%q", c.Err.Error())
+		}
+		event := "limits_applied"
+		if c.Reverted {
+			event = "limits_reverted"
+		}
+		auditor.LogLimits(event, rec.PID, rec.Name, details)
+	})
+}
+
+// newPendingQueue opens the approval queue shared by yolo, the TUI and the
+// approve/deny commands.
+func newPendingQueue(cfg *config.Config) *safety.PendingQueue {
+	return safety.NewPendingQueue(cfg.Safety.PendingFile, cfg.Safety.PendingTTL)
+}
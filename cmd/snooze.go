@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/iamgilwell/aura/internal/ai"
+	"github.com/iamgilwell/aura/internal/config"
+	"github.com/iamgilwell/aura/internal/monitor"
+	"github.com/iamgilwell/aura/internal/safety"
+)
+
+var snoozeReason string
+
+var snoozeCmd = &cobra.Command{
+	Use:   "snooze [<pid|pattern> [duration|keep]]",
+	Short: "Keep or snooze processes so Aura leaves them alone",
+	Long: `Tells Aura to leave processes alone: yolo mode and the TUI skip them before
+asking the AI. A PID snoozes the identity of that process (its executable and
+command line), so restarts stay covered; anything else is a glob on the
+process name or executable path, e.g. "node*" or "/opt/app/*".
+
+The duration (e.g. 30m, 4h, 7d) defaults to "keep", which never expires.
+Snoozes are stored in safety.snooze_file and survive restarts. Without
+arguments, lists the active snoozes; remove one with "aura unsnooze".`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: runSnooze,
+}
+
+var unsnoozeCmd = &cobra.Command{
+	Use:   "unsnooze <id|pid|pattern>",
+	Short: "Let Aura evaluate snoozed processes again",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runUnsnooze,
+}
+
+func init() {
+	snoozeCmd.Flags().StringVar(&snoozeReason, "reason", "", "why the processes are fine, recorded in the audit log")
+}
+
+func runSnooze(cmd *cobra.Command, args []string) error {
+	cfg := config.Global
+	store := newSnoozeStore(cfg)
+
+	if len(args) == 0 {
+		snoozes, err := store.List()
+		if err != nil {
+			return err
+		}
+		if len(snoozes) == 0 {
+			fmt.Println("No processes are kept or snoozed.")
+			return nil
+		}
+		fmt.Printf("%-8s %-24s %-30s %-11s %s\n", "ID", "TARGET", "STATUS", "SOURCE", "REASON")
+		for _, s := range snoozes {
+			fmt.Printf("%-8s %-24s %-30s %-11s %s\n", s.ID, s.Target(), s.Expiry(), s.Source, s.Reason)
+		}
+		return nil
+	}
+
+	var d time.Duration
+	if len(args) == 2 && args[1] != "keep" {
+		var err error
+		if d, err = ai.ParseDuration(args[1]); err != nil || d <= 0 {
+			return fmt.Errorf("invalid duration %q: use e.g. 30m, 4h, 7d or keep", args[1])
+		}
+	}
+
+	var s safety.Snooze
+	if pid, err := strconv.Atoi(args[0]); err == nil {
+		proc, err := findProcess(cfg, pid)
+		if err != nil {
+			return err
+		}
+		s = safety.NewSnooze(proc, d)
+	} else if s, err = safety.NewPatternSnooze(args[0], d); err != nil {
+		return err
+	}
+	s.Reason = snoozeReason
+	s.Source = "cli"
+
+	s, err := store.Add(s)
+	if err != nil {
+		return err
+	}
+
+	auditor, err := newAuditor(cfg)
+	if err != nil {
+		return fmt.Errorf("creating auditor: %w", err)
+	}
+	defer auditor.Close()
+	auditor.LogEvent("snooze", fmt.Sprintf("id=%s target=%q %s, reason=%q", s.ID, s.Target(), s.Expiry(), s.Reason))
+
+	fmt.Printf("%s %s; undo with: aura unsnooze %s\n", s.Target(), s.Expiry(), s.ID)
+	return nil
+}
+
+func runUnsnooze(cmd *cobra.Command, args []string) error {
+	cfg := config.Global
+	store := newSnoozeStore(cfg)
+
+	var removed safety.Snoozes
+	var err error
+	if pid, convErr := strconv.Atoi(args[0]); convErr == nil {
+		proc, err := findProcess(cfg, pid)
+		if err != nil {
+			return err
+		}
+		if removed, err = store.RemoveProcess(proc); err == nil && len(removed) == 0 {
+			err = fmt.Errorf("%s (PID %d) is not snoozed", proc.Name, pid)
+		}
+	} else {
+		removed, err = store.Remove(args[0])
+	}
+	if err != nil {
+		return err
+	}
+
+	auditor, err := newAuditor(cfg)
+	if err != nil {
+		return fmt.Errorf("creating auditor: %w", err)
+	}
+	defer auditor.Close()
+	for _, s := range removed {
+		auditor.LogEvent("unsnooze", fmt.Sprintf("id=%s target=%q", s.ID, s.Target()))
+		fmt.Printf("Aura evaluates %s again\n", s.Target())
+	}
+	return nil
+}
+
+// newSnoozeStore opens the kept and snoozed processes shared by yolo, the
+// TUI and the snooze commands.
+func newSnoozeStore(cfg *config.Config) *safety.SnoozeStore {
+	return safety.NewSnoozeStore(cfg.Safety.SnoozeFile)
+}
+
+// findProcess returns the running process with the given PID.
+func findProcess(cfg *config.Config, pid int) (*monitor.ProcessInfo, error) {
+	mon := monitor.NewProcessMonitor(
+		cfg.Monitoring.ScanInterval,
+		cfg.Monitoring.HistorySize,
+		cfg.Safety.ProtectedProcs,
+	)
+	for _, p := range mon.Snapshot(0) {
+		if p.PID == pid {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("no process with PID %d", pid)
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -62,8 +64,44 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	fmt.Printf("  API Key Set:    %v\n", apiKeySet)
 	fmt.Println()
 
+	if err := printSchedules(cfg); err != nil {
+		return err
+	}
+
 	fmt.Printf("Protected Processes: %d configured\n", len(cfg.Safety.ProtectedProcs))
 	fmt.Printf("Never Terminate:     %d configured\n", len(cfg.Safety.NeverTerminate))
 
 	return nil
 }
+
+// printSchedules lists the maintenance windows and the settings in effect now.
+func printSchedules(cfg *config.Config) error {
+	schedule, err := newSchedule(cfg)
+	if err != nil || schedule == nil {
+		return err
+	}
+
+	now := time.Now()
+	fmt.Println("Schedules:")
+	for _, w := range schedule.Windows {
+		var effects []string
+		if w.ConsentLevel != nil {
+			effects = append(effects, fmt.Sprintf("consent %d", *w.ConsentLevel))
+		}
+		if w.Aggressiveness > 0 {
+			effects = append(effects, fmt.Sprintf("aggressiveness %d", w.Aggressiveness))
+		}
+		if len(effects) == 0 {
+			effects = append(effects, "policy rules only")
+		}
+		active := ""
+		if w.Cron.Matches(now) {
+			active = "  [active]"
+		}
+		fmt.Printf("  %-14s %-36s %s%s\n", w.Name, w.Cron, strings.Join(effects, ", "), active)
+	}
+	s := schedule.Resolve(now, safety.Settings{ConsentLevel: cfg.Safety.ConsentLevel, Aggressiveness: cfg.AI.Aggressiveness})
+	fmt.Printf("  Now: %s\n", s)
+	fmt.Println()
+	return nil
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/iamgilwell/aura/internal/ai"
+	"github.com/iamgilwell/aura/internal/config"
+	"github.com/iamgilwell/aura/internal/notification"
+)
+
+var (
+	summarySince     string
+	summaryNarrative bool
+)
+
+var summaryCmd = &cobra.Command{
+	Use:   "summary",
+	Short: "Summarize recent activity from the audit trail",
+	Long: `Aggregates the audit log (decisions, terminations, power savings and blocked
+actions) over a time window. With --narrative, Claude is asked for a short
+human-readable account of the session; without AI the local summary is shown.`,
+	RunE: runSummary,
+}
+
+func init() {
+	summaryCmd.Flags().StringVar(&summarySince, "since", "24h", "time window to summarize (e.g. 30m, 24h, 7d)")
+	summaryCmd.Flags().BoolVar(&summaryNarrative, "narrative", false, "ask the AI for a narrative of the session")
+}
+
+func runSummary(cmd *cobra.Command, args []string) error {
+	cfg := config.Global
+
+	window, err := ai.ParseDuration(summarySince)
+	if err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+	until := time.Now()
+	since := until.Add(-window)
+
+	entries, err := notification.ReadAudit(cfg.Notifications.AuditFile, since)
+	if err != nil {
+		return err
+	}
+
+	summary := notification.Summarize(entries, since, until)
+	if err := summary.Render(os.Stdout); err != nil {
+		return err
+	}
+
+	if !summaryNarrative {
+		return nil
+	}
+	if !cfg.AI.Enabled || cfg.Anthropic.APIKey == "" {
+		fmt.Println("\nAI is disabled; showing the local summary only.")
+		return nil
+	}
+
+	cache := ai.NewCache(cfg.AI.CacheSize, cfg.AI.CacheTTL)
+	aiEngine := ai.NewEngine(cfg.Anthropic.APIKey, cfg.Anthropic.Model, cache, cfg.AI.ConfidenceThreshold, cfg.AI.Aggressiveness)
+
+	narrative, err := aiEngine.Narrate(context.Background(), summary.String())
+	if err != nil {
+		fmt.Printf("\nCould not generate narrative: %v\n", err)
+		return nil
+	}
+	fmt.Printf("\n%s\n", narrative)
+	return nil
+}
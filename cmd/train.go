@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/iamgilwell/aura/internal/ai"
+	"github.com/iamgilwell/aura/internal/config"
+	"github.com/iamgilwell/aura/internal/notification"
+)
+
+var (
+	trainSince      string
+	trainOut        string
+	trainEpochs     int
+	trainMinSamples int
+)
+
+var trainCmd = &cobra.Command{
+	Use:   "train",
+	Short: "Train the local decision scorer from the audit history",
+	Long: `Fits a logistic regression over the process features recorded with each
+audited AI decision and user-initiated termination. Set ai.local_model to the
+output file to pre-screen processes locally so only uncertain cases reach the
+remote API.`,
+	RunE: runTrain,
+}
+
+func init() {
+	trainCmd.Flags().StringVar(&trainSince, "since", "30d", "how far back in the audit log to read")
+	trainCmd.Flags().StringVar(&trainOut, "out", "", "model output file (default: ai.local_model or aura-model.json)")
+	trainCmd.Flags().IntVar(&trainEpochs, "epochs", 500, "gradient descent iterations")
+	trainCmd.Flags().IntVar(&trainMinSamples, "min-samples", 20, "minimum labelled samples required")
+}
+
+func runTrain(cmd *cobra.Command, args []string) error {
+	cfg := config.Global
+
+	window, err := ai.ParseDuration(trainSince)
+	if err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+
+	entries, err := notification.ReadAudit(cfg.Notifications.AuditFile, time.Now().Add(-window))
+	if err != nil {
+		return err
+	}
+
+	samples := notification.TrainingSamples(entries)
+	if len(samples) < trainMinSamples {
+		return fmt.Errorf("only %d labelled samples in the audit log, need at least %d", len(samples), trainMinSamples)
+	}
+
+	var positives int
+	for _, s := range samples {
+		if s.Label >= 0.5 {
+			positives++
+		}
+	}
+
+	model, err := ai.TrainLocalModel(samples, trainEpochs, 0.1, 0.001)
+	if err != nil {
+		return err
+	}
+
+	out := trainOut
+	if out == "" {
+		out = cfg.AI.LocalModel
+	}
+	if out == "" {
+		out = "aura-model.json"
+	}
+	if err := model.Save(out); err != nil {
+		return err
+	}
+
+	fmt.Printf("Trained on %d samples (%d terminate, %d keep)\n", len(samples), positives, len(samples)-positives)
+	fmt.Printf("Training accuracy: %.1f%%\n", model.Accuracy*100)
+	fmt.Printf("Model written to %s\n", out)
+	if cfg.AI.LocalModel != out {
+		fmt.Printf("Set ai.local_model: %q to use it.\n", out)
+	}
+	return nil
+}
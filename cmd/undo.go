@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/iamgilwell/aura/internal/config"
+)
+
+var undoCmd = &cobra.Command{
+	Use:   "undo [termination-id]",
+	Short: "Relaunch a terminated process",
+	Long: `Relaunches a process terminated by Aura from the restore record captured
+before the termination: its argv, working directory, environment (secrets are
+not recorded and stay unset), user and resource limits. The termination ID is
+the "id" of the termination audit entry and is printed when Aura terminates
+a process. Without an ID, lists the terminations that can be undone.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runUndo,
+}
+
+func runUndo(cmd *cobra.Command, args []string) error {
+	cfg := config.Global
+
+	safetyMgr, err := newSafetyManager(cfg, cfg.Safety.ConsentLevel)
+	if err != nil {
+		return err
+	}
+	procMgr := newProcessManager(cfg, safetyMgr)
+	store := procMgr.Restores()
+	if store == nil {
+		return fmt.Errorf("undo is disabled (safety.restore_dir is empty)")
+	}
+
+	if len(args) == 0 {
+		recs, err := store.List()
+		if err != nil {
+			return err
+		}
+		if len(recs) == 0 {
+			fmt.Println("No terminations can be undone.")
+			return nil
+		}
+		fmt.Printf("%-8s %-20s %7s %-10s %-19s %s\n", "ID", "NAME", "PID", "USER", "TERMINATED", "COMMAND")
+		for _, r := range recs {
+			fmt.Printf("%-8s %-20s %7d %-10s %-19s %s\n",
+				r.ID, r.Name, r.PID, r.User, r.TerminatedAt.Format(time.DateTime), r.Command())
+		}
+		return nil
+	}
+
+	auditor, err := newAuditor(cfg)
+	if err != nil {
+		return fmt.Errorf("creating auditor: %w", err)
+	}
+	defer auditor.Close()
+
+	rec, pid, err := procMgr.Undo(args[0])
+	if rec == nil {
+		return err
+	}
+	auditor.LogRelaunch(rec.ID, rec.Name, pid, err)
+	if err != nil {
+		return fmt.Errorf("relaunching %s: %w", rec.Name, err)
+	}
+	if cfg.Safety.DryRun {
+		fmt.Printf("Would relaunch %s: %s\n", rec.Name, rec.Command())
+		return nil
+	}
+	fmt.Printf("Relaunched %s as PID %d\n", rec.Name, pid)
+	return nil
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/iamgilwell/aura/internal/config"
+	"github.com/iamgilwell/aura/internal/power"
+	"github.com/iamgilwell/aura/internal/process"
+	"github.com/iamgilwell/aura/internal/safety"
+)
+
+var unitCmd = &cobra.Command{
+	Use:   "unit <pid> [stop|restart|kill|signal]",
+	Short: "Stop, restart or kill the systemd unit owning a process",
+	Long: `Killing a process that belongs to a systemd service only makes systemd
+restart it if the unit's Restart= setting says so. Without an action, shows
+the unit owning the process, its Restart= setting and what a termination
+would do under safety.unit_action. With an action, terminates the process
+through its unit:
+
+  stop     systemctl stop: the unit and all its processes go
+  restart  systemctl restart
+  kill     systemctl kill with the escalation ladder; systemd may restart it
+  signal   signal the process as usual, ignoring the unit
+
+Every process of the unit is checked against the safety rules first.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runUnit,
+}
+
+func runUnit(cmd *cobra.Command, args []string) error {
+	cfg := config.Global
+
+	pid, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid PID %q", args[0])
+	}
+
+	safetyMgr, err := newSafetyManager(cfg, cfg.Safety.ConsentLevel)
+	if err != nil {
+		return err
+	}
+	procMgr := newProcessManager(cfg, safetyMgr)
+
+	proc, err := scanProcess(cfg, safetyMgr, procMgr, pid)
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 1 {
+		unit, ok := procMgr.UnitOf(proc)
+		if !ok {
+			if proc.Unit == "" {
+				fmt.Printf("%s (PID %d) is not owned by a systemd unit.\n", proc.Name, proc.PID)
+			} else {
+				fmt.Printf("%s (PID %d) belongs to %s, managed by another user's service manager.\n", proc.Name, proc.PID, proc.Unit)
+			}
+			return nil
+		}
+		fmt.Printf("Process:  %s (PID %d)\n", proc.Name, proc.PID)
+		fmt.Printf("Unit:     %s\n", unit.Name)
+		fmt.Printf("Cgroup:   %s\n", unit.Cgroup)
+		if unit.User {
+			fmt.Println("Manager:  user (systemctl --user)")
+		}
+		if unit.Restart != "" {
+			fmt.Printf("Restart:  %s\n", unit.Restart)
+		}
+		if members, err := procMgr.Members(proc, safety.ScopeUnit); err == nil {
+			fmt.Printf("Members:  %d processes\n", len(members))
+		}
+		if action, _, err := procMgr.UnitPlan(proc); err != nil {
+			fmt.Printf("Planned:  none (%v)\n", err)
+		} else {
+			fmt.Printf("Planned:  %s\n", unitPlanText(action, unit))
+		}
+		return nil
+	}
+
+	action, err := safety.ParseUnitAction(args[1])
+	if err != nil {
+		return err
+	}
+
+	auditor, err := newAuditor(cfg)
+	if err != nil {
+		return fmt.Errorf("creating auditor: %w", err)
+	}
+	defer auditor.Close()
+	auditHooks(procMgr, auditor)
+
+	t, err := procMgr.StartUnitAction(context.Background(), proc, action)
+	if err != nil {
+		auditor.LogBlocked(proc.PID, proc.Name, err.Error(), "")
+		return err
+	}
+	result, err := t.Wait()
+	if err != nil {
+		return fmt.Errorf("%s of %s (PID %d): %w", action, proc.Name, proc.PID, err)
+	}
+
+	powerCalc := power.NewCalculator(cfg.Power.CPUWattPerPercent, cfg.Power.MemoryWattPerMB, cfg.Power.DiskWattPerMBps)
+	reason := fmt.Sprintf("unit %s", action)
+	id, _ := logTermination(auditor, powerCalc, result, reason)
+	if cfg.Safety.DryRun {
+		fmt.Printf("Would %s %s (PID %d)%s%s\n", result.Verb(), proc.Name, proc.PID, result.Extent(), result.ActionVia())
+		return nil
+	}
+	hint := undoHint(result, id)
+	if err := saveRestore(procMgr, id, result); err != nil {
+		fmt.Printf("Warning: could not save restore record: %v\n", err)
+		hint = ""
+	}
+	fmt.Printf("%s %s (PID %d)%s, %s%s\n", terminated(result), proc.Name, proc.PID, result.Extent(), result.Outcome(), hint)
+	return nil
+}
+
+// unitPlanText describes a planned unit action.
+func unitPlanText(action safety.UnitAction, unit process.Unit) string {
+	if action == safety.UnitSignal {
+		return "signal the process"
+	}
+	return fmt.Sprintf("systemctl %s %s", action, unit.Name)
+}
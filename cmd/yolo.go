@@ -40,45 +40,111 @@ func runYolo(cmd *cobra.Command, args []string) error {
 	}
 	defer notifier.Close()
 
-	auditor, err := notification.NewAuditor(cfg.Notifications.AuditFile)
+	auditor, err := newAuditor(cfg)
 	if err != nil {
 		return fmt.Errorf("creating auditor: %w", err)
 	}
 	defer auditor.Close()
 
-	notifier.Warn("YOLO MODE ACTIVATED - AI will automatically terminate wasteful processes!")
-	auditor.LogEvent("yolo_start", "YOLO mode activated")
+	if cfg.Safety.DryRun {
+		notifier.Warn("YOLO DRY RUN - decisions are made and audited, but no process will be touched")
+		auditor.LogEvent("yolo_start", "YOLO mode activated (dry run)")
+	} else {
+		notifier.Warn("YOLO MODE ACTIVATED - AI will automatically terminate wasteful processes!")
+		auditor.LogEvent("yolo_start", "YOLO mode activated")
+	}
 
 	// Set consent to automatic
-	safetyMgr := safety.NewManager(cfg.Safety.ProtectedProcs, cfg.Safety.NeverTerminate, safety.ConsentAutomatic)
-	procMgr := process.NewManager(safetyMgr, cfg.Safety.TerminateTimeout)
+	safetyMgr, err := newSafetyManager(cfg, safety.ConsentAutomatic)
+	if err != nil {
+		return err
+	}
+	procMgr := newProcessManager(cfg, safetyMgr)
+	auditHooks(procMgr, auditor)
+	if s := auditSchedule(safetyMgr, auditor); len(s.Windows) > 0 {
+		notifier.Info("Now " + s.String())
+	}
+	tracker := process.NewOutcomeTracker(cfg.Safety.RespawnWindow, cfg.Safety.RespawnSuppress)
+	queue := newPendingQueue(cfg)
+	snoozeStore := newSnoozeStore(cfg)
 
 	cache := ai.NewCache(cfg.AI.CacheSize, cfg.AI.CacheTTL)
 	aiEngine := ai.NewEngine(cfg.Anthropic.APIKey, cfg.Anthropic.Model, cache, cfg.AI.ConfidenceThreshold, cfg.AI.Aggressiveness)
+	decider, closeDecider := newDecider(cfg, aiEngine, notifier)
+	defer closeDecider()
 
 	powerCalc := power.NewCalculator(cfg.Power.CPUWattPerPercent, cfg.Power.MemoryWattPerMB, cfg.Power.DiskWattPerMBps)
 	powerMetrics := power.NewMetrics()
+	powerMetrics.SetDryRun(cfg.Safety.DryRun)
 
 	mon := monitor.NewProcessMonitor(
 		cfg.Monitoring.ScanInterval,
 		cfg.Monitoring.HistorySize,
 		cfg.Safety.ProtectedProcs,
 	)
+	mon.SetPins(safetyMgr.Pins())
+	mon.SetContainerNames(procMgr.ContainerName)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	limitSeen := make(map[string]bool)
+
 	mon.OnUpdate(func(procs []*monitor.ProcessInfo, metrics *monitor.SystemMetrics) {
+		if s, changed := applySchedule(cfg, safetyMgr, aiEngine, auditor); changed {
+			notifier.Info("Now " + s.String())
+		}
+		procMgr.UpdateProtection(procs)
+		notification.ExpirePending(queue, auditor)
+
+		// Check whether earlier terminations came back
+		for _, ev := range tracker.Observe(procs) {
+			auditor.LogOutcome(ev.TerminationID, ev.Name, string(ev.Outcome), ev.NewPID, ev.After)
+			if ev.Outcome != process.OutcomeStayedDead {
+				notifier.Warn(fmt.Sprintf("%s was %s after %s (new PID %d); leaving it alone for %s",
+					ev.Name, ev.Outcome, ev.After.Truncate(time.Second), ev.NewPID, cfg.Safety.RespawnSuppress))
+			}
+		}
+
+		for _, proc := range procs {
+			if proc.Suspicious != "" && auditor.LogSuspicious(proc) {
+				notifier.Warn(fmt.Sprintf("Suspicious process: %s", proc.Suspicious))
+			}
+		}
+
+		if !safetyMgr.IsMonitorOnly() {
+			limitSeen = applyRuleLimits(procMgr, safetyMgr, auditor, notifier, procs, limitSeen)
+		}
+
+		snoozes, err := snoozeStore.List()
+		if err != nil {
+			notifier.Warn(fmt.Sprintf("Could not read snoozed processes: %v", err))
+		}
+
 		// Evaluate user processes with high resource usage
 		for _, proc := range procs {
+			if safetyMgr.IsMonitorOnly() {
+				break
+			}
 			if proc.Category != monitor.CategoryUser {
 				continue
 			}
 			if proc.CPU < cfg.Monitoring.CPUThreshold && proc.Memory < cfg.Monitoring.MemoryThreshold {
 				continue
 			}
+			if procMgr.Terminating(proc.PID) != nil {
+				continue
+			}
+			if suppressed, why := tracker.Suppressed(proc); suppressed {
+				notifier.Debug(fmt.Sprintf("Skipping PID %d: %s", proc.PID, why))
+				continue
+			}
+			if s, ok := snoozes.Find(proc); ok {
+				notifier.Debug(fmt.Sprintf("Skipping PID %d: %s %s", proc.PID, s.Target(), s.Expiry()))
+				continue
+			}
 
-			decision, err := aiEngine.EvaluateProcess(ctx, proc, metrics)
+			decision, err := decider.EvaluateProcess(ctx, proc, metrics)
 			if err != nil {
 				notifier.Error(fmt.Sprintf("AI evaluation failed for PID %d: %v", proc.PID, err))
 				continue
@@ -87,41 +153,219 @@ func runYolo(cmd *cobra.Command, args []string) error {
 			notifier.Decision(decision)
 			auditor.LogDecision(decision)
 
-			if decision.Action == ai.ActionTerminate && decision.Confidence >= cfg.AI.ConfidenceThreshold {
-				if safetyMgr.IsProtected(proc) {
-					notifier.Warn(fmt.Sprintf("Skipping protected process: %s (PID %d)", proc.Name, proc.PID))
+			var action safety.Action
+			switch decision.Action {
+			case ai.ActionTerminate:
+				action = safety.ActionTerminate
+			case ai.ActionThrottle:
+				action = safety.ActionThrottle
+			}
+			if action == "" || decision.Confidence < cfg.AI.ConfidenceThreshold {
+				continue
+			}
+
+			verdict := safetyMgr.EvaluateAction(proc, action)
+			if action == safety.ActionTerminate {
+				// The rest of its scope may need consent the target does not
+				verdict = procMgr.TerminationVerdict(proc)
+			}
+			if !verdict.Allowed {
+				notifier.Warn(fmt.Sprintf("Skipping %s (PID %d): %s", proc.Name, proc.PID, verdict.Reason))
+				auditor.LogBlocked(proc.PID, proc.Name, verdict.Reason, verdict.Rule)
+				continue
+			}
+			if verdict.Confirm {
+				parkForApproval(queue, auditor, notifier, proc, action, decision, "yolo")
+				continue
+			}
+
+			if action == safety.ActionThrottle {
+				if err := procMgr.SafeThrottle(proc); err != nil {
+					notifier.Error(fmt.Sprintf("Failed to throttle PID %d: %v", proc.PID, err))
 					continue
 				}
-
-				notifier.Warn(fmt.Sprintf("Terminating: %s (PID %d) - %s", proc.Name, proc.PID, decision.Reason))
-				if err := procMgr.SafeTerminate(proc, false); err != nil {
-					notifier.Error(fmt.Sprintf("Failed to terminate PID %d: %v", proc.PID, err))
+				auditor.LogAction(action.Past(), proc, decision.Reason)
+				verb := "Throttled"
+				if cfg.Safety.DryRun {
+					verb = "Would throttle"
+				}
+				msg := fmt.Sprintf("%s: %s (PID %d) to nice %d", verb, proc.Name, proc.PID, process.ThrottleNice)
+				if l := procMgr.LimitsFor(proc); !l.IsZero() {
+					msg += ", " + l.String()
+				}
+				msg += " - " + decision.Reason
+				if verdict.Notify {
+					notifier.Warn(msg)
 				} else {
-					savings := powerCalc.EstimateSavings(proc)
+					notifier.Info(msg)
+				}
+				continue
+			}
+
+			if ok, reason := safetyMgr.CheckAutomatic(proc); !ok {
+				notifier.Error(fmt.Sprintf("!!! TERMINATION BUDGET TRIPPED: %s — switching to monitor-only mode, restart yolo to resume !!!", reason))
+				auditor.LogBlocked(proc.PID, proc.Name, reason, "")
+				auditor.LogEvent("budget_tripped", reason)
+				break
+			}
+
+			if cfg.Safety.DryRun {
+				result, err := procMgr.SafeTerminate(proc, false)
+				if err != nil {
+					notifier.Error(fmt.Sprintf("Would fail to terminate PID %d: %v", proc.PID, err))
+					continue
+				}
+				safetyMgr.ChargeAutomatic(proc)
+				_, savings := logTermination(auditor, powerCalc, result, decision.Reason)
+				if !result.Paused() {
 					powerMetrics.RecordSaving(proc.Name, proc.PID, savings, decision.Reason)
-					auditor.LogTermination(proc.PID, proc.Name, decision.Reason)
-					notifier.Info(fmt.Sprintf("Terminated PID %d, saved %.2fW (total: %.2fW)",
-						proc.PID, savings, powerMetrics.TotalSaved()))
 				}
+				notifier.Warn(fmt.Sprintf("Would terminate: %s (PID %d)%s, saving %.2fW (total: %.2fW) - %s",
+					proc.Name, proc.PID, result.Extent(), savings, powerMetrics.WouldSave(), decision.Reason))
+				continue
+			}
+
+			// The escalation runs in the background so that a slow exit
+			// does not hold up the next scan
+			notifier.Warn(fmt.Sprintf("Terminating: %s (PID %d) - %s", proc.Name, proc.PID, decision.Reason))
+			t, err := procMgr.StartTerminate(ctx, proc, false)
+			if err != nil {
+				notifier.Error(fmt.Sprintf("Failed to terminate PID %d: %v", proc.PID, err))
+				continue
 			}
+			safetyMgr.ChargeAutomatic(proc)
+			go func(proc *monitor.ProcessInfo, reason string) {
+				result, err := t.Wait()
+				if !t.Acted() {
+					// Vetoed or cancelled before touching it
+					safetyMgr.RefundAutomatic(proc)
+				}
+				if err != nil {
+					notifier.Error(fmt.Sprintf("Failed to terminate PID %d: %v", proc.PID, err))
+					return
+				}
+				id, savings := logTermination(auditor, powerCalc, result, reason)
+				if result.Paused() {
+					// Nothing was freed, and nothing can respawn
+					notifier.Info(fmt.Sprintf("Paused PID %d%s (%s)%s", proc.PID, result.Extent(), result.Outcome(), undoHint(result, id)))
+					return
+				}
+				powerMetrics.RecordSaving(proc.Name, proc.PID, savings, reason)
+				tracker.Track(id, proc)
+				hint := undoHint(result, id)
+				if err := saveRestore(procMgr, id, result); err != nil {
+					notifier.Warn(fmt.Sprintf("Could not save restore record for PID %d: %v", proc.PID, err))
+					hint = ""
+				}
+				notifier.Info(fmt.Sprintf("Terminated PID %d%s (%s), saved %.2fW (total: %.2fW)%s",
+					proc.PID, result.Extent(), result.Outcome(), savings, powerMetrics.TotalSaved(), hint))
+			}(proc, decision.Reason)
 		}
 
 		// Periodic status
+		if cfg.Safety.DryRun {
+			fmt.Printf("\n[%s] Processes: %d | CPU: %.1f%% | Mem: %.1f%% | Dry run, would have saved: %.2fW\n",
+				time.Now().Format("15:04:05"),
+				metrics.NumProcs, metrics.TotalCPU, metrics.TotalMemory, powerMetrics.WouldSave())
+			return
+		}
 		fmt.Printf("\n[%s] Processes: %d | CPU: %.1f%% | Mem: %.1f%% | Power saved: %.2fW | Monthly projection: %.2f kWh\n",
 			time.Now().Format("15:04:05"),
 			metrics.NumProcs, metrics.TotalCPU, metrics.TotalMemory,
 			powerMetrics.TotalSaved(), powerMetrics.MonthlyProjection())
 	})
 
+	// The OOM responder checks memory pressure between scans and picks its
+	// victims from the latest one
+	if cfg.OOM.Enabled {
+		responder, err := newOOMResponder(cfg, procMgr, mon.Processes)
+		if err != nil {
+			return err
+		}
+		responder.SetSkip(oomSkip(tracker, snoozeStore))
+		auditOOM(cfg, responder, safetyMgr, procMgr, tracker, auditor, notifier)
+		go func() {
+			if err := responder.Run(ctx, cfg.OOM.Interval); err != nil && ctx.Err() == nil {
+				notifier.Error(fmt.Sprintf("OOM responder stopped: %v", err))
+			}
+		}()
+	}
+
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 
 	go func() {
 		<-sigCh
 		notifier.Info("Shutting down YOLO mode...")
-		auditor.LogEvent("yolo_stop", fmt.Sprintf("Total power saved: %.2fW", powerMetrics.TotalSaved()))
+		if cfg.Safety.DryRun {
+			auditor.LogEvent("yolo_stop", fmt.Sprintf("Dry run, power that would have been saved: %.2fW", powerMetrics.WouldSave()))
+		} else {
+			auditor.LogEvent("yolo_stop", fmt.Sprintf("Total power saved: %.2fW", powerMetrics.TotalSaved()))
+		}
 		cancel()
 	}()
 
 	return mon.Start(ctx)
 }
+
+// parkForApproval queues a recommended action that needs consent and tells
+// the user how to act on it.
+func parkForApproval(queue *safety.PendingQueue, auditor *notification.Auditor, notifier *notification.Notifier,
+	proc *monitor.ProcessInfo, action safety.Action, decision *ai.DecisionResponse, source string) {
+	item, added, err := notification.ParkForApproval(queue, auditor, proc, action, decision, source)
+	if err != nil {
+		notifier.Error(fmt.Sprintf("Could not queue %s (PID %d) for approval: %v", proc.Name, proc.PID, err))
+		return
+	}
+	if added {
+		notifier.Warn(fmt.Sprintf("%s of %s (PID %d) needs approval: aura approve %s / aura deny %s",
+			action, proc.Name, proc.PID, item.ID, item.ID))
+	}
+}
+
+// applyRuleLimits applies the limits of the policy rule matching each
+// process Aura has not limited yet, where the consent matrix lets the limit
+// action go ahead without asking. seen holds the processes handled at
+// earlier scans, by PID and start time; the processes of this scan are
+// returned for the next one.
+func applyRuleLimits(procMgr *process.Manager, safetyMgr *safety.Manager, auditor *notification.Auditor, notifier *notification.Notifier,
+	procs []*monitor.ProcessInfo, seen map[string]bool) map[string]bool {
+	next := make(map[string]bool, len(seen))
+	for _, proc := range procs {
+		key := fmt.Sprintf("%d/%d", proc.PID, proc.StartTime.Unix())
+		next[key] = true
+		if seen[key] {
+			continue
+		}
+
+		verdict := procMgr.LimitVerdict(proc, procMgr.LimitsFor(proc))
+		if !verdict.Allowed || verdict.Limits.IsZero() || procMgr.Limited(proc) != nil {
+			continue
+		}
+		if verdict.Confirm {
+			notifier.Debug(fmt.Sprintf("Not limiting %s (PID %d) without consent: aura limit %d", proc.Name, proc.PID, proc.PID))
+			continue
+		}
+
+		rec, err := procMgr.SafeLimit(proc, verdict.Limits)
+		if rec == nil {
+			notifier.Error(fmt.Sprintf("Failed to limit PID %d: %v", proc.PID, err))
+			continue
+		}
+		auditor.LogAction(safety.ActionLimit.Past(), proc, fmt.Sprintf("policy rule '%s'", verdict.Rule))
+		verb := "Limited"
+		if procMgr.DryRun() {
+			verb = "Would limit"
+		}
+		msg := fmt.Sprintf("%s: %s (PID %d) to %s - policy rule '%s'", verb, proc.Name, proc.PID, rec.Applied, verdict.Rule)
+		switch {
+		case err != nil:
+			notifier.Warn(fmt.Sprintf("%s; %v", msg, err))
+		case verdict.Notify:
+			notifier.Warn(msg)
+		default:
+			notifier.Info(msg)
+		}
+	}
+	return next
+}
@@ -0,0 +1,263 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/iamgilwell/aura/internal/monitor"
+)
+
+// Intent describes what a natural-language request asks Aura to do.
+type Intent string
+
+const (
+	IntentQuery   Intent = "query"   // List matching processes
+	IntentAction  Intent = "action"  // Propose an action on matching processes
+	IntentExplain Intent = "explain" // Answer a question about the system
+)
+
+// ProcessQuery is a structured filter over ProcessMonitor.Processes().
+type ProcessQuery struct {
+	Name        string       `json:"name,omitempty"`             // glob matched against the process name
+	Cmdline     string       `json:"cmdline_contains,omitempty"` // substring of the command line
+	User        string       `json:"user,omitempty"`
+	Category    string       `json:"category,omitempty"`
+	State       string       `json:"state,omitempty"`
+	MinCPU      float64      `json:"min_cpu,omitempty"`
+	MinMemoryMB float64      `json:"min_memory_mb,omitempty"`
+	MinAge      JSONDuration `json:"min_age,omitempty"`
+	MaxAge      JSONDuration `json:"max_age,omitempty"`
+	SortBy      string       `json:"sort_by,omitempty"` // cpu, memory or age
+	Limit       int          `json:"limit,omitempty"`
+}
+
+// CommandPlan is the structured translation of a natural-language request.
+// Targets are resolved locally from Query; the model never picks PIDs.
+type CommandPlan struct {
+	Request string       `json:"request"`
+	Intent  Intent       `json:"intent"`
+	Query   ProcessQuery `json:"query"`
+	Action  Action       `json:"action,omitempty"`
+	Answer  string       `json:"answer,omitempty"`
+}
+
+// JSONDuration is a time.Duration that unmarshals from strings like "24h".
+type JSONDuration time.Duration
+
+// UnmarshalJSON accepts Go duration strings, plus a "d" suffix for days.
+func (d *JSONDuration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		var secs float64
+		if err := json.Unmarshal(b, &secs); err != nil {
+			return fmt.Errorf("invalid duration %s", string(b))
+		}
+		*d = JSONDuration(time.Duration(secs * float64(time.Second)))
+		return nil
+	}
+	parsed, err := ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = JSONDuration(parsed)
+	return nil
+}
+
+// MarshalJSON encodes the duration as a Go duration string.
+func (d JSONDuration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// ParseDuration is time.ParseDuration with support for whole days ("2d").
+func ParseDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	if strings.HasSuffix(s, "d") {
+		var days float64
+		if _, err := fmt.Sscanf(strings.TrimSuffix(s, "d"), "%g", &days); err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return time.Duration(days * float64(24*time.Hour)), nil
+	}
+	return time.ParseDuration(s)
+}
+
+// Match reports whether a process satisfies every set field of the query.
+func (q *ProcessQuery) Match(p *monitor.ProcessInfo) bool {
+	if q.Name != "" {
+		if ok, _ := path.Match(strings.ToLower(q.Name), strings.ToLower(p.Name)); !ok {
+			return false
+		}
+	}
+	if q.Cmdline != "" && !strings.Contains(strings.ToLower(p.Cmdline), strings.ToLower(q.Cmdline)) {
+		return false
+	}
+	if q.User != "" && q.User != p.User {
+		return false
+	}
+	if q.Category != "" && !strings.EqualFold(q.Category, p.Category.String()) {
+		return false
+	}
+	if q.State != "" && q.State != p.State {
+		return false
+	}
+	if p.CPU < q.MinCPU || p.MemoryMB < q.MinMemoryMB {
+		return false
+	}
+	if q.MinAge > 0 || q.MaxAge > 0 {
+		age := time.Since(p.StartTime)
+		if q.MinAge > 0 && age < time.Duration(q.MinAge) {
+			return false
+		}
+		if q.MaxAge > 0 && age > time.Duration(q.MaxAge) {
+			return false
+		}
+	}
+	return true
+}
+
+// Filter returns the processes matching the query, sorted and limited.
+func (q *ProcessQuery) Filter(procs []*monitor.ProcessInfo) []*monitor.ProcessInfo {
+	var result []*monitor.ProcessInfo
+	for _, p := range procs {
+		if q.Match(p) {
+			result = append(result, p)
+		}
+	}
+
+	switch q.SortBy {
+	case "memory":
+		sort.SliceStable(result, func(i, j int) bool { return result[i].MemoryMB > result[j].MemoryMB })
+	case "age":
+		sort.SliceStable(result, func(i, j int) bool { return result[i].StartTime.Before(result[j].StartTime) })
+	default:
+		sort.SliceStable(result, func(i, j int) bool { return result[i].CPU > result[j].CPU })
+	}
+
+	if q.Limit > 0 && len(result) > q.Limit {
+		result = result[:q.Limit]
+	}
+	return result
+}
+
+// ParseCommandPlan extracts a CommandPlan from a model response.
+func ParseCommandPlan(text string) (*CommandPlan, error) {
+	start := strings.Index(text, "{")
+	end := strings.LastIndex(text, "}")
+	if start < 0 || end < 0 || end <= start {
+		return nil, fmt.Errorf("no JSON found in response")
+	}
+
+	var plan CommandPlan
+	if err := json.Unmarshal([]byte(text[start:end+1]), &plan); err != nil {
+		return nil, fmt.Errorf("parsing command plan: %w", err)
+	}
+
+	switch plan.Intent {
+	case IntentQuery, IntentExplain:
+		plan.Action = ""
+	case IntentAction:
+		if plan.Action != ActionTerminate {
+			return nil, fmt.Errorf("unsupported action %q", plan.Action)
+		}
+	default:
+		return nil, fmt.Errorf("unknown intent %q", plan.Intent)
+	}
+	return &plan, nil
+}
+
+// Interpret translates a natural-language request into a CommandPlan.
+// The plan is only a proposal: callers must validate targets with the
+// safety manager and obtain explicit confirmation before acting.
+func (e *Engine) Interpret(ctx context.Context, request string, procs []*monitor.ProcessInfo, state *monitor.SystemMetrics) (*CommandPlan, error) {
+	msg, err := e.client.Messages.New(ctx, anthropic.MessageNewParams{
+		Model:     e.model,
+		MaxTokens: 1024,
+		System: []anthropic.TextBlockParam{
+			{Text: commandSystemPrompt},
+		},
+		Messages: []anthropic.MessageParam{
+			anthropic.NewUserMessage(anthropic.NewTextBlock(buildCommandPrompt(request, procs, state))),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+
+	var responseText string
+	for _, block := range msg.Content {
+		if block.Type == "text" {
+			responseText = block.Text
+			break
+		}
+	}
+
+	plan, err := ParseCommandPlan(responseText)
+	if err != nil {
+		return nil, err
+	}
+	plan.Request = request
+	return plan, nil
+}
+
+const commandSystemPrompt = `You are Aura, an AI-powered Linux process optimizer. Translate the user's request into a structured query over the running processes.
+
+RULES:
+- Use intent "query" to list processes, "action" to propose terminating them, and "explain" to answer a question
+- Never name PIDs; describe targets only through the query fields
+- Durations use Go syntax or days, e.g. "90m", "24h", "2d"
+- For "explain", put a short answer in "answer" grounded in the process list provided
+
+Respond ONLY with valid JSON in this exact format:
+{
+  "intent": "query|action|explain",
+  "query": {
+    "name": "glob on process name",
+    "cmdline_contains": "substring",
+    "user": "",
+    "category": "User|System|Essential|Kernel",
+    "state": "",
+    "min_cpu": 0.0,
+    "min_memory_mb": 0.0,
+    "min_age": "",
+    "max_age": "",
+    "sort_by": "cpu|memory|age",
+    "limit": 0
+  },
+  "action": "terminate",
+  "answer": ""
+}`
+
+func buildCommandPrompt(request string, procs []*monitor.ProcessInfo, state *monitor.SystemMetrics) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Request: %s\n\n", request))
+
+	if state != nil {
+		sb.WriteString("System State:\n")
+		sb.WriteString(fmt.Sprintf("Total CPU Usage: %.1f%%\n", state.TotalCPU))
+		sb.WriteString(fmt.Sprintf("Memory Usage: %.1f%% (%.0f MB used / %.0f MB total)\n",
+			state.TotalMemory, state.TotalMemMB-state.FreeMemMB, state.TotalMemMB))
+		sb.WriteString(fmt.Sprintf("Load Average: %.2f, %.2f, %.2f\n\n",
+			state.LoadAvg1, state.LoadAvg5, state.LoadAvg15))
+	}
+
+	sb.WriteString("Top processes (name, user, category, CPU%, MB, age, command):\n")
+	limit := 25
+	if len(procs) < limit {
+		limit = len(procs)
+	}
+	for _, p := range procs[:limit] {
+		sb.WriteString(fmt.Sprintf("- %s | %s | %s | %.1f | %.0f | %s | %s\n",
+			p.Name, p.User, p.Category, p.CPU, p.MemoryMB,
+			time.Since(p.StartTime).Truncate(time.Minute), p.Cmdline))
+	}
+	return sb.String()
+}
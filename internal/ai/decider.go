@@ -0,0 +1,72 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/iamgilwell/aura/internal/monitor"
+)
+
+// Decider evaluates whether a process should be acted on. Engine is the
+// remote implementation; wrappers below combine it with local logic.
+type Decider interface {
+	EvaluateProcess(ctx context.Context, proc *monitor.ProcessInfo, state *monitor.SystemMetrics) (*DecisionResponse, error)
+}
+
+// ScreenedDecider scores processes with a local model first and only
+// forwards uncertain cases to the remote decider.
+type ScreenedDecider struct {
+	model          *LocalModel
+	remote         Decider
+	keepBelow      float64
+	terminateAbove float64
+}
+
+// NewScreenedDecider creates a decider that keeps processes scoring at or
+// below keepBelow, terminates those at or above terminateAbove, and asks
+// remote about everything in between. remote may be nil.
+func NewScreenedDecider(model *LocalModel, remote Decider, keepBelow, terminateAbove float64) *ScreenedDecider {
+	return &ScreenedDecider{
+		model:          model,
+		remote:         remote,
+		keepBelow:      keepBelow,
+		terminateAbove: terminateAbove,
+	}
+}
+
+// EvaluateProcess implements Decider.
+func (d *ScreenedDecider) EvaluateProcess(ctx context.Context, proc *monitor.ProcessInfo, state *monitor.SystemMetrics) (*DecisionResponse, error) {
+	features := NewProcessFeatures(proc)
+	score := d.model.Predict(features)
+
+	decision := &DecisionResponse{
+		ProcessPID:  proc.PID,
+		ProcessName: proc.Name,
+		ProcessUnit: proc.Unit,
+		Timestamp:   time.Now(),
+		Source:      "local",
+		Features:    features,
+	}
+
+	switch {
+	case score <= d.keepBelow:
+		decision.Action = ActionKeep
+		decision.Confidence = 1 - score
+		decision.Reason = fmt.Sprintf("local model score %.2f", score)
+		return decision, nil
+	case score >= d.terminateAbove:
+		decision.Action = ActionTerminate
+		decision.Confidence = score
+		decision.RiskScore = 1 - score
+		decision.Reason = fmt.Sprintf("local model score %.2f", score)
+		return decision, nil
+	}
+
+	if d.remote == nil {
+		decision.Action = ActionKeep
+		decision.Reason = fmt.Sprintf("local model uncertain (score %.2f) and no remote engine - defaulting to keep", score)
+		return decision, nil
+	}
+	return d.remote.EvaluateProcess(ctx, proc, state)
+}
@@ -20,6 +20,7 @@ type Engine struct {
 	cache            *Cache
 	confidenceThresh float64
 	aggressiveness   int
+	promptOverride   string
 
 	mu         sync.RWMutex
 	history    []*DecisionResponse
@@ -58,7 +59,7 @@ func (e *Engine) EvaluateProcess(ctx context.Context, proc *monitor.ProcessInfo,
 		Model:     e.model,
 		MaxTokens: 1024,
 		System: []anthropic.TextBlockParam{
-			{Text: systemPrompt(e.aggressiveness)},
+			{Text: e.systemPrompt()},
 		},
 		Messages: []anthropic.MessageParam{
 			anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
@@ -107,6 +108,43 @@ func (e *Engine) addToHistory(d *DecisionResponse) {
 	}
 }
 
+// SetSystemPrompt replaces the built-in evaluation prompt. It is used to
+// trial alternative prompts, e.g. in shadow mode. An empty string restores
+// the default.
+func (e *Engine) SetSystemPrompt(prompt string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.promptOverride = prompt
+}
+
+// SetAggressiveness changes the aggressiveness level (1-10) used in the
+// evaluation prompt. Cached decisions made at another level are dropped.
+func (e *Engine) SetAggressiveness(level int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if level == e.aggressiveness {
+		return
+	}
+	e.aggressiveness = level
+	e.cache.Clear()
+}
+
+// Aggressiveness returns the current aggressiveness level.
+func (e *Engine) Aggressiveness() int {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.aggressiveness
+}
+
+func (e *Engine) systemPrompt() string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.promptOverride != "" {
+		return e.promptOverride
+	}
+	return systemPrompt(e.aggressiveness)
+}
+
 func systemPrompt(aggressiveness int) string {
 	return fmt.Sprintf(`You are Aura, an AI-powered Linux process optimizer. Your job is to evaluate running processes and decide whether they should be terminated to save resources and power.
 
@@ -140,6 +178,12 @@ func (e *Engine) buildPrompt(proc *monitor.ProcessInfo, state *monitor.SystemMet
 	sb.WriteString(fmt.Sprintf("State: %s\n", proc.State))
 	sb.WriteString(fmt.Sprintf("Parent PID: %d\n", proc.PPid))
 	sb.WriteString(fmt.Sprintf("Category: %s\n", proc.Category))
+	if proc.Unit != "" {
+		sb.WriteString(fmt.Sprintf("Systemd Unit: %s\n", proc.Unit))
+	}
+	if proc.Container != "" {
+		sb.WriteString(fmt.Sprintf("Container: %s\n", proc.ContainerLabel()))
+	}
 	sb.WriteString(fmt.Sprintf("Command: %s\n", proc.Cmdline))
 	sb.WriteString(fmt.Sprintf("CPU Trend: %+.1f%%\n", proc.CPUTrend))
 	sb.WriteString(fmt.Sprintf("Memory Trend: %+.1f%%\n", proc.MemoryTrend))
@@ -180,12 +224,15 @@ func (e *Engine) parseResponse(text string, proc *monitor.ProcessInfo) (*Decisio
 	return &DecisionResponse{
 		ProcessPID:  proc.PID,
 		ProcessName: proc.Name,
+		ProcessUnit: proc.Unit,
 		Action:      Action(raw.Action),
 		Confidence:  raw.Confidence,
 		Reason:      raw.Reason,
 		RiskScore:   raw.RiskScore,
 		SavingsWatt: raw.SavingsWatt,
 		Timestamp:   time.Now(),
+		Source:      "remote",
+		Features:    NewProcessFeatures(proc),
 	}, nil
 }
 
@@ -193,6 +240,7 @@ func (e *Engine) fallbackDecision(proc *monitor.ProcessInfo, err error) *Decisio
 	return &DecisionResponse{
 		ProcessPID:  proc.PID,
 		ProcessName: proc.Name,
+		ProcessUnit: proc.Unit,
 		Action:      ActionKeep,
 		Confidence:  0.0,
 		Reason:      fmt.Sprintf("AI unavailable (%v) - defaulting to keep", err),
@@ -0,0 +1,34 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// Narrate asks the model for a short human-readable account of an audit
+// summary. report is the locally rendered summary text.
+func (e *Engine) Narrate(ctx context.Context, report string) (string, error) {
+	msg, err := e.client.Messages.New(ctx, anthropic.MessageNewParams{
+		Model:     e.model,
+		MaxTokens: 1024,
+		System: []anthropic.TextBlockParam{
+			{Text: `You are Aura, an AI-powered Linux process optimizer. Given an aggregate of Aura's audit trail, write a brief plain-text narrative (at most two short paragraphs) of what happened during the session and why: which processes were terminated, how much power was saved, and what was blocked by safety rules. Do not invent events that are not in the report.`},
+		},
+		Messages: []anthropic.MessageParam{
+			anthropic.NewUserMessage(anthropic.NewTextBlock(report)),
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("API call failed: %w", err)
+	}
+
+	for _, block := range msg.Content {
+		if block.Type == "text" {
+			return strings.TrimSpace(block.Text), nil
+		}
+	}
+	return "", fmt.Errorf("no text in response")
+}
@@ -0,0 +1,165 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"time"
+)
+
+// TrainingSample is a labelled feature set. Label is 1 for processes that
+// should be terminated and 0 for processes that should be kept.
+type TrainingSample struct {
+	Features *ProcessFeatures
+	Label    float64
+}
+
+// LocalModel is a logistic regression over standardized ProcessFeatures.
+type LocalModel struct {
+	FeatureNames []string  `json:"feature_names"`
+	Weights      []float64 `json:"weights"`
+	Bias         float64   `json:"bias"`
+	Mean         []float64 `json:"mean"`
+	Std          []float64 `json:"std"`
+	Samples      int       `json:"samples"`
+	Accuracy     float64   `json:"accuracy"`
+	TrainedAt    time.Time `json:"trained_at"`
+}
+
+// TrainLocalModel fits a logistic regression with L2 regularization using
+// batch gradient descent.
+func TrainLocalModel(samples []TrainingSample, epochs int, learningRate, l2 float64) (*LocalModel, error) {
+	if len(samples) == 0 {
+		return nil, fmt.Errorf("no training samples")
+	}
+
+	n := len(FeatureNames)
+	xs := make([][]float64, len(samples))
+	for i, s := range samples {
+		xs[i] = s.Features.Vector()
+	}
+
+	// Standardize each feature
+	mean := make([]float64, n)
+	std := make([]float64, n)
+	for _, x := range xs {
+		for j, v := range x {
+			mean[j] += v
+		}
+	}
+	for j := range mean {
+		mean[j] /= float64(len(xs))
+	}
+	for _, x := range xs {
+		for j, v := range x {
+			std[j] += (v - mean[j]) * (v - mean[j])
+		}
+	}
+	for j := range std {
+		std[j] = math.Sqrt(std[j] / float64(len(xs)))
+		if std[j] < 1e-9 {
+			std[j] = 1
+		}
+	}
+	for _, x := range xs {
+		for j := range x {
+			x[j] = (x[j] - mean[j]) / std[j]
+		}
+	}
+
+	weights := make([]float64, n)
+	var bias float64
+	grad := make([]float64, n)
+	m := float64(len(xs))
+
+	for epoch := 0; epoch < epochs; epoch++ {
+		for j := range grad {
+			grad[j] = 0
+		}
+		var gradBias float64
+		for i, x := range xs {
+			err := sigmoid(dot(weights, x)+bias) - samples[i].Label
+			for j, v := range x {
+				grad[j] += err * v
+			}
+			gradBias += err
+		}
+		for j := range weights {
+			weights[j] -= learningRate * (grad[j]/m + l2*weights[j])
+		}
+		bias -= learningRate * gradBias / m
+	}
+
+	model := &LocalModel{
+		FeatureNames: FeatureNames,
+		Weights:      weights,
+		Bias:         bias,
+		Mean:         mean,
+		Std:          std,
+		Samples:      len(samples),
+		TrainedAt:    time.Now(),
+	}
+
+	var correct int
+	for _, s := range samples {
+		if (model.Predict(s.Features) >= 0.5) == (s.Label >= 0.5) {
+			correct++
+		}
+	}
+	model.Accuracy = float64(correct) / m
+	return model, nil
+}
+
+// Predict returns the probability that a process should be terminated.
+func (m *LocalModel) Predict(f *ProcessFeatures) float64 {
+	x := f.Vector()
+	z := m.Bias
+	for j := range x {
+		if j >= len(m.Weights) {
+			break
+		}
+		z += m.Weights[j] * (x[j] - m.Mean[j]) / m.Std[j]
+	}
+	return sigmoid(z)
+}
+
+// Save writes the model as JSON.
+func (m *LocalModel) Save(path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding model: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("writing model: %w", err)
+	}
+	return nil
+}
+
+// LoadLocalModel reads a model written by Save.
+func LoadLocalModel(path string) (*LocalModel, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading model: %w", err)
+	}
+	var m LocalModel
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parsing model: %w", err)
+	}
+	if len(m.Weights) != len(FeatureNames) || len(m.Mean) != len(m.Weights) || len(m.Std) != len(m.Weights) {
+		return nil, fmt.Errorf("model has %d weights, expected %d", len(m.Weights), len(FeatureNames))
+	}
+	return &m, nil
+}
+
+func sigmoid(z float64) float64 {
+	return 1 / (1 + math.Exp(-z))
+}
+
+func dot(a, b []float64) float64 {
+	var sum float64
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
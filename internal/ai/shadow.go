@@ -0,0 +1,205 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/iamgilwell/aura/internal/monitor"
+)
+
+// ShadowDecider returns the primary decider's decisions unchanged while
+// evaluating the same processes with a shadow decider in the background.
+// Shadow decisions are only compared and reported, never acted on.
+type ShadowDecider struct {
+	primary Decider
+	shadow  Decider
+	report  *ShadowReport
+	timeout time.Duration
+	wg      sync.WaitGroup
+}
+
+// NewShadowDecider creates a shadow-mode decider.
+func NewShadowDecider(primary, shadow Decider, report *ShadowReport, timeout time.Duration) *ShadowDecider {
+	return &ShadowDecider{
+		primary: primary,
+		shadow:  shadow,
+		report:  report,
+		timeout: timeout,
+	}
+}
+
+// EvaluateProcess implements Decider.
+func (d *ShadowDecider) EvaluateProcess(ctx context.Context, proc *monitor.ProcessInfo, state *monitor.SystemMetrics) (*DecisionResponse, error) {
+	decision, err := d.primary.EvaluateProcess(ctx, proc, state)
+	if err != nil {
+		return nil, err
+	}
+
+	// Copy inputs so later scans cannot mutate them under the shadow
+	procCopy := *proc
+	var stateCopy monitor.SystemMetrics
+	if state != nil {
+		stateCopy = *state
+	}
+	primaryCopy := *decision
+
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		sctx, cancel := context.WithTimeout(context.Background(), d.timeout)
+		defer cancel()
+
+		shadowDecision, err := d.shadow.EvaluateProcess(sctx, &procCopy, &stateCopy)
+		if err != nil {
+			d.report.RecordError(&procCopy, err)
+			return
+		}
+		d.report.Record(&procCopy, &primaryCopy, shadowDecision)
+	}()
+
+	return decision, nil
+}
+
+// Close waits for in-flight shadow evaluations and closes the report.
+func (d *ShadowDecider) Close() error {
+	d.wg.Wait()
+	return d.report.Close()
+}
+
+// Report returns the comparison report.
+func (d *ShadowDecider) Report() *ShadowReport {
+	return d.report
+}
+
+// ShadowStats counts agreement between primary and shadow decisions.
+type ShadowStats struct {
+	Total     int            `json:"total"`
+	Agreed    int            `json:"agreed"`
+	Disagreed int            `json:"disagreed"`
+	Errors    int            `json:"errors"`
+	Pairs     map[string]int `json:"pairs"` // "primary->shadow" action counts for disagreements
+}
+
+// AgreementRate returns the fraction of compared decisions that agreed.
+func (s ShadowStats) AgreementRate() float64 {
+	if s.Total == 0 {
+		return 0
+	}
+	return float64(s.Agreed) / float64(s.Total)
+}
+
+type shadowCase struct {
+	Timestamp time.Time         `json:"timestamp"`
+	Event     string            `json:"event"`
+	PID       int               `json:"pid,omitempty"`
+	Process   string            `json:"process,omitempty"`
+	Primary   *DecisionResponse `json:"primary,omitempty"`
+	Shadow    *DecisionResponse `json:"shadow,omitempty"`
+	Error     string            `json:"error,omitempty"`
+	Stats     *ShadowStats      `json:"stats,omitempty"`
+}
+
+// ShadowReport accumulates agreement statistics and appends diverging
+// cases to a JSON-lines report file.
+type ShadowReport struct {
+	mu    sync.Mutex
+	file  *os.File
+	stats ShadowStats
+}
+
+// NewShadowReport opens (or creates) the report file. An empty path keeps
+// statistics in memory only.
+func NewShadowReport(filePath string) (*ShadowReport, error) {
+	r := &ShadowReport{stats: ShadowStats{Pairs: make(map[string]int)}}
+	if filePath == "" {
+		return r, nil
+	}
+	f, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("opening shadow report: %w", err)
+	}
+	r.file = f
+	return r, nil
+}
+
+// Record compares a primary and shadow decision for the same process.
+func (r *ShadowReport) Record(proc *monitor.ProcessInfo, primary, shadow *DecisionResponse) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.stats.Total++
+	if primary.Action == shadow.Action {
+		r.stats.Agreed++
+		return
+	}
+	r.stats.Disagreed++
+	r.stats.Pairs[fmt.Sprintf("%s->%s", primary.Action, shadow.Action)]++
+
+	r.write(shadowCase{
+		Timestamp: time.Now(),
+		Event:     "divergence",
+		PID:       proc.PID,
+		Process:   proc.Name,
+		Primary:   primary,
+		Shadow:    shadow,
+	})
+}
+
+// RecordError notes a failed shadow evaluation.
+func (r *ShadowReport) RecordError(proc *monitor.ProcessInfo, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.stats.Errors++
+	r.write(shadowCase{
+		Timestamp: time.Now(),
+		Event:     "shadow_error",
+		PID:       proc.PID,
+		Process:   proc.Name,
+		Error:     err.Error(),
+	})
+}
+
+// Stats returns a copy of the current statistics.
+func (r *ShadowReport) Stats() ShadowStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s := r.stats
+	s.Pairs = make(map[string]int, len(r.stats.Pairs))
+	for k, v := range r.stats.Pairs {
+		s.Pairs[k] = v
+	}
+	return s
+}
+
+// Close appends the final statistics to the report and closes it.
+func (r *ShadowReport) Close() error {
+	stats := r.Stats()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	r.write(shadowCase{Timestamp: time.Now(), Event: "summary", Stats: &stats})
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+func (r *ShadowReport) write(c shadowCase) {
+	if r.file == nil {
+		return
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return
+	}
+	r.file.Write(data)
+	r.file.Write([]byte("\n"))
+}
@@ -3,6 +3,7 @@ package ai
 import (
 	"crypto/sha256"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/iamgilwell/aura/internal/monitor"
@@ -20,30 +21,98 @@ const (
 
 // DecisionRequest contains the data sent to the AI for evaluation.
 type DecisionRequest struct {
-	Process      *monitor.ProcessInfo
-	SystemState  *monitor.SystemMetrics
-	ProcessList  []*monitor.ProcessInfo // top consumers for context
-	History      []*DecisionResponse    // recent decisions for context
+	Process     *monitor.ProcessInfo
+	SystemState *monitor.SystemMetrics
+	ProcessList []*monitor.ProcessInfo // top consumers for context
+	History     []*DecisionResponse    // recent decisions for context
 }
 
 // DecisionResponse is the AI's evaluation of a process.
 type DecisionResponse struct {
-	ProcessPID   int       `json:"process_pid"`
-	ProcessName  string    `json:"process_name"`
-	Action       Action    `json:"action"`
-	Confidence   float64   `json:"confidence"`
-	Reason       string    `json:"reason"`
-	RiskScore    float64   `json:"risk_score"`
-	SavingsWatt  float64   `json:"savings_watt"`
-	Timestamp    time.Time `json:"timestamp"`
-	FromCache    bool      `json:"from_cache"`
+	ProcessPID  int              `json:"process_pid"`
+	ProcessName string           `json:"process_name"`
+	ProcessUnit string           `json:"process_unit,omitempty"` // systemd unit owning the process
+	Action      Action           `json:"action"`
+	Confidence  float64          `json:"confidence"`
+	Reason      string           `json:"reason"`
+	RiskScore   float64          `json:"risk_score"`
+	SavingsWatt float64          `json:"savings_watt"`
+	Timestamp   time.Time        `json:"timestamp"`
+	FromCache   bool             `json:"from_cache"`
+	Source      string           `json:"source,omitempty"`   // "remote" or "local"
+	Features    *ProcessFeatures `json:"features,omitempty"` // inputs the decision was based on
+}
+
+// ProcessFeatures is the numeric view of a process used for training and
+// scoring local models. It is recorded with each decision in the audit log.
+type ProcessFeatures struct {
+	CPU         float64 `json:"cpu"`
+	Memory      float64 `json:"memory"`
+	MemoryMB    float64 `json:"memory_mb"`
+	IORead      int64   `json:"io_read"`
+	IOWrite     int64   `json:"io_write"`
+	AgeSeconds  float64 `json:"age_seconds"`
+	UID         int     `json:"uid"`
+	Category    string  `json:"category"`
+	CPUTrend    float64 `json:"cpu_trend"`
+	MemoryTrend float64 `json:"memory_trend"`
+}
+
+// NewProcessFeatures extracts features from a process.
+func NewProcessFeatures(proc *monitor.ProcessInfo) *ProcessFeatures {
+	var age float64
+	if !proc.StartTime.IsZero() {
+		age = time.Since(proc.StartTime).Seconds()
+	}
+	return &ProcessFeatures{
+		CPU:         proc.CPU,
+		Memory:      proc.Memory,
+		MemoryMB:    proc.MemoryMB,
+		IORead:      proc.IORead,
+		IOWrite:     proc.IOWrite,
+		AgeSeconds:  age,
+		UID:         proc.UID,
+		Category:    proc.Category.String(),
+		CPUTrend:    proc.CPUTrend,
+		MemoryTrend: proc.MemoryTrend,
+	}
+}
+
+// Vector returns the features as model inputs. Heavy-tailed values are
+// log-scaled so a single large process does not dominate training.
+func (f *ProcessFeatures) Vector() []float64 {
+	boolf := func(b bool) float64 {
+		if b {
+			return 1
+		}
+		return 0
+	}
+	return []float64{
+		f.CPU,
+		f.Memory,
+		math.Log1p(f.MemoryMB),
+		math.Log1p(float64(f.IORead)),
+		math.Log1p(float64(f.IOWrite)),
+		math.Log1p(f.AgeSeconds / 3600),
+		boolf(f.UID == 0),
+		boolf(f.Category == monitor.CategoryUser.String()),
+		boolf(f.Category == monitor.CategorySystem.String()),
+		f.CPUTrend,
+		f.MemoryTrend,
+	}
+}
+
+// FeatureNames labels the entries returned by ProcessFeatures.Vector.
+var FeatureNames = []string{
+	"cpu", "memory", "log_memory_mb", "log_io_read", "log_io_write",
+	"log_age_hours", "is_root", "is_user", "is_system", "cpu_trend", "memory_trend",
 }
 
 // ProcessSignature generates a cache key for a process based on its name and resource pattern.
 func ProcessSignature(proc *monitor.ProcessInfo) string {
 	// Bucket CPU and memory to avoid cache misses from tiny fluctuations
-	cpuBucket := int(proc.CPU / 5) * 5   // Round to nearest 5%
-	memBucket := int(proc.Memory / 5) * 5
+	cpuBucket := int(proc.CPU/5) * 5 // Round to nearest 5%
+	memBucket := int(proc.Memory/5) * 5
 
 	raw := fmt.Sprintf("%s|%s|%d|%d|%s",
 		proc.Name,
@@ -18,6 +18,7 @@ type Config struct {
 	Safety        SafetyConfig        `mapstructure:"safety"`
 	Notifications NotificationConfig  `mapstructure:"notifications"`
 	Power         PowerConfig         `mapstructure:"power"`
+	OOM           OOMConfig           `mapstructure:"oom"`
 }
 
 type AnthropicConfig struct {
@@ -40,6 +41,25 @@ type AIConfig struct {
 	CacheTTL           time.Duration `mapstructure:"cache_ttl"`
 	MaxRequestsPerMin  int     `mapstructure:"max_requests_per_min"`
 	Aggressiveness     int     `mapstructure:"aggressiveness"`
+
+	// Local scorer trained by 'aura train'; empty disables pre-screening
+	LocalModel          string  `mapstructure:"local_model"`
+	LocalKeepBelow      float64 `mapstructure:"local_keep_below"`
+	LocalTerminateAbove float64 `mapstructure:"local_terminate_above"`
+
+	Shadow ShadowConfig `mapstructure:"shadow"`
+}
+
+// ShadowConfig describes a second decision engine that is evaluated
+// alongside the primary one without ever acting.
+type ShadowConfig struct {
+	Enabled        bool          `mapstructure:"enabled"`
+	Model          string        `mapstructure:"model"`          // empty uses anthropic.model
+	Aggressiveness int           `mapstructure:"aggressiveness"` // 0 uses ai.aggressiveness
+	PromptFile     string        `mapstructure:"prompt_file"`    // replaces the system prompt
+	LocalModel     string        `mapstructure:"local_model"`    // evaluate a local scorer instead of the API
+	ReportFile     string        `mapstructure:"report_file"`
+	Timeout        time.Duration `mapstructure:"timeout"`
 }
 
 type SafetyConfig struct {
@@ -47,6 +67,159 @@ type SafetyConfig struct {
 	ProtectedProcs   []string `mapstructure:"protected_processes"`
 	NeverTerminate   []string `mapstructure:"never_terminate"`
 	TerminateTimeout time.Duration `mapstructure:"terminate_timeout"`
+
+	// What goes down with a terminated process: process, tree, group,
+	// session or cgroup. Policy rules can set their own scope
+	TerminationScope string `mapstructure:"termination_scope"`
+
+	// Signals sent to terminate a process, each optionally followed by a
+	// wait, e.g. "SIGINT 3s SIGTERM 10s SIGKILL". Empty sends SIGTERM, then
+	// SIGKILL after terminate_timeout. Policy rules can set their own
+	Escalation string `mapstructure:"escalation"`
+
+	// What terminating a process owned by a systemd service does: auto
+	// (stop the unit if Restart= would bring the process back), signal,
+	// stop, restart or kill (systemctl kill). Policy rules can set their own
+	UnitAction string `mapstructure:"unit_action"`
+
+	// What terminating a process running in a container does: signal (the
+	// process alone), stop or pause the container through the runtime API
+	// at container_socket. Policy rules can set their own
+	ContainerAction string `mapstructure:"container_action"`
+	ContainerSocket string `mapstructure:"container_socket"`
+
+	// Run the whole pipeline without touching processes; actions are
+	// audited as would_terminate, would_throttle, ...
+	DryRun bool `mapstructure:"dry_run"`
+
+	// Per category or policy tag and action (terminate, throttle, suspend):
+	// auto, notify, confirm or forbid. Cells override the consent_level preset
+	ConsentMatrix map[string]map[string]string `mapstructure:"consent_matrix"`
+
+	// Optional file of ordered allow/deny/confirm rules
+	PolicyFile string `mapstructure:"policy_file"`
+
+	// Pinned executables for protected names
+	Pins []PinConfig `mapstructure:"pins"`
+
+	// Maintenance windows: cron expressions that change the consent level
+	// and aggressiveness while they match, and that policy rules can be
+	// limited to by name
+	Schedules []ScheduleConfig `mapstructure:"schedules"`
+
+	// Executables or HTTP requests run before/after terminations, referenced
+	// by name from policy rules
+	Hooks []HookConfig `mapstructure:"hooks"`
+
+	// Tree protection: subtrees of these protected processes, and the
+	// session and terminal Aura runs in
+	ProtectDescendants []string `mapstructure:"protect_descendants"`
+	ProtectOwnSession  bool     `mapstructure:"protect_own_session"`
+
+	// Refuse or confirm terminations of processes holding locks, writable
+	// files or editor swap files
+	DataGuard DataGuardConfig `mapstructure:"data_guard"`
+
+	// Limits on automatic terminations; exceeding one switches to monitor-only
+	Budgets BudgetConfig `mapstructure:"budgets"`
+
+	// Recommendations needing consent wait here for aura approve/deny
+	PendingFile string        `mapstructure:"pending_file"`
+	PendingTTL  time.Duration `mapstructure:"pending_ttl"`
+
+	// Processes the user chose to keep or snooze, skipped before any AI call
+	SnoozeFile string `mapstructure:"snooze_file"`
+
+	// Restore records captured before terminations, for aura undo
+	RestoreDir       string        `mapstructure:"restore_dir"`
+	RestoreRetention time.Duration `mapstructure:"restore_retention"`
+
+	// Resource limits and OOM score adjustment throttles apply along with
+	// the lower priority; policy rules can set their own. The original
+	// values of limited processes are kept in limits_dir for aura unlimit
+	ThrottleLimits LimitsConfig `mapstructure:"throttle_limits"`
+	LimitsDir      string       `mapstructure:"limits_dir"`
+
+	// Outcome tracking: how long to watch for a terminated process coming
+	// back, and how long to leave a respawning program alone afterwards
+	RespawnWindow   time.Duration `mapstructure:"respawn_window"`
+	RespawnSuppress time.Duration `mapstructure:"respawn_suppress"`
+}
+
+// BudgetConfig limits automatic terminations. Zero disables a limit.
+type BudgetConfig struct {
+	MaxPerMinute int           `mapstructure:"max_per_minute"`
+	MaxPerHour   int           `mapstructure:"max_per_hour"`
+	MaxPerUser   int           `mapstructure:"max_per_user"`
+	MaxMemoryMB  float64       `mapstructure:"max_memory_mb"`
+	MemoryWindow time.Duration `mapstructure:"memory_window"`
+	MaxExeShare  float64       `mapstructure:"max_exe_share"`
+}
+
+// LimitsConfig holds resource limits for a process. Zero fields are left
+// alone; cpu_time is counted from when the limit is applied.
+type LimitsConfig struct {
+	AddressSpaceMB float64       `mapstructure:"address_space_mb"`
+	CPUTime        time.Duration `mapstructure:"cpu_time"`
+	OpenFiles      int           `mapstructure:"open_files"`
+	OOMScoreAdj    int           `mapstructure:"oom_score_adj"`
+}
+
+// OOMConfig configures the early-OOM responder, which terminates a process
+// when memory pressure reaches the kill level, before the kernel OOM killer
+// has to. It checks pressure every interval, more often than processes are
+// scanned, and waits cooldown after each termination.
+type OOMConfig struct {
+	Enabled    bool           `mapstructure:"enabled"`
+	Interval   time.Duration  `mapstructure:"interval"`
+	Warn       OOMLevelConfig `mapstructure:"warn"`
+	Kill       OOMLevelConfig `mapstructure:"kill"`
+	Cooldown   time.Duration  `mapstructure:"cooldown"`
+	Escalation string         `mapstructure:"escalation"` // empty uses safety.escalation
+}
+
+// OOMLevelConfig is a memory pressure level, reached when all tasks stalled
+// on memory for Pressure percent of the last 10 seconds (PSI full avg10) or
+// available memory falls to AvailablePercent. Zero disables a condition.
+type OOMLevelConfig struct {
+	Pressure         float64 `mapstructure:"pressure"`
+	AvailablePercent float64 `mapstructure:"available_percent"`
+}
+
+// PinConfig ties a protected process name to its executable path and,
+// optionally, the SHA-256 of the binary.
+type PinConfig struct {
+	Name   string `mapstructure:"name"`
+	Exe    string `mapstructure:"exe"`
+	SHA256 string `mapstructure:"sha256"`
+}
+
+// DataGuardConfig configures the data-loss check. Processes writing faster
+// than WriteRateKB to files they hold are not terminated.
+type DataGuardConfig struct {
+	Enabled     bool    `mapstructure:"enabled"`
+	WriteRateKB float64 `mapstructure:"write_rate_kb"`
+}
+
+// ScheduleConfig defines a maintenance window: the consent level and
+// aggressiveness in effect while its cron expression matches.
+type ScheduleConfig struct {
+	Name           string `mapstructure:"name"`
+	Cron           string `mapstructure:"cron"`     // minute hour day month weekday
+	Timezone       string `mapstructure:"timezone"` // IANA name, default local time
+	ConsentLevel   *int   `mapstructure:"consent_level"`
+	Aggressiveness int    `mapstructure:"aggressiveness"`
+}
+
+// HookConfig defines a termination hook. Exactly one of Exec and URL is set.
+type HookConfig struct {
+	Name    string        `mapstructure:"name"`
+	Stage   string        `mapstructure:"stage"` // pre or post
+	Exec    []string      `mapstructure:"exec"`
+	URL     string        `mapstructure:"url"`
+	Method  string        `mapstructure:"method"`
+	Timeout time.Duration `mapstructure:"timeout"`
+	Veto    bool          `mapstructure:"veto"`
 }
 
 type NotificationConfig struct {
@@ -78,6 +251,12 @@ func setDefaults() {
 	viper.SetDefault("ai.cache_ttl", "30m")
 	viper.SetDefault("ai.max_requests_per_min", 30)
 	viper.SetDefault("ai.aggressiveness", 5)
+	viper.SetDefault("ai.local_model", "")
+	viper.SetDefault("ai.local_keep_below", 0.2)
+	viper.SetDefault("ai.local_terminate_above", 0.95)
+	viper.SetDefault("ai.shadow.enabled", false)
+	viper.SetDefault("ai.shadow.report_file", "aura-shadow.jsonl")
+	viper.SetDefault("ai.shadow.timeout", "30s")
 
 	viper.SetDefault("safety.consent_level", 2)
 	viper.SetDefault("safety.protected_processes", []string{
@@ -89,6 +268,31 @@ func setDefaults() {
 		"systemd", "init", "kernel", "kthreadd",
 	})
 	viper.SetDefault("safety.terminate_timeout", "5s")
+	viper.SetDefault("safety.termination_scope", "process")
+	viper.SetDefault("safety.escalation", "")
+	viper.SetDefault("safety.unit_action", "auto")
+	viper.SetDefault("safety.container_action", "signal")
+	viper.SetDefault("safety.container_socket", "/var/run/docker.sock")
+	viper.SetDefault("safety.dry_run", false)
+	viper.SetDefault("safety.respawn_window", "10m")
+	viper.SetDefault("safety.respawn_suppress", "24h")
+	viper.SetDefault("safety.policy_file", "")
+	viper.SetDefault("safety.protect_descendants", []string{"sshd"})
+	viper.SetDefault("safety.protect_own_session", true)
+	viper.SetDefault("safety.data_guard.enabled", true)
+	viper.SetDefault("safety.data_guard.write_rate_kb", 256)
+	viper.SetDefault("safety.budgets.max_per_minute", 3)
+	viper.SetDefault("safety.budgets.max_per_hour", 20)
+	viper.SetDefault("safety.budgets.max_per_user", 10)
+	viper.SetDefault("safety.budgets.max_memory_mb", 8192)
+	viper.SetDefault("safety.budgets.memory_window", "1h")
+	viper.SetDefault("safety.budgets.max_exe_share", 0.5)
+	viper.SetDefault("safety.pending_file", "aura-pending.json")
+	viper.SetDefault("safety.pending_ttl", "1h")
+	viper.SetDefault("safety.snooze_file", "aura-snooze.json")
+	viper.SetDefault("safety.restore_dir", "aura-restore")
+	viper.SetDefault("safety.restore_retention", "168h")
+	viper.SetDefault("safety.limits_dir", "aura-limits")
 
 	viper.SetDefault("notifications.log_file", "aura.log")
 	viper.SetDefault("notifications.audit_file", "aura-audit.log")
@@ -99,6 +303,15 @@ func setDefaults() {
 	viper.SetDefault("power.memory_watt_per_mb", 0.001)
 	viper.SetDefault("power.disk_watt_per_mbps", 0.02)
 	viper.SetDefault("power.track_savings", true)
+
+	viper.SetDefault("oom.enabled", false)
+	viper.SetDefault("oom.interval", "500ms")
+	viper.SetDefault("oom.warn.pressure", 10.0)
+	viper.SetDefault("oom.warn.available_percent", 10.0)
+	viper.SetDefault("oom.kill.pressure", 40.0)
+	viper.SetDefault("oom.kill.available_percent", 4.0)
+	viper.SetDefault("oom.cooldown", "5s")
+	viper.SetDefault("oom.escalation", "SIGTERM 1s SIGKILL")
 }
 
 // Load reads configuration from file, environment, and defaults.
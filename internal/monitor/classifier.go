@@ -34,6 +34,7 @@ var knownSystemDaemons = map[string]bool{
 // Classifier categorizes processes.
 type Classifier struct {
 	protectedNames map[string]bool
+	pins           *PinSet
 }
 
 // NewClassifier creates a new process classifier.
@@ -45,8 +46,17 @@ func NewClassifier(protectedNames []string) *Classifier {
 	return &Classifier{protectedNames: pmap}
 }
 
-// Classify determines the ProcessCategory for a process.
+// SetPins enables verification of protected processes against pinned
+// executables. It must be called before the classifier is in use.
+func (c *Classifier) SetPins(pins *PinSet) {
+	c.pins = pins
+}
+
+// Classify determines the ProcessCategory for a process. Processes that
+// claim a pinned identity without matching it are flagged in p.Suspicious.
 func (c *Classifier) Classify(p *ProcessInfo) ProcessCategory {
+	p.Suspicious = ""
+
 	// Kernel threads: PID 1 or 2, or PPID is kthreadd (PID 2)
 	if p.PID <= 2 || p.PPid == 2 {
 		return CategoryKernel
@@ -57,6 +67,19 @@ func (c *Classifier) Classify(p *ProcessInfo) ProcessCategory {
 		return CategoryKernel
 	}
 
+	// Pinned executables: a verified pin is essential even if renamed, a
+	// spoofed name earns no protection
+	switch status, why := c.pins.Verify(p); status {
+	case PinVerified:
+		return CategoryEssential
+	case PinMismatch:
+		p.Suspicious = why
+		if p.UID == 0 {
+			return CategorySystem
+		}
+		return CategoryUser
+	}
+
 	// Essential / protected processes
 	if c.protectedNames[p.Name] {
 		return CategoryEssential
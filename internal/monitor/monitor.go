@@ -19,6 +19,7 @@ type ProcessMonitor struct {
 	historySize  int
 	metrics      *SystemMetrics
 	onUpdate     func([]*ProcessInfo, *SystemMetrics)
+	containers   func(id string) string // container names by ID
 
 	prevProcs map[int]*ProcessInfo // previous scan for delta calculation
 }
@@ -35,6 +36,19 @@ func NewProcessMonitor(scanInterval time.Duration, historySize int, protectedNam
 	}
 }
 
+// SetPins enables verification of protected processes against pinned
+// executables. It must be called before Start.
+func (m *ProcessMonitor) SetPins(pins *PinSet) {
+	m.classifier.SetPins(pins)
+}
+
+// SetContainerNames sets how processes running in containers get their
+// container names, typically by asking the container runtime. It must be
+// called before Start.
+func (m *ProcessMonitor) SetContainerNames(names func(id string) string) {
+	m.containers = names
+}
+
 // OnUpdate sets a callback invoked after each scan.
 func (m *ProcessMonitor) OnUpdate(fn func([]*ProcessInfo, *SystemMetrics)) {
 	m.mu.Lock()
@@ -78,6 +92,16 @@ func (m *ProcessMonitor) Processes() []*ProcessInfo {
 	return procs
 }
 
+// Snapshot performs two scans separated by sample so CPU deltas are
+// populated, and returns the resulting processes. It is intended for
+// one-shot commands that do not run the scanning loop.
+func (m *ProcessMonitor) Snapshot(sample time.Duration) []*ProcessInfo {
+	m.scan()
+	time.Sleep(sample)
+	m.scan()
+	return m.Processes()
+}
+
 // SystemMetrics returns the latest system metrics.
 func (m *ProcessMonitor) SystemMetrics() *SystemMetrics {
 	m.mu.RLock()
@@ -92,6 +116,7 @@ func (m *ProcessMonitor) scan() {
 	}
 
 	newProcs := make(map[int]*ProcessInfo)
+	sockets := readSocketTable()
 
 	for _, entry := range entries {
 		if !entry.IsDir() {
@@ -116,11 +141,20 @@ func (m *ProcessMonitor) scan() {
 			// Re-parse stat to get proper CPU delta
 			_ = proc.parseStat()
 
+			if elapsed := proc.lastScan.Sub(prev.lastScan).Seconds(); elapsed > 0 && proc.IOWrite >= prev.IOWrite {
+				proc.IOWriteRate = float64(proc.IOWrite-prev.IOWrite) / elapsed
+			}
+
 			// Calculate trends
 			proc.CPUTrend = proc.CPU - prev.CPU
 			proc.MemoryTrend = proc.Memory - prev.Memory
 		}
 
+		proc.parseSockets(sockets)
+		if proc.Container != "" && m.containers != nil {
+			proc.ContainerName = m.containers(proc.Container)
+		}
+
 		// Classify
 		proc.Category = m.classifier.Classify(proc)
 
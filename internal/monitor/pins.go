@@ -0,0 +1,141 @@
+package monitor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Pin ties a protected identity to an executable path and, optionally, the
+// SHA-256 of the binary. Either Name or Exe must be set.
+type Pin struct {
+	Name   string
+	Exe    string
+	SHA256 string
+}
+
+// PinStatus is the result of verifying a process against the pins.
+type PinStatus int
+
+const (
+	PinNone         PinStatus = iota // no pin applies to the process
+	PinVerified                      // the process matches a pin
+	PinMismatch                      // the process claims a pinned identity but does not match
+	PinUnverifiable                  // a pin applies but the executable cannot be read
+)
+
+type hashEntry struct {
+	size    int64
+	modTime time.Time
+	sum     string
+}
+
+// PinSet verifies processes against pinned executables.
+type PinSet struct {
+	pins []Pin
+
+	mu     sync.Mutex
+	hashes map[string]hashEntry // exe path → cached hash
+}
+
+// NewPinSet creates a pin set. Names are truncated to the 15 characters
+// the kernel keeps in /proc/[pid]/stat.
+func NewPinSet(pins []Pin) *PinSet {
+	s := &PinSet{hashes: make(map[string]hashEntry)}
+	for _, p := range pins {
+		if len(p.Name) > 15 {
+			p.Name = p.Name[:15]
+		}
+		p.SHA256 = strings.ToLower(p.SHA256)
+		s.pins = append(s.pins, p)
+	}
+	return s
+}
+
+// Verify checks p against the pins that apply to it, either by name or by
+// executable path. The returned string names the pin on success and
+// explains the problem otherwise.
+func (s *PinSet) Verify(p *ProcessInfo) (PinStatus, string) {
+	if s == nil {
+		return PinNone, ""
+	}
+
+	var candidates []Pin
+	for _, pin := range s.pins {
+		if (pin.Name != "" && pin.Name == p.Name) || (pin.Exe != "" && pin.Exe == p.Exe) {
+			candidates = append(candidates, pin)
+		}
+	}
+	if len(candidates) == 0 {
+		return PinNone, ""
+	}
+	if p.Exe == "" {
+		return PinUnverifiable, fmt.Sprintf("executable of '%s' (PID %d) is unreadable", p.Name, p.PID)
+	}
+
+	mismatch := ""
+	for _, pin := range candidates {
+		if pin.Exe != "" && pin.Exe != p.Exe {
+			mismatch = fmt.Sprintf("'%s' (PID %d) runs %s, pinned to %s", p.Name, p.PID, p.Exe, pin.Exe)
+			continue
+		}
+		if pin.SHA256 != "" {
+			sum, err := s.hash(p)
+			if err != nil {
+				return PinUnverifiable, fmt.Sprintf("hashing %s: %v", p.Exe, err)
+			}
+			if sum != pin.SHA256 {
+				mismatch = fmt.Sprintf("'%s' (PID %d) binary %s does not match its pinned hash", p.Name, p.PID, p.Exe)
+				continue
+			}
+		}
+		return PinVerified, pinLabel(pin)
+	}
+	return PinMismatch, mismatch
+}
+
+// hash returns the SHA-256 of the binary the process is running. Hashes are
+// cached per path until the file's size or modification time changes.
+func (s *PinSet) hash(p *ProcessInfo) (string, error) {
+	exe := fmt.Sprintf("/proc/%d/exe", p.PID)
+	info, err := os.Stat(exe)
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	cached, ok := s.hashes[p.Exe]
+	s.mu.Unlock()
+	if ok && cached.size == info.Size() && cached.modTime.Equal(info.ModTime()) {
+		return cached.sum, nil
+	}
+
+	f, err := os.Open(exe)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	sum := hex.EncodeToString(h.Sum(nil))
+
+	s.mu.Lock()
+	s.hashes[p.Exe] = hashEntry{size: info.Size(), modTime: info.ModTime(), sum: sum}
+	s.mu.Unlock()
+	return sum, nil
+}
+
+func pinLabel(pin Pin) string {
+	if pin.Name != "" {
+		return pin.Name
+	}
+	return pin.Exe
+}
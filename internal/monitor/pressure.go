@@ -0,0 +1,98 @@
+package monitor
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// MemoryPressure is the memory pressure of the whole system: how much of
+// the time tasks stalled waiting for memory, from /proc/pressure/memory,
+// and how much memory can still be allocated without swapping, from
+// /proc/meminfo.
+type MemoryPressure struct {
+	PSI  bool    // whether the kernel reports pressure stall information
+	Some float64 // percent of the last 10s at least one task stalled (avg10)
+	Full float64 // percent of the last 10s all tasks stalled at once (avg10)
+
+	AvailableMB float64 // MemAvailable
+	TotalMB     float64 // MemTotal
+}
+
+// AvailablePercent returns available memory as a percentage of the total,
+// or 100 if the total is unknown.
+func (p MemoryPressure) AvailablePercent() float64 {
+	if p.TotalMB <= 0 {
+		return 100
+	}
+	return p.AvailableMB / p.TotalMB * 100
+}
+
+// String describes the pressure, e.g. "full 12.5%, some 30.1%, 812 MB
+// (5.1%) available".
+func (p MemoryPressure) String() string {
+	s := fmt.Sprintf("%.0f MB (%.1f%%) available", p.AvailableMB, p.AvailablePercent())
+	if p.PSI {
+		s = fmt.Sprintf("full %.1f%%, some %.1f%%, %s", p.Full, p.Some, s)
+	}
+	return s
+}
+
+// ReadMemoryPressure reads the current memory pressure. Kernels without
+// pressure stall information (CONFIG_PSI, psi=1) report available memory
+// only.
+func ReadMemoryPressure() (MemoryPressure, error) {
+	meminfo, err := os.ReadFile("/proc/meminfo")
+	if err != nil {
+		return MemoryPressure{}, err
+	}
+	psi, _ := os.ReadFile("/proc/pressure/memory")
+	return ParseMemoryPressure(string(psi), string(meminfo)), nil
+}
+
+// ParseMemoryPressure parses the contents of /proc/pressure/memory, which
+// may be empty, and /proc/meminfo.
+func ParseMemoryPressure(psi, meminfo string) MemoryPressure {
+	var p MemoryPressure
+	for _, line := range strings.Split(psi, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		for _, f := range fields[1:] {
+			v, ok := strings.CutPrefix(f, "avg10=")
+			if !ok {
+				continue
+			}
+			avg, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				break
+			}
+			switch fields[0] {
+			case "some":
+				p.Some, p.PSI = avg, true
+			case "full":
+				p.Full, p.PSI = avg, true
+			}
+		}
+	}
+
+	for _, line := range strings.Split(meminfo, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		kb, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			continue
+		}
+		switch fields[0] {
+		case "MemTotal:":
+			p.TotalMB = kb / 1024.0
+		case "MemAvailable:":
+			p.AvailableMB = kb / 1024.0
+		}
+	}
+	return p
+}
@@ -46,13 +46,35 @@ type ProcessInfo struct {
 	IOWrite   int64
 	State     string
 	PPid      int
+	Pgrp      int
+	Session   int
+	TTY       int // controlling terminal device number, 0 if none
+	Nice      int
 	Cmdline   string
+	Exe       string // resolved /proc/[pid]/exe, empty if unreadable
+	Cgroup    string // unified (v2) or name=systemd cgroup path
+	Unit      string // systemd unit owning the cgroup, if any
 	StartTime time.Time
 	Category  ProcessCategory
 
+	// Container running the process, from its cgroup path; the name is only
+	// known when the monitor can ask the container runtime
+	Container     string // full ID
+	ContainerName string
+
+	// Sockets resolved from /proc/net via fd inodes (fds of other users'
+	// processes are only readable as root)
+	Listeners   []Socket
+	Connections []Socket // established TCP, connected UDP and unix
+
+	// Set by the classifier when the process claims a pinned identity
+	// without matching its executable or hash
+	Suspicious string
+
 	// Deltas tracked across scans
 	CPUTrend    float64
 	MemoryTrend float64
+	IOWriteRate float64 // write_bytes growth in bytes per second
 
 	// Raw jiffies for CPU calculation
 	prevUtime uint64
@@ -88,6 +110,12 @@ func parseProcessInfo(pid int) (*ProcessInfo, error) {
 	// Parse /proc/[pid]/cmdline
 	proc.parseCmdline()
 
+	// Resolve /proc/[pid]/exe (may fail without root)
+	proc.parseExe()
+
+	// Parse /proc/[pid]/cgroup
+	proc.parseCgroup()
+
 	// Parse /proc/[pid]/io (may fail without root)
 	proc.parseIO()
 
@@ -124,6 +152,10 @@ func (p *ProcessInfo) parseStat() error {
 
 	ppid, _ := strconv.Atoi(fields[1])
 	p.PPid = ppid
+	p.Pgrp, _ = strconv.Atoi(fields[2])
+	p.Session, _ = strconv.Atoi(fields[3])
+	p.TTY, _ = strconv.Atoi(fields[4])
+	p.Nice, _ = strconv.Atoi(fields[16])
 
 	utime, _ := strconv.ParseUint(fields[11], 10, 64)
 	stime, _ := strconv.ParseUint(fields[12], 10, 64)
@@ -200,6 +232,111 @@ func (p *ProcessInfo) parseCmdline() {
 	}
 }
 
+func (p *ProcessInfo) parseExe() {
+	exe, err := os.Readlink(fmt.Sprintf("/proc/%d/exe", p.PID))
+	if err != nil {
+		return
+	}
+	p.Exe = strings.TrimSuffix(exe, " (deleted)")
+}
+
+func (p *ProcessInfo) parseCgroup() {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/cgroup", p.PID))
+	if err != nil {
+		return
+	}
+	p.Cgroup = ParseCgroupPath(string(data))
+	p.Unit = UnitFromCgroup(p.Cgroup)
+	p.Container = ContainerFromCgroup(p.Cgroup)
+}
+
+// ParseCgroupPath returns the cgroup path from /proc/[pid]/cgroup content,
+// preferring the unified hierarchy and falling back to name=systemd.
+func ParseCgroupPath(content string) string {
+	var fallback string
+	for _, line := range strings.Split(content, "\n") {
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		if parts[0] == "0" && parts[1] == "" {
+			return parts[2]
+		}
+		if parts[1] == "name=systemd" {
+			fallback = parts[2]
+		}
+	}
+	return fallback
+}
+
+// UnitFromCgroup returns the innermost systemd unit (service or scope) in a
+// cgroup path, e.g. "nginx.service" for "/system.slice/nginx.service".
+func UnitFromCgroup(path string) string {
+	parts := strings.Split(path, "/")
+	for i := len(parts) - 1; i >= 0; i-- {
+		if strings.HasSuffix(parts[i], ".service") || strings.HasSuffix(parts[i], ".scope") {
+			return parts[i]
+		}
+	}
+	return ""
+}
+
+// containerPrefixes name the cgroups of containers run by Docker, Podman,
+// containerd and CRI-O under the systemd cgroup driver.
+var containerPrefixes = []string{"docker-", "libpod-", "cri-containerd-", "crio-"}
+
+// ContainerFromCgroup returns the ID of the container owning a cgroup path,
+// e.g. for "/system.slice/docker-<id>.scope" or "/docker/<id>" (cgroupfs
+// driver). The cgroups of conmon and other container monitors do not count.
+func ContainerFromCgroup(path string) string {
+	parts := strings.Split(path, "/")
+	for i := len(parts) - 1; i >= 0; i-- {
+		part := strings.TrimSuffix(parts[i], ".scope")
+		for _, prefix := range containerPrefixes {
+			if id, ok := strings.CutPrefix(part, prefix); ok && isContainerID(id) {
+				return id
+			}
+		}
+		if isContainerID(part) {
+			return part
+		}
+	}
+	return ""
+}
+
+// ContainerLabel returns the name of the container running p or, when its
+// name is unknown, the short form of its ID used by docker ps.
+func (p *ProcessInfo) ContainerLabel() string {
+	if p.ContainerName != "" || len(p.Container) < 12 {
+		return p.ContainerName
+	}
+	return p.Container[:12]
+}
+
+// isContainerID reports whether s is a full, 64 hex digit container ID.
+func isContainerID(s string) bool {
+	if len(s) != 64 {
+		return false
+	}
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// Identity returns a key that stays stable when the same program is
+// restarted under a new PID: the executable path and command line, or the
+// process name when the executable cannot be read.
+func (p *ProcessInfo) Identity() string {
+	exe := p.Exe
+	if exe == "" {
+		exe = p.Name
+	}
+	return exe + "\x00" + p.Cmdline
+}
+
 func (p *ProcessInfo) parseIO() {
 	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/io", p.PID))
 	if err != nil {
@@ -0,0 +1,198 @@
+package monitor
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Socket is a network or unix socket held by a process.
+type Socket struct {
+	Proto      string // tcp, tcp6, udp, udp6, unix
+	LocalPort  int
+	RemotePort int
+	Path       string // bound path of unix sockets
+}
+
+// String formats the socket for display, e.g. "tcp:5432" or "unix:/run/x.sock".
+func (s Socket) String() string {
+	if s.Proto == "unix" {
+		return "unix:" + s.Path
+	}
+	if s.RemotePort > 0 {
+		return fmt.Sprintf("%s:%d->%d", s.Proto, s.LocalPort, s.RemotePort)
+	}
+	return fmt.Sprintf("%s:%d", s.Proto, s.LocalPort)
+}
+
+// socketEntry is a socket from /proc/net keyed by inode.
+type socketEntry struct {
+	Socket
+	listening   bool
+	established bool
+}
+
+// socketTable maps socket inodes to sockets, read once per scan.
+type socketTable map[uint64]socketEntry
+
+const (
+	tcpEstablished = "01"
+	tcpListen      = "0A"
+	udpUnconnected = "07"
+
+	unixAcceptCon = 0x10000 // __SO_ACCEPTCON: a listening unix socket
+	unixConnected = "03"    // SS_CONNECTED
+)
+
+// readSocketTable parses /proc/net/{tcp,tcp6,udp,udp6,unix}.
+func readSocketTable() socketTable {
+	table := make(socketTable)
+	for _, proto := range []string{"tcp", "tcp6", "udp", "udp6"} {
+		data, err := os.ReadFile("/proc/net/" + proto)
+		if err != nil {
+			continue
+		}
+		parseInetSockets(table, proto, string(data))
+	}
+	if data, err := os.ReadFile("/proc/net/unix"); err == nil {
+		parseUnixSockets(table, string(data))
+	}
+	return table
+}
+
+// parseInetSockets parses a /proc/net/tcp-style table:
+//
+//	sl local_address rem_address st tx_queue:rx_queue tr:tm->when retrnsmt uid timeout inode
+func parseInetSockets(table socketTable, proto, content string) {
+	lines := strings.Split(content, "\n")
+	for _, line := range lines[1:] {
+		fields := strings.Fields(line)
+		if len(fields) < 10 {
+			continue
+		}
+		inode, err := strconv.ParseUint(fields[9], 10, 64)
+		if err != nil || inode == 0 {
+			continue
+		}
+		local, remote, state := hexPort(fields[1]), hexPort(fields[2]), fields[3]
+
+		e := socketEntry{Socket: Socket{Proto: proto, LocalPort: local}}
+		switch {
+		case strings.HasPrefix(proto, "tcp") && state == tcpListen:
+			e.listening = true
+		case strings.HasPrefix(proto, "udp") && state == udpUnconnected && remote == 0:
+			e.listening = true
+		case state == tcpEstablished:
+			e.established = true
+			e.RemotePort = remote
+		}
+		table[inode] = e
+	}
+}
+
+// parseUnixSockets parses /proc/net/unix:
+//
+//	Num RefCount Protocol Flags Type St Inode Path
+func parseUnixSockets(table socketTable, content string) {
+	lines := strings.Split(content, "\n")
+	for _, line := range lines[1:] {
+		fields := strings.Fields(line)
+		if len(fields) < 7 {
+			continue
+		}
+		inode, err := strconv.ParseUint(fields[6], 10, 64)
+		if err != nil || inode == 0 {
+			continue
+		}
+		flags, _ := strconv.ParseUint(fields[3], 16, 64)
+		e := socketEntry{Socket: Socket{Proto: "unix"}, listening: flags&unixAcceptCon != 0}
+		e.established = !e.listening && fields[5] == unixConnected
+		if len(fields) > 7 {
+			e.Path = fields[7]
+		}
+		table[inode] = e
+	}
+}
+
+// hexPort extracts the port from an "ADDR:PORT" hex address.
+func hexPort(addr string) int {
+	i := strings.LastIndexByte(addr, ':')
+	if i < 0 {
+		return 0
+	}
+	port, _ := strconv.ParseUint(addr[i+1:], 16, 16)
+	return int(port)
+}
+
+// parseSockets resolves the socket file descriptors of the process against
+// table. File descriptors of other users' processes are only readable as
+// root.
+func (p *ProcessInfo) parseSockets(table socketTable) {
+	p.Listeners, p.Connections = nil, nil
+
+	fdDir := fmt.Sprintf("/proc/%d/fd", p.PID)
+	entries, err := os.ReadDir(fdDir)
+	if err != nil {
+		return
+	}
+	seen := make(map[uint64]bool)
+	for _, e := range entries {
+		target, err := os.Readlink(fdDir + "/" + e.Name())
+		if err != nil || !strings.HasPrefix(target, "socket:[") {
+			continue
+		}
+		inode, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(target, "socket:["), "]"), 10, 64)
+		if err != nil || seen[inode] {
+			continue
+		}
+		seen[inode] = true
+
+		s, ok := table[inode]
+		switch {
+		case !ok:
+		case s.listening:
+			p.Listeners = append(p.Listeners, s.Socket)
+		case s.established:
+			p.Connections = append(p.Connections, s.Socket)
+		}
+	}
+}
+
+// ListensOn reports whether the process has a listener on one of ports.
+func (p *ProcessInfo) ListensOn(ports []int) bool {
+	for _, s := range p.Listeners {
+		for _, port := range ports {
+			if s.Proto != "unix" && s.LocalPort == port {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// NetConnections returns the number of established TCP and connected UDP
+// sockets, leaving out unix sockets, which nearly every desktop process
+// holds to D-Bus, X or Wayland.
+func (p *ProcessInfo) NetConnections() int {
+	n := 0
+	for _, s := range p.Connections {
+		if s.Proto != "unix" {
+			n++
+		}
+	}
+	return n
+}
+
+// ConnectedOn reports whether the process has an established connection
+// whose local or remote port is one of ports.
+func (p *ProcessInfo) ConnectedOn(ports []int) bool {
+	for _, s := range p.Connections {
+		for _, port := range ports {
+			if s.Proto != "unix" && (s.LocalPort == port || s.RemotePort == port) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -1,6 +1,9 @@
 package notification
 
 import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -8,26 +11,77 @@ import (
 	"time"
 
 	"github.com/iamgilwell/aura/internal/ai"
+	"github.com/iamgilwell/aura/internal/monitor"
+	"github.com/iamgilwell/aura/internal/process"
+	"github.com/iamgilwell/aura/internal/safety"
 )
 
 // AuditEntry is a single audit log entry.
 type AuditEntry struct {
-	Timestamp   time.Time          `json:"timestamp"`
-	Event       string             `json:"event"`
+	ID          string               `json:"id,omitempty"`
+	Ref         string               `json:"ref,omitempty"` // ID of a related earlier entry
+	Timestamp   time.Time            `json:"timestamp"`
+	Event       string               `json:"event"`
+	PID         int                  `json:"pid,omitempty"`
+	Process     string               `json:"process,omitempty"`
+	Reason      string               `json:"reason,omitempty"`
+	Rule        string               `json:"rule,omitempty"`        // policy rule that decided
+	Hook        string               `json:"hook,omitempty"`        // termination hook that ran
+	Scope       string               `json:"scope,omitempty"`       // termination scope beyond the process itself
+	Members     []int                `json:"members,omitempty"`     // PIDs taken down by a scoped termination
+	Exit        *TerminationExit     `json:"exit,omitempty"`        // how a terminated process went down
+	Unit        string               `json:"unit,omitempty"`        // systemd unit owning the process
+	UnitAction  string               `json:"unit_action,omitempty"` // systemctl stop, restart or kill used instead of signals
+	SavingsWatt float64              `json:"savings_watt,omitempty"`
+	Features    *ai.ProcessFeatures  `json:"features,omitempty"`
 	Decision    *ai.DecisionResponse `json:"decision,omitempty"`
-	Details     string             `json:"details,omitempty"`
+	Details     string               `json:"details,omitempty"`
+
+	Container       string `json:"container,omitempty"`        // name or short ID of the container running the process
+	ContainerAction string `json:"container_action,omitempty"` // stop or pause through the container runtime instead of signals
+}
+
+// TerminationExit records which signal of the escalation ladder a
+// terminated process exited after.
+type TerminationExit struct {
+	Signal  string   `json:"signal"`
+	Sent    []string `json:"sent"`
+	Seconds float64  `json:"seconds"`
+	Zombie  bool     `json:"zombie,omitempty"` // exited but not reaped by its parent
 }
 
 // Auditor writes an append-only audit trail.
 type Auditor struct {
 	mu   sync.Mutex
 	file *os.File
+
+	suspicious map[string]bool // processes already flagged
+	dryRun     bool
+}
+
+// dryRunEvents are the intervention events recorded under another name in
+// dry-run mode, so simulated actions never look like real ones.
+var dryRunEvents = map[string]string{
+	"termination":      "would_terminate",
+	"container_paused": "would_pause_container",
+	"throttled":        "would_throttle",
+	"suspended":        "would_suspend",
+	"limited":          "would_limit",
+	"resumed":          "would_resume",
+	"relaunched":       "would_relaunch",
+}
+
+// SetDryRun records terminations, container pauses, throttles,
+// suspensions, limits, resumes and relaunches as would_terminate,
+// would_pause_container, would_throttle, ... events.
+func (a *Auditor) SetDryRun(on bool) {
+	a.dryRun = on
 }
 
 // NewAuditor creates a new auditor.
 func NewAuditor(filePath string) (*Auditor, error) {
 	if filePath == "" {
-		return &Auditor{}, nil
+		return &Auditor{suspicious: make(map[string]bool)}, nil
 	}
 
 	f, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
@@ -35,7 +89,7 @@ func NewAuditor(filePath string) (*Auditor, error) {
 		return nil, fmt.Errorf("opening audit file: %w", err)
 	}
 
-	return &Auditor{file: f}, nil
+	return &Auditor{file: f, suspicious: make(map[string]bool)}, nil
 }
 
 // Close closes the audit file.
@@ -54,12 +108,209 @@ func (a *Auditor) LogDecision(decision *ai.DecisionResponse) {
 	})
 }
 
-// LogTermination records a process termination.
-func (a *Auditor) LogTermination(pid int, name string, reason string) {
+// LogTermination records a process termination and returns its entry ID.
+func (a *Auditor) LogTermination(proc *monitor.ProcessInfo, reason string, savingsWatt float64) string {
+	return a.LogScopedTermination(proc, TerminationDetails{Scope: "process"}, reason, savingsWatt)
+}
+
+// TerminationDetails describes how a termination was carried out.
+type TerminationDetails struct {
+	Scope      string           // process, tree, group, session, cgroup, unit or container
+	Members    []int            // PIDs taken down, for scopes beyond the process
+	UnitAction string           // stop, restart or kill, for the unit scope
+	Exit       *TerminationExit // nil when no signal made the process exit
+
+	ContainerAction string // stop or pause, for the container scope
+}
+
+// TerminationDetailsOf converts how result was carried out for the audit
+// trail.
+func TerminationDetailsOf(result *process.TerminationResult) TerminationDetails {
+	d := TerminationDetails{
+		Scope:      string(result.Scope),
+		Members:    result.PIDs(),
+		UnitAction: string(result.UnitAction),
+
+		ContainerAction: string(result.ContainerAction),
+	}
+	if e := result.Exit; e.Signal != 0 {
+		sent := make([]string, len(e.Sent))
+		for i, sig := range e.Sent {
+			sent[i] = safety.SignalName(sig)
+		}
+		d.Exit = &TerminationExit{
+			Signal:  safety.SignalName(e.Signal),
+			Sent:    sent,
+			Seconds: e.Elapsed.Seconds(),
+			Zombie:  e.Zombie,
+		}
+	}
+	return d
+}
+
+// LogScopedTermination records a termination that took down the members of
+// a tree, group, session, cgroup, unit or container along with proc as a
+// single entry, and returns its ID. savingsWatt covers all members.
+func (a *Auditor) LogScopedTermination(proc *monitor.ProcessInfo, d TerminationDetails, reason string, savingsWatt float64) string {
+	entry := AuditEntry{
+		Timestamp:   time.Now(),
+		Event:       "termination",
+		PID:         proc.PID,
+		Process:     proc.Name,
+		Reason:      reason,
+		SavingsWatt: savingsWatt,
+		Exit:        d.Exit,
+		Unit:        proc.Unit,
+		UnitAction:  d.UnitAction,
+		Features:    ai.NewProcessFeatures(proc),
+		Details:     fmt.Sprintf("pid=%d name=%s reason=%s", proc.PID, proc.Name, reason),
+	}
+	if d.Scope != "" && d.Scope != "process" {
+		entry.Scope = d.Scope
+		entry.Members = d.Members
+	}
+	if proc.Container != "" {
+		entry.Container, entry.ContainerAction = proc.ContainerLabel(), d.ContainerAction
+	}
+	return a.log(entry)
+}
+
+// LogContainerPause records pausing the container of proc, which freezes
+// the members without terminating them, and returns the entry ID.
+func (a *Auditor) LogContainerPause(proc *monitor.ProcessInfo, d TerminationDetails, reason string) string {
+	return a.log(AuditEntry{
+		Timestamp:       time.Now(),
+		Event:           "container_paused",
+		PID:             proc.PID,
+		Process:         proc.Name,
+		Reason:          reason,
+		Scope:           d.Scope,
+		Members:         d.Members,
+		Unit:            proc.Unit,
+		Container:       proc.ContainerLabel(),
+		ContainerAction: d.ContainerAction,
+		Details:         fmt.Sprintf("pid=%d name=%s reason=%s", proc.PID, proc.Name, reason),
+	})
+}
+
+// LogAction records a non-terminating intervention such as throttled,
+// suspended, limited or resumed, and returns its entry ID.
+func (a *Auditor) LogAction(event string, proc *monitor.ProcessInfo, reason string) string {
+	return a.log(AuditEntry{
+		Timestamp: time.Now(),
+		Event:     event,
+		PID:       proc.PID,
+		Process:   proc.Name,
+		Reason:    reason,
+		Unit:      proc.Unit,
+		Container: proc.ContainerLabel(),
+		Details:   fmt.Sprintf("pid=%d name=%s nice=%d state=%s", proc.PID, proc.Name, proc.Nice, proc.State),
+	})
+}
+
+// LogBlocked records an action that was refused by the safety system.
+// rule is the policy rule responsible, or empty for built-in protections.
+func (a *Auditor) LogBlocked(pid int, name, reason, rule string) {
+	a.log(AuditEntry{
+		Timestamp: time.Now(),
+		Event:     "blocked",
+		PID:       pid,
+		Process:   name,
+		Reason:    reason,
+		Rule:      rule,
+	})
+}
+
+// LogSuspicious records a process whose identity does not match its pin.
+// Each process is logged once; it reports whether an entry was written.
+func (a *Auditor) LogSuspicious(proc *monitor.ProcessInfo) bool {
+	key := fmt.Sprintf("%d/%d", proc.PID, proc.StartTime.Unix())
+	a.mu.Lock()
+	seen := a.suspicious[key]
+	a.suspicious[key] = true
+	a.mu.Unlock()
+	if seen {
+		return false
+	}
+
+	a.log(AuditEntry{
+		Timestamp: time.Now(),
+		Event:     "suspicious",
+		PID:       proc.PID,
+		Process:   proc.Name,
+		Reason:    proc.Suspicious,
+		Details:   fmt.Sprintf("exe=%s uid=%d cmdline=%q", proc.Exe, proc.UID, proc.Cmdline),
+	})
+	return true
+}
+
+// LogApproval records a change to the pending-approval queue. event is one
+// of approval_requested, approved, denied or approval_expired, and ref is
+// the pending item ID.
+func (a *Auditor) LogApproval(event, ref string, pid int, name, reason string) {
+	a.log(AuditEntry{
+		Timestamp: time.Now(),
+		Event:     event,
+		Ref:       ref,
+		PID:       pid,
+		Process:   name,
+		Reason:    reason,
+	})
+}
+
+// LogOutcome records what happened after the termination with entry ID ref.
+func (a *Auditor) LogOutcome(ref, name, outcome string, newPID int, after time.Duration) {
+	a.log(AuditEntry{
+		Timestamp: time.Now(),
+		Event:     "outcome",
+		Ref:       ref,
+		PID:       newPID,
+		Process:   name,
+		Reason:    outcome,
+		Details:   fmt.Sprintf("outcome=%s after=%s", outcome, after.Truncate(time.Second)),
+	})
+}
+
+// LogRelaunch records an undo of the termination with entry ID ref: the
+// relaunched PID, or the error that prevented the relaunch.
+func (a *Auditor) LogRelaunch(ref, name string, newPID int, err error) {
+	entry := AuditEntry{
+		Timestamp: time.Now(),
+		Event:     "relaunched",
+		Ref:       ref,
+		PID:       newPID,
+		Process:   name,
+	}
+	if err != nil {
+		entry.Event = "relaunch_failed"
+		entry.Reason = err.Error()
+	}
+	a.log(entry)
+}
+
+// LogHook records the result of a termination hook. stage is pre or post
+// and outcome is ok, failed or vetoed.
+func (a *Auditor) LogHook(stage, hook string, pid int, name, outcome, details string) {
+	a.log(AuditEntry{
+		Timestamp: time.Now(),
+		Event:     "hook",
+		Hook:      hook,
+		PID:       pid,
+		Process:   name,
+		Reason:    outcome,
+		Details:   fmt.Sprintf("stage=%s %s", stage, details),
+	})
+}
+
+// LogLimits records resource limits applied to a process or reverted;
+// event is limits_applied or limits_reverted.
+func (a *Auditor) LogLimits(event string, pid int, name, details string) {
 	a.log(AuditEntry{
 		Timestamp: time.Now(),
-		Event:     "termination",
-		Details:   fmt.Sprintf("pid=%d name=%s reason=%s", pid, name, reason),
+		Event:     event,
+		PID:       pid,
+		Process:   name,
+		Details:   details,
 	})
 }
 
@@ -72,9 +323,15 @@ func (a *Auditor) LogEvent(event, details string) {
 	})
 }
 
-func (a *Auditor) log(entry AuditEntry) {
+func (a *Auditor) log(entry AuditEntry) string {
+	if entry.ID == "" {
+		entry.ID = newEntryID()
+	}
+	if event, ok := dryRunEvents[entry.Event]; ok && a.dryRun {
+		entry.Event = event
+	}
 	if a.file == nil {
-		return
+		return entry.ID
 	}
 
 	a.mu.Lock()
@@ -82,9 +339,44 @@ func (a *Auditor) log(entry AuditEntry) {
 
 	data, err := json.Marshal(entry)
 	if err != nil {
-		return
+		return entry.ID
 	}
 
 	a.file.Write(data)
 	a.file.Write([]byte("\n"))
+	return entry.ID
+}
+
+// newEntryID returns a short random identifier for referencing entries.
+func newEntryID() string {
+	var b [4]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return fmt.Sprintf("%08x", time.Now().UnixNano()&0xffffffff)
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// ReadAudit loads audit entries recorded at or after since. Malformed
+// lines are skipped so a truncated write does not hide the rest of the log.
+func ReadAudit(filePath string, since time.Time) ([]AuditEntry, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("opening audit file: %w", err)
+	}
+	defer f.Close()
+
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		if entry.Timestamp.Before(since) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
 }
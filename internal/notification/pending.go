@@ -0,0 +1,35 @@
+package notification
+
+import (
+	"github.com/iamgilwell/aura/internal/ai"
+	"github.com/iamgilwell/aura/internal/monitor"
+	"github.com/iamgilwell/aura/internal/safety"
+)
+
+// ParkForApproval queues action on proc, recommended by decision, for
+// approval and audits the request. source names the mode that parked it.
+// It returns the stored item and whether it was newly added; an action
+// already waiting is neither queued nor audited again.
+func ParkForApproval(queue *safety.PendingQueue, auditor *Auditor, proc *monitor.ProcessInfo, action safety.Action,
+	decision *ai.DecisionResponse, source string) (safety.PendingItem, bool, error) {
+	item := safety.NewPendingItem(proc, action, decision.Reason)
+	item.Confidence = decision.Confidence
+	item.SavingsWatt = decision.SavingsWatt
+	item.Source = source
+
+	item, added, err := queue.Add(item)
+	if err == nil && added {
+		auditor.LogApproval("approval_requested", item.ID, item.PID, item.Name, item.Reason)
+	}
+	return item, added, err
+}
+
+// ExpirePending removes the approval requests that timed out and audits
+// them.
+func ExpirePending(queue *safety.PendingQueue, auditor *Auditor) []safety.PendingItem {
+	expired, _ := queue.Expire()
+	for _, it := range expired {
+		auditor.LogApproval("approval_expired", it.ID, it.PID, it.Name, it.Reason)
+	}
+	return expired
+}
@@ -0,0 +1,183 @@
+package notification
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/iamgilwell/aura/internal/ai"
+)
+
+// ProcessCount is a process name with the number of times it was seen.
+type ProcessCount struct {
+	Name  string
+	Count int
+}
+
+// Summary aggregates audit entries over a time window.
+type Summary struct {
+	Since time.Time
+	Until time.Time
+
+	Decisions         int
+	CachedDecisions   int
+	DecisionsByAction map[ai.Action]int
+	AvgConfidence     float64
+
+	Terminations    int
+	TotalSavedWatts float64
+	TopTerminated   []ProcessCount
+
+	// Dry-run terminations, kept apart from real ones
+	WouldTerminate int
+	WouldSaveWatts float64
+
+	Blocked        int
+	BlockedReasons []ProcessCount
+
+	Outcomes map[string]int
+
+	Suspicious []ProcessCount
+
+	Events map[string]int
+}
+
+// Summarize aggregates entries into a Summary for the given window.
+func Summarize(entries []AuditEntry, since, until time.Time) *Summary {
+	s := &Summary{
+		Since:             since,
+		Until:             until,
+		DecisionsByAction: make(map[ai.Action]int),
+		Events:            make(map[string]int),
+		Outcomes:          make(map[string]int),
+	}
+
+	terminated := make(map[string]int)
+	blocked := make(map[string]int)
+	suspicious := make(map[string]int)
+	var confidenceSum float64
+
+	for _, e := range entries {
+		s.Events[e.Event]++
+
+		switch e.Event {
+		case "ai_decision":
+			if e.Decision == nil {
+				continue
+			}
+			s.Decisions++
+			s.DecisionsByAction[e.Decision.Action]++
+			confidenceSum += e.Decision.Confidence
+			if e.Decision.FromCache {
+				s.CachedDecisions++
+			}
+		case "termination":
+			s.Terminations++
+			s.TotalSavedWatts += e.SavingsWatt
+			terminated[entryProcess(e)]++
+		case "would_terminate":
+			s.WouldTerminate++
+			s.WouldSaveWatts += e.SavingsWatt
+		case "blocked":
+			s.Blocked++
+			blocked[e.Reason]++
+		case "outcome":
+			s.Outcomes[e.Reason]++
+		case "suspicious":
+			suspicious[e.Process]++
+		}
+	}
+
+	if s.Decisions > 0 {
+		s.AvgConfidence = confidenceSum / float64(s.Decisions)
+	}
+	s.TopTerminated = topCounts(terminated, 10)
+	s.BlockedReasons = topCounts(blocked, 10)
+	s.Suspicious = topCounts(suspicious, 10)
+	return s
+}
+
+// entryProcess returns the process name of an entry, falling back to the
+// details string written by older versions of LogTermination.
+func entryProcess(e AuditEntry) string {
+	if e.Process != "" {
+		return e.Process
+	}
+	for _, field := range strings.Fields(e.Details) {
+		if strings.HasPrefix(field, "name=") {
+			return strings.TrimPrefix(field, "name=")
+		}
+	}
+	return "unknown"
+}
+
+func topCounts(counts map[string]int, n int) []ProcessCount {
+	result := make([]ProcessCount, 0, len(counts))
+	for name, c := range counts {
+		result = append(result, ProcessCount{Name: name, Count: c})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Name < result[j].Name
+	})
+	if len(result) > n {
+		result = result[:n]
+	}
+	return result
+}
+
+var summaryTemplate = template.Must(template.New("summary").Funcs(template.FuncMap{
+	"ts": func(t time.Time) string { return t.Format("2006-01-02 15:04") },
+}).Parse(`Aura session summary ({{ts .Since}} → {{ts .Until}})
+─────────────────────────────────────────────
+AI decisions:   {{.Decisions}} ({{.CachedDecisions}} from cache, avg confidence {{printf "%.2f" .AvgConfidence}})
+{{- range $action, $n := .DecisionsByAction}}
+  {{printf "%-10s" $action}} {{$n}}
+{{- end}}
+Terminations:   {{.Terminations}}
+Power saved:    {{printf "%.2f" .TotalSavedWatts}} W
+{{- if .WouldTerminate}}
+Dry run:        {{.WouldTerminate}} would terminate, {{printf "%.2f" .WouldSaveWatts}} W would be saved
+{{- end}}
+{{- if .TopTerminated}}
+Most terminated:
+{{- range .TopTerminated}}
+  {{printf "%-20s" .Name}} {{.Count}}
+{{- end}}
+{{- end}}
+{{- if .Outcomes}}
+Outcomes:
+{{- range $outcome, $n := .Outcomes}}
+  {{printf "%-20s" $outcome}} {{$n}}
+{{- end}}
+{{- end}}
+Blocked:        {{.Blocked}}
+{{- range .BlockedReasons}}
+  {{printf "%-40s" .Name}} {{.Count}}
+{{- end}}
+{{- if .Suspicious}}
+Suspicious processes:
+{{- range .Suspicious}}
+  {{printf "%-20s" .Name}} {{.Count}}
+{{- end}}
+{{- end}}
+`))
+
+// Render writes the summary using the local text template.
+func (s *Summary) Render(w io.Writer) error {
+	return summaryTemplate.Execute(w, s)
+}
+
+// String returns the rendered summary.
+func (s *Summary) String() string {
+	var sb strings.Builder
+	if err := s.Render(&sb); err != nil {
+		return fmt.Sprintf("rendering summary: %v", err)
+	}
+	return sb.String()
+}
@@ -0,0 +1,58 @@
+package notification
+
+import (
+	"strings"
+
+	"github.com/iamgilwell/aura/internal/ai"
+)
+
+// TrainingSamples derives labelled samples from audit entries for the
+// local scorer. Fresh remote decisions are labelled by their action; user
+// initiated terminations count as feedback that the process should go.
+// Termination outcomes override both: a process that was respawned or
+// restarted by the user should have been kept, one that stayed dead is
+// confirmed as a good termination.
+func TrainingSamples(entries []AuditEntry) []ai.TrainingSample {
+	outcomes := make(map[string]string)
+	for _, e := range entries {
+		if e.Event == "outcome" && e.Ref != "" {
+			outcomes[e.Ref] = e.Reason
+		}
+	}
+
+	var samples []ai.TrainingSample
+
+	for _, e := range entries {
+		switch e.Event {
+		case "ai_decision":
+			d := e.Decision
+			if d == nil || d.Features == nil || d.FromCache || d.Source == "local" || d.Confidence == 0 {
+				continue
+			}
+			label := 0.0
+			if d.Action == ai.ActionTerminate {
+				label = 1
+			}
+			samples = append(samples, ai.TrainingSample{Features: d.Features, Label: label})
+		case "termination":
+			if e.Features == nil {
+				continue
+			}
+			switch outcomes[e.ID] {
+			case "respawned", "user-restarted":
+				samples = append(samples, ai.TrainingSample{Features: e.Features, Label: 0})
+			case "stayed-dead":
+				samples = append(samples, ai.TrainingSample{Features: e.Features, Label: 1})
+			default:
+				if isUserInitiated(e.Reason) {
+					samples = append(samples, ai.TrainingSample{Features: e.Features, Label: 1})
+				}
+			}
+		}
+	}
+	return samples
+}
+
+func isUserInitiated(reason string) bool {
+	return reason == "manual termination" || strings.HasPrefix(reason, "ask:") || strings.HasPrefix(reason, "approved:")
+}
@@ -37,6 +37,16 @@ func (c *Calculator) EstimateSavings(proc *monitor.ProcessInfo) float64 {
 	return c.ProcessPower(proc)
 }
 
+// EstimateGroupSavings returns the estimated power savings in watts if all
+// of procs are terminated.
+func (c *Calculator) EstimateGroupSavings(procs []*monitor.ProcessInfo) float64 {
+	var total float64
+	for _, p := range procs {
+		total += c.ProcessPower(p)
+	}
+	return total
+}
+
 // MonthlykWh converts watts to monthly kWh.
 func MonthlykWh(watts float64) float64 {
 	hoursPerMonth := 24.0 * 30.0
@@ -11,6 +11,10 @@ type Metrics struct {
 	totalWattsSaved float64
 	savingsLog  []SavingsEntry
 	startTime   time.Time
+
+	// Dry run: savings are what terminations would have saved
+	dryRun          bool
+	totalWattsWould float64
 }
 
 // SavingsEntry records a single power savings event.
@@ -20,6 +24,7 @@ type SavingsEntry struct {
 	PID         int
 	WattsSaved  float64
 	Reason      string
+	DryRun      bool // would have been saved; the process was not terminated
 }
 
 // NewMetrics creates a new power metrics tracker.
@@ -29,18 +34,38 @@ func NewMetrics() *Metrics {
 	}
 }
 
+// SetDryRun makes RecordSaving count savings as what would have been saved,
+// reported by WouldSave, instead of TotalSaved.
+func (m *Metrics) SetDryRun(on bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.dryRun = on
+}
+
+// DryRun reports whether savings are recorded as dry-run savings.
+func (m *Metrics) DryRun() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.dryRun
+}
+
 // RecordSaving logs a power savings event.
 func (m *Metrics) RecordSaving(processName string, pid int, watts float64, reason string) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	m.totalWattsSaved += watts
+	if m.dryRun {
+		m.totalWattsWould += watts
+	} else {
+		m.totalWattsSaved += watts
+	}
 	m.savingsLog = append(m.savingsLog, SavingsEntry{
 		Timestamp:   time.Now(),
 		ProcessName: processName,
 		PID:         pid,
 		WattsSaved:  watts,
 		Reason:      reason,
+		DryRun:      m.dryRun,
 	})
 }
 
@@ -51,6 +76,14 @@ func (m *Metrics) TotalSaved() float64 {
 	return m.totalWattsSaved
 }
 
+// WouldSave returns the watts dry-run terminations would have saved this
+// session.
+func (m *Metrics) WouldSave() float64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.totalWattsWould
+}
+
 // SessionDuration returns how long the session has been running.
 func (m *Metrics) SessionDuration() time.Duration {
 	return time.Since(m.startTime)
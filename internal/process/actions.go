@@ -0,0 +1,88 @@
+package process
+
+import (
+	"fmt"
+	"syscall"
+
+	"github.com/iamgilwell/aura/internal/monitor"
+	"github.com/iamgilwell/aura/internal/safety"
+)
+
+// ThrottleNice is the nice value given to throttled processes.
+const ThrottleNice = 10
+
+// SafeThrottle lowers the CPU priority of a process after validating the
+// throttle with the safety manager, and applies the limits of the matching
+// policy rule or the throttle limits, if any. Processes that are already at
+// least as nice keep their priority.
+func (m *Manager) SafeThrottle(procInfo *monitor.ProcessInfo) error {
+	v := m.safetyMgr.EvaluateAction(procInfo, safety.ActionThrottle)
+	if !v.Allowed {
+		return fmt.Errorf("throttle blocked: %s", v.Reason)
+	}
+	if m.dryRun {
+		return nil
+	}
+	if procInfo.Nice < ThrottleNice {
+		if err := syscall.Setpriority(syscall.PRIO_PROCESS, procInfo.PID, ThrottleNice); err != nil {
+			return fmt.Errorf("renicing %d: %w", procInfo.PID, err)
+		}
+	}
+	l := m.limitsOf(v)
+	if l.CPUTime > 0 {
+		// A CPU time limit is a delayed termination; throttles only set it
+		// where terminating needs no consent
+		if t := m.safetyMgr.EvaluateAction(procInfo, safety.ActionTerminate); !t.Allowed || t.Confirm {
+			l.CPUTime = 0
+		}
+	}
+	if !l.IsZero() {
+		if _, err := m.applyLimits(procInfo, l); err != nil {
+			return fmt.Errorf("limiting %d: %w", procInfo.PID, err)
+		}
+	}
+	return nil
+}
+
+// SafeSuspend stops a process with SIGSTOP after validating the suspension
+// with the safety manager.
+func (m *Manager) SafeSuspend(procInfo *monitor.ProcessInfo) error {
+	if v := m.safetyMgr.EvaluateAction(procInfo, safety.ActionSuspend); !v.Allowed {
+		return fmt.Errorf("suspend blocked: %s", v.Reason)
+	}
+	if m.dryRun {
+		return nil
+	}
+	if err := syscall.Kill(procInfo.PID, syscall.SIGSTOP); err != nil {
+		return fmt.Errorf("sending SIGSTOP to %d: %w", procInfo.PID, err)
+	}
+	return nil
+}
+
+// Resume continues a suspended process. Undoing a suspension needs no
+// consent.
+func (m *Manager) Resume(pid int) error {
+	if m.dryRun {
+		return nil
+	}
+	if err := syscall.Kill(pid, syscall.SIGCONT); err != nil {
+		return fmt.Errorf("sending SIGCONT to %d: %w", pid, err)
+	}
+	return nil
+}
+
+// SafeApply performs action on a process through the matching Safe method.
+func (m *Manager) SafeApply(procInfo *monitor.ProcessInfo, action safety.Action) error {
+	switch action {
+	case safety.ActionThrottle:
+		return m.SafeThrottle(procInfo)
+	case safety.ActionSuspend:
+		return m.SafeSuspend(procInfo)
+	case safety.ActionLimit:
+		_, err := m.SafeLimit(procInfo, m.LimitsFor(procInfo))
+		return err
+	default:
+		_, err := m.SafeTerminate(procInfo, false)
+		return err
+	}
+}
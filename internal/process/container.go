@@ -0,0 +1,223 @@
+package process
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iamgilwell/aura/internal/monitor"
+	"github.com/iamgilwell/aura/internal/safety"
+)
+
+// containerTimeout bounds container runtime queries; stopping a container
+// may take as long as its stop timeout.
+const containerTimeout = 2 * time.Second
+
+// ContainerClient talks to a Docker-compatible engine API, Docker's or
+// Podman's, over its unix socket.
+type ContainerClient struct {
+	socket string
+	http   *http.Client
+
+	mu    sync.Mutex
+	names map[string]string // by container ID, "" if the lookup failed
+}
+
+// NewContainerClient creates a client for the engine API listening on the
+// unix socket at path, e.g. /var/run/docker.sock or
+// /run/podman/podman.sock.
+func NewContainerClient(path string) *ContainerClient {
+	return &ContainerClient{
+		socket: path,
+		http: &http.Client{Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", path)
+			},
+		}},
+		names: make(map[string]string),
+	}
+}
+
+// Container is a container as reported by its runtime.
+type Container struct {
+	ID     string
+	Name   string
+	Status string // created, running, paused, restarting, exited, ...
+}
+
+// do sends a request to the engine and returns the response body, or an
+// error carrying the engine's message unless the status is one of ok.
+func (c *ContainerClient) do(ctx context.Context, method, path string, ok ...int) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, "http://engine"+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("container runtime at %s: %w", c.socket, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(ok, resp.StatusCode) {
+		var e struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(body, &e) != nil || e.Message == "" {
+			e.Message = resp.Status
+		}
+		return nil, fmt.Errorf("%s %s: %s", method, path, e.Message)
+	}
+	return body, nil
+}
+
+// Inspect returns the container with the given ID or name.
+func (c *ContainerClient) Inspect(ctx context.Context, id string) (Container, error) {
+	body, err := c.do(ctx, http.MethodGet, "/containers/"+url.PathEscape(id)+"/json", http.StatusOK)
+	if err != nil {
+		return Container{}, err
+	}
+	var raw struct {
+		ID    string `json:"Id"`
+		Name  string `json:"Name"`
+		State struct {
+			Status string `json:"Status"`
+		} `json:"State"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return Container{}, fmt.Errorf("inspecting container %s: %w", id, err)
+	}
+	return Container{ID: raw.ID, Name: strings.TrimPrefix(raw.Name, "/"), Status: raw.State.Status}, nil
+}
+
+// Stop stops a container: the runtime sends its stop signal and, after
+// timeout, SIGKILL. Stopping a container that is not running succeeds.
+func (c *ContainerClient) Stop(ctx context.Context, id string, timeout time.Duration) error {
+	path := fmt.Sprintf("/containers/%s/stop?t=%d", url.PathEscape(id), int(timeout.Round(time.Second).Seconds()))
+	_, err := c.do(ctx, http.MethodPost, path, http.StatusNoContent, http.StatusNotModified)
+	return err
+}
+
+// Pause freezes every process of a container.
+func (c *ContainerClient) Pause(ctx context.Context, id string) error {
+	_, err := c.do(ctx, http.MethodPost, "/containers/"+url.PathEscape(id)+"/pause", http.StatusNoContent)
+	return err
+}
+
+// Name returns the name of a container, or "" if the runtime cannot tell.
+// Names are looked up once per container.
+func (c *ContainerClient) Name(id string) string {
+	c.mu.Lock()
+	name, ok := c.names[id]
+	c.mu.Unlock()
+	if ok {
+		return name
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), containerTimeout)
+	defer cancel()
+	if ctr, err := c.Inspect(ctx, id); err == nil {
+		name = ctr.Name
+	}
+	c.mu.Lock()
+	c.names[id] = name
+	c.mu.Unlock()
+	return name
+}
+
+// SetContainerClient enables container actions through the runtime behind
+// c; nil disables them.
+func (m *Manager) SetContainerClient(c *ContainerClient) {
+	m.containers = c
+}
+
+// SetContainerAction sets what terminations do to processes running in
+// containers unless the policy rule matching a process sets its own.
+func (m *Manager) SetContainerAction(a safety.ContainerAction) {
+	m.containerAction = a
+}
+
+// ContainerName returns the name of a container, or "" without a container
+// runtime; it suits monitor.ProcessMonitor.SetContainerNames.
+func (m *Manager) ContainerName(id string) string {
+	if m.containers == nil {
+		return ""
+	}
+	return m.containers.Name(id)
+}
+
+// ContainerPlan reports how a termination of proc would treat its container
+// under the matching policy rule and the configured container action.
+func (m *Manager) ContainerPlan(proc *monitor.ProcessInfo) (safety.ContainerAction, error) {
+	return m.containerPlan(proc, m.safetyMgr.Evaluate(proc), "")
+}
+
+// containerPlan decides how to terminate proc with respect to its container.
+// requested overrides the container action of the verdict and the manager;
+// the plan is ContainerSignal when the process is signalled directly.
+func (m *Manager) containerPlan(proc *monitor.ProcessInfo, verdict safety.Verdict, requested safety.ContainerAction) (safety.ContainerAction, error) {
+	action := requested
+	for _, a := range []safety.ContainerAction{verdict.ContainerAction, m.containerAction, safety.ContainerSignal} {
+		if action == "" {
+			action = a
+		}
+	}
+	switch {
+	case action == safety.ContainerSignal:
+		return action, nil
+	case proc.Container == "":
+		if requested != "" {
+			return "", fmt.Errorf("%s (PID %d) is not running in a container", proc.Name, proc.PID)
+		}
+		return safety.ContainerSignal, nil
+	case m.containers == nil:
+		return "", fmt.Errorf("cannot %s container %s of %s (PID %d): no container runtime socket configured", action, proc.ContainerLabel(), proc.Name, proc.PID)
+	}
+	return action, nil
+}
+
+// runContainer carries out a container action on the container of a
+// validated termination and, when stopping it, checks its processes went.
+// Cancelling stops waiting for the runtime, not the runtime itself.
+func (m *Manager) runContainer(t *Termination, result *TerminationResult, esc safety.Escalation) error {
+	id := t.Proc.Container
+	start := time.Now()
+	switch result.ContainerAction {
+	case safety.ContainerPause:
+		ctx, cancel := context.WithTimeout(t.ctx, containerTimeout)
+		defer cancel()
+		if err := m.containers.Pause(ctx, id); err != nil {
+			return err
+		}
+	default:
+		// The runtime has its own ladder: the container's stop signal,
+		// then SIGKILL once the ladder's waits are up
+		var wait time.Duration
+		for _, step := range esc {
+			wait += step.Wait
+		}
+		if wait == 0 {
+			wait = m.timeout
+		}
+		ctx, cancel := context.WithTimeout(t.ctx, wait+containerTimeout)
+		defer cancel()
+		if err := m.containers.Stop(ctx, id, wait); err != nil {
+			return err
+		}
+		if _, zombie := alive(result.PIDs()); zombie {
+			result.Zombie = true
+		}
+	}
+	result.Elapsed = time.Since(start)
+	return nil
+}
@@ -0,0 +1,161 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/iamgilwell/aura/internal/safety"
+)
+
+// Exit describes how a terminated process went down.
+type Exit struct {
+	Signal  syscall.Signal   // the signal it exited after; 0 if it was gone already
+	Sent    []syscall.Signal // every signal sent, in order
+	Elapsed time.Duration    // from the first signal until it exited
+	Zombie  bool             // it exited but its parent has not reaped it
+}
+
+// String describes the exit, e.g. "exited after SIGTERM in 1.2s".
+func (e Exit) String() string {
+	if e.Signal == 0 {
+		return "no signal needed"
+	}
+	s := fmt.Sprintf("exited after %s in %s", safety.SignalName(e.Signal), e.Elapsed.Round(100*time.Millisecond))
+	if e.Zombie {
+		s += ", left a zombie"
+	}
+	return s
+}
+
+// SetEscalation sets the escalation ladder used unless the policy rule
+// matching a process sets its own. nil sends SIGTERM, then SIGKILL after
+// the manager's timeout.
+func (m *Manager) SetEscalation(esc safety.Escalation) {
+	m.escalation = esc
+}
+
+// escalationOf returns the ladder for a termination decided by v. force
+// skips straight to SIGKILL.
+func (m *Manager) escalationOf(v safety.Verdict, force bool) safety.Escalation {
+	switch {
+	case force:
+		return safety.Escalation{{Signal: syscall.SIGKILL}}
+	case v.Escalation != nil:
+		return v.Escalation
+	case m.escalation != nil:
+		return m.escalation
+	default:
+		return safety.DefaultEscalation(m.timeout)
+	}
+}
+
+// terminate climbs esc for a single process.
+func (m *Manager) terminate(ctx context.Context, pid int, esc safety.Escalation, sent func(syscall.Signal)) (Exit, error) {
+	return m.escalate(ctx, strconv.Itoa(pid), esc, sent, func(sig syscall.Signal) error {
+		return syscall.Kill(pid, sig)
+	}, func() []int {
+		return []int{pid}
+	})
+}
+
+// escalate sends each signal of esc through signal, reporting it to sent if
+// set, and waits its rung for the processes listed by pids to exit or turn
+// into zombies. It fails if they outlast the ladder, which happens when it
+// does not end in SIGKILL, and stops climbing once ctx is cancelled.
+func (m *Manager) escalate(ctx context.Context, target string, esc safety.Escalation, sent func(syscall.Signal), signal func(syscall.Signal) error, pids func() []int) (Exit, error) {
+	var exit Exit
+	start := time.Now()
+	for i, step := range esc {
+		if ctx.Err() != nil {
+			return exit, cancelled(ctx, target, exit)
+		}
+		if err := signal(step.Signal); err != nil {
+			if i > 0 && errors.Is(err, syscall.ESRCH) {
+				// Exited between the last check and this signal
+				exit.Signal = exit.Sent[len(exit.Sent)-1]
+				exit.Elapsed = time.Since(start)
+				return exit, nil
+			}
+			return exit, fmt.Errorf("sending %s to %s: %w", safety.SignalName(step.Signal), target, err)
+		}
+		exit.Sent = append(exit.Sent, step.Signal)
+		if sent != nil {
+			sent(step.Signal)
+		}
+
+		wait := step.Wait
+		if wait == 0 && i == len(esc)-1 {
+			wait = m.timeout
+		}
+		deadline := time.Now().Add(wait)
+		for {
+			if running, zombie := alive(pids()); !running {
+				exit.Signal = step.Signal
+				exit.Elapsed = time.Since(start)
+				exit.Zombie = zombie
+				return exit, nil
+			}
+			if !time.Now().Before(deadline) {
+				break
+			}
+			select {
+			case <-ctx.Done():
+				return exit, cancelled(ctx, target, exit)
+			case <-time.After(100 * time.Millisecond):
+			}
+		}
+	}
+	return exit, fmt.Errorf("%s still running after %s", target, esc)
+}
+
+func cancelled(ctx context.Context, target string, exit Exit) error {
+	if len(exit.Sent) == 0 {
+		return fmt.Errorf("termination of %s cancelled before any signal: %w", target, ctx.Err())
+	}
+	return fmt.Errorf("termination of %s cancelled after %s: %w", target, safety.SignalName(exit.Sent[len(exit.Sent)-1]), ctx.Err())
+}
+
+// alive reports whether any of pids is still running, and whether any has
+// exited without being reaped.
+func alive(pids []int) (running, zombie bool) {
+	for _, pid := range pids {
+		switch procState(pid) {
+		case "":
+		case "Z", "X":
+			zombie = true
+		default:
+			running = true
+		}
+	}
+	return running, zombie
+}
+
+// procState returns the state letter of pid from /proc, or "" once it is
+// gone.
+func procState(pid int) string {
+	if f := statFields(pid); len(f) > 0 {
+		return f[0]
+	}
+	return ""
+}
+
+// statFields returns the fields of /proc/[pid]/stat after the name, starting
+// with the state, or nil once the process is gone.
+func statFields(pid int) []string {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return nil
+	}
+	// The fields follow the parenthesised name, which may contain spaces
+	content := string(data)
+	if i := strings.LastIndexByte(content, ')'); i >= 0 {
+		return strings.Fields(content[i+1:])
+	}
+	return nil
+}
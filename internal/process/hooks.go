@@ -0,0 +1,232 @@
+package process
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iamgilwell/aura/internal/monitor"
+)
+
+// HookStage is when a hook runs relative to the termination.
+type HookStage string
+
+const (
+	HookPre  HookStage = "pre"  // before the signal; may veto
+	HookPost HookStage = "post" // after the process is gone
+)
+
+// DefaultHookTimeout bounds hooks that do not set their own timeout.
+const DefaultHookTimeout = 10 * time.Second
+
+// maxHookOutput caps the hook output kept for the audit trail.
+const maxHookOutput = 512
+
+// Hook is an executable or HTTP request run around a termination. Policy
+// rules refer to hooks by name.
+//
+// Exec arguments and the URL may contain {pid}, {name}, {exe}, {user},
+// {unit} and {cgroup}; the same values are passed to executables as
+// AURA_PID, AURA_NAME, ... environment variables and to HTTP hooks as a JSON
+// body.
+type Hook struct {
+	Name    string
+	Stage   HookStage
+	Exec    []string
+	URL     string
+	Method  string // default POST
+	Timeout time.Duration
+	Veto    bool // a pre hook exiting non-zero or answering non-2xx blocks the termination
+}
+
+// HookResult is the outcome of running one hook.
+type HookResult struct {
+	Hook     string
+	Stage    HookStage
+	PID      int
+	Name     string
+	Vetoed   bool
+	Err      error // the hook could not run, timed out or failed
+	Output   string
+	Duration time.Duration
+}
+
+// Outcome summarizes the result for display and the audit trail.
+func (r HookResult) Outcome() string {
+	switch {
+	case r.Vetoed:
+		return "vetoed"
+	case r.Err != nil:
+		return "failed"
+	default:
+		return "ok"
+	}
+}
+
+// Validate checks that a hook has a name, a stage and exactly one action.
+func (h Hook) Validate() error {
+	if h.Name == "" {
+		return errors.New("hook name is required")
+	}
+	if h.Stage != HookPre && h.Stage != HookPost {
+		return fmt.Errorf("hook %q: stage must be pre or post, got %q", h.Name, h.Stage)
+	}
+	if (len(h.Exec) == 0) == (h.URL == "") {
+		return fmt.Errorf("hook %q: set exactly one of exec or url", h.Name)
+	}
+	if h.Veto && h.Stage != HookPre {
+		return fmt.Errorf("hook %q: only pre hooks can veto", h.Name)
+	}
+	return nil
+}
+
+// hookVars are the process attributes exposed to hooks.
+func hookVars(proc *monitor.ProcessInfo) map[string]string {
+	return map[string]string{
+		"pid":    strconv.Itoa(proc.PID),
+		"name":   proc.Name,
+		"exe":    proc.Exe,
+		"user":   proc.User,
+		"unit":   proc.Unit,
+		"cgroup": proc.Cgroup,
+	}
+}
+
+func expandHookVars(s string, vars map[string]string) string {
+	for k, v := range vars {
+		s = strings.ReplaceAll(s, "{"+k+"}", v)
+	}
+	return s
+}
+
+// Run executes the hook for proc and reports the result. Failures and
+// timeouts never veto; only a definite answer from a veto hook does.
+func (h Hook) Run(ctx context.Context, proc *monitor.ProcessInfo) HookResult {
+	timeout := h.Timeout
+	if timeout <= 0 {
+		timeout = DefaultHookTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	res := HookResult{Hook: h.Name, Stage: h.Stage, PID: proc.PID, Name: proc.Name}
+	vars := hookVars(proc)
+	var refused bool
+	if len(h.Exec) > 0 {
+		refused, res.Output, res.Err = h.runExec(ctx, vars)
+	} else {
+		refused, res.Output, res.Err = h.runHTTP(ctx, vars)
+	}
+	res.Duration = time.Since(start)
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		refused = false
+		res.Err = fmt.Errorf("timed out after %s", timeout)
+	}
+	res.Vetoed = h.Veto && refused
+	if len(res.Output) > maxHookOutput {
+		res.Output = res.Output[:maxHookOutput]
+	}
+	return res
+}
+
+// runExec runs the hook command. refused is set when it ran and exited
+// non-zero.
+func (h Hook) runExec(ctx context.Context, vars map[string]string) (refused bool, output string, err error) {
+	args := make([]string, len(h.Exec))
+	for i, a := range h.Exec {
+		args[i] = expandHookVars(a, vars)
+	}
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Env = os.Environ()
+	for k, v := range vars {
+		cmd.Env = append(cmd.Env, "AURA_"+strings.ToUpper(k)+"="+v)
+	}
+	out, err := cmd.CombinedOutput()
+	output = strings.TrimSpace(string(out))
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return true, output, fmt.Errorf("exited with status %d", exitErr.ExitCode())
+	}
+	return false, output, err
+}
+
+// runHTTP sends the hook request. refused is set on a non-2xx answer.
+func (h Hook) runHTTP(ctx context.Context, vars map[string]string) (refused bool, output string, err error) {
+	method := h.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+	body, _ := json.Marshal(map[string]string{
+		"stage": string(h.Stage), "hook": h.Name,
+		"pid": vars["pid"], "name": vars["name"], "exe": vars["exe"],
+		"user": vars["user"], "unit": vars["unit"], "cgroup": vars["cgroup"],
+	})
+	req, err := http.NewRequestWithContext(ctx, method, expandHookVars(h.URL, vars), bytes.NewReader(body))
+	if err != nil {
+		return false, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return false, "", err
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, maxHookOutput))
+	output = strings.TrimSpace(string(data))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return true, output, fmt.Errorf("HTTP %s", resp.Status)
+	}
+	return false, output, nil
+}
+
+// SetHooks installs the hooks that policy rules can refer to by name.
+func (m *Manager) SetHooks(hooks []Hook) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks = make(map[string]Hook, len(hooks))
+	for _, h := range hooks {
+		m.hooks[h.Name] = h
+	}
+}
+
+// OnHookResult registers fn to receive the result of every hook run, e.g.
+// to record it in the audit trail.
+func (m *Manager) OnHookResult(fn func(HookResult)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onHook = fn
+}
+
+// runHooks runs the named hooks of stage in order. It stops at and returns
+// the first veto.
+func (m *Manager) runHooks(ctx context.Context, stage HookStage, names []string, proc *monitor.ProcessInfo) *HookResult {
+	for _, name := range names {
+		m.mu.Lock()
+		h, ok := m.hooks[name]
+		report := m.onHook
+		m.mu.Unlock()
+		if !ok || h.Stage != stage {
+			continue
+		}
+
+		res := h.Run(ctx, proc)
+		if report != nil {
+			report(res)
+		}
+		if res.Vetoed {
+			return &res
+		}
+	}
+	return nil
+}
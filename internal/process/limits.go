@@ -0,0 +1,487 @@
+package process
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iamgilwell/aura/internal/monitor"
+	"github.com/iamgilwell/aura/internal/safety"
+)
+
+// The resources Aura limits, by their /proc/[pid]/limits name.
+const (
+	limitAddressSpace = "Max address space"
+	limitCPUTime      = "Max cpu time"
+	limitOpenFiles    = "Max open files"
+)
+
+// clkTck is the rate of the clock ticks /proc/[pid]/stat counts in.
+const clkTck = 100
+
+// LimitRecord holds the values a process had before Aura limited it, so
+// that they can be reverted, also by a later run of Aura.
+type LimitRecord struct {
+	PID     int           `json:"pid"`
+	Name    string        `json:"name"`
+	Started uint64        `json:"started"` // clock ticks after boot, telling the process from a later one with its PID
+	Applied safety.Limits `json:"applied"`
+
+	// Original values, resource limits by /proc/[pid]/limits name;
+	// OOMScoreAdj is nil unless Aura changed it
+	Limits      map[string]Limit `json:"limits,omitempty"`
+	OOMScoreAdj *int             `json:"oom_score_adj,omitempty"`
+
+	LimitedAt time.Time `json:"limited_at"`
+}
+
+// Running reports whether the limited process is still running, and not a
+// later process that reuses its PID.
+func (r *LimitRecord) Running() bool {
+	started, ok := startTicks(r.PID)
+	return ok && started == r.Started
+}
+
+// Originals describes the values the process had before it was limited,
+// e.g. "address space unlimited, oom_score_adj 0".
+func (r *LimitRecord) Originals() string {
+	names := make([]string, 0, len(r.Limits))
+	for name := range r.Limits {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var parts []string
+	for _, name := range names {
+		parts = append(parts, formatLimit(name, r.Limits[name].Soft))
+	}
+	if r.OOMScoreAdj != nil {
+		parts = append(parts, fmt.Sprintf("oom_score_adj %d", *r.OOMScoreAdj))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func formatLimit(name string, v uint64) string {
+	label := strings.TrimPrefix(name, "Max ")
+	switch {
+	case v == ^uint64(0):
+		return label + " unlimited"
+	case name == limitAddressSpace:
+		return fmt.Sprintf("%s %d MB", label, v>>20)
+	case name == limitCPUTime:
+		return fmt.Sprintf("%s %s", label, time.Duration(v)*time.Second)
+	}
+	return fmt.Sprintf("%s %d", label, v)
+}
+
+// LimitChange reports limits applied to or reverted on a process.
+type LimitChange struct {
+	Record   *LimitRecord
+	Reverted bool
+	Err      error // values that could not be changed
+}
+
+// LimitStore keeps limit records as one JSON file per limited process, so
+// limits outlive the Aura run that applied them. An empty directory keeps
+// the records in memory.
+type LimitStore struct {
+	dir string
+
+	mu   sync.Mutex
+	recs map[int]*LimitRecord // used when dir is empty
+}
+
+// NewLimitStore creates a store in dir.
+func NewLimitStore(dir string) *LimitStore {
+	return &LimitStore{dir: dir, recs: make(map[int]*LimitRecord)}
+}
+
+// Save writes rec, replacing any record for its PID.
+func (s *LimitStore) Save(rec *LimitRecord) error {
+	if s.dir == "" {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.recs[rec.PID] = rec
+		return nil
+	}
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return fmt.Errorf("creating limits directory: %w", err)
+	}
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding limit record: %w", err)
+	}
+	path := s.path(rec.PID)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("writing limit record: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// Load returns the record for pid, or nil if there is none.
+func (s *LimitStore) Load(pid int) (*LimitRecord, error) {
+	if s.dir == "" {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.recs[pid], nil
+	}
+	data, err := os.ReadFile(s.path(pid))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading limit record: %w", err)
+	}
+	var rec LimitRecord
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("parsing limit record of PID %d: %w", pid, err)
+	}
+	return &rec, nil
+}
+
+// Remove deletes the record for pid.
+func (s *LimitStore) Remove(pid int) error {
+	if s.dir == "" {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.recs, pid)
+		return nil
+	}
+	if err := os.Remove(s.path(pid)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// List returns the records of processes still running, most recently
+// limited first, and drops the others.
+func (s *LimitStore) List() ([]*LimitRecord, error) {
+	var pids []int
+	if s.dir == "" {
+		s.mu.Lock()
+		for pid := range s.recs {
+			pids = append(pids, pid)
+		}
+		s.mu.Unlock()
+	} else {
+		entries, err := os.ReadDir(s.dir)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("reading limits directory: %w", err)
+		}
+		for _, e := range entries {
+			name, ok := strings.CutSuffix(e.Name(), ".json")
+			if pid, err := strconv.Atoi(name); ok && err == nil && !e.IsDir() {
+				pids = append(pids, pid)
+			}
+		}
+	}
+
+	var recs []*LimitRecord
+	for _, pid := range pids {
+		rec, err := s.Load(pid)
+		if err != nil || rec == nil {
+			continue
+		}
+		if !rec.Running() {
+			_ = s.Remove(pid)
+			continue
+		}
+		recs = append(recs, rec)
+	}
+	sort.Slice(recs, func(i, j int) bool { return recs[i].LimitedAt.After(recs[j].LimitedAt) })
+	return recs, nil
+}
+
+func (s *LimitStore) path(pid int) string {
+	return filepath.Join(s.dir, strconv.Itoa(pid)+".json")
+}
+
+// SetLimitStore sets where the original values of limited processes are
+// kept.
+func (m *Manager) SetLimitStore(s *LimitStore) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.limits = s
+}
+
+// LimitStore returns the store of limited processes.
+func (m *Manager) LimitStore() *LimitStore {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.limits == nil {
+		m.limits = NewLimitStore("")
+	}
+	return m.limits
+}
+
+// SetThrottleLimits sets the limits throttles apply along with the lower
+// priority, unless the policy rule matching a process sets its own.
+func (m *Manager) SetThrottleLimits(l safety.Limits) {
+	m.throttleLimits = l
+}
+
+// OnLimits registers fn to receive every change of limits, e.g. to record
+// it in the audit trail.
+func (m *Manager) OnLimits(fn func(LimitChange)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onLimits = fn
+}
+
+// LimitsFor returns the limits the limit action and throttles apply to
+// proc: those of the matching policy rule, or the throttle limits.
+func (m *Manager) LimitsFor(proc *monitor.ProcessInfo) safety.Limits {
+	return m.limitsOf(m.safetyMgr.EvaluateAction(proc, safety.ActionLimit))
+}
+
+func (m *Manager) limitsOf(v safety.Verdict) safety.Limits {
+	if !v.Limits.IsZero() {
+		return v.Limits
+	}
+	return m.throttleLimits
+}
+
+// Limited returns the record of proc if Aura limited it, or nil.
+func (m *Manager) Limited(proc *monitor.ProcessInfo) *LimitRecord {
+	rec, err := m.LimitStore().Load(proc.PID)
+	if err != nil || rec == nil || !rec.Running() {
+		return nil
+	}
+	return rec
+}
+
+// LimitVerdict evaluates applying l to proc with the safety manager. A CPU
+// time limit ends in SIGXCPU, which kills the process, so it is a delayed
+// termination: the terminate action must be allowed too, with its data
+// guard, and its confirmation applies.
+func (m *Manager) LimitVerdict(proc *monitor.ProcessInfo, l safety.Limits) safety.Verdict {
+	v := m.safetyMgr.EvaluateAction(proc, safety.ActionLimit)
+	if !v.Allowed || l.CPUTime <= 0 {
+		return v
+	}
+	t := m.safetyMgr.EvaluateAction(proc, safety.ActionTerminate)
+	if !t.Allowed {
+		return safety.Verdict{Rule: t.Rule, Tags: t.Tags, Reason: "cpu_time ends in SIGXCPU: " + t.Reason}
+	}
+	v.Confirm = v.Confirm || t.Confirm
+	v.Notify = v.Notify || t.Notify
+	return v
+}
+
+// SafeLimit applies resource limits and an OOM score adjustment to a process
+// after validating them with LimitVerdict, recording the original values so
+// RevertLimits can restore them. Limiting a process again keeps the values
+// it had before the first time. An address space limit below what the
+// process already has mapped is refused: its next allocation would fail. In
+// dry run, the returned record only holds what would be applied.
+func (m *Manager) SafeLimit(procInfo *monitor.ProcessInfo, l safety.Limits) (*LimitRecord, error) {
+	if v := m.LimitVerdict(procInfo, l); !v.Allowed {
+		return nil, fmt.Errorf("limit blocked: %s", v.Reason)
+	}
+	if l.IsZero() {
+		return nil, fmt.Errorf("no limits to apply to %s (PID %d)", procInfo.Name, procInfo.PID)
+	}
+	if err := l.Validate(); err != nil {
+		return nil, err
+	}
+	if err := addressSpaceFits(procInfo, l.AddressSpaceMB); err != nil {
+		return nil, err
+	}
+	if m.dryRun {
+		return &LimitRecord{PID: procInfo.PID, Name: procInfo.Name, Applied: l}, nil
+	}
+	return m.applyLimits(procInfo, l)
+}
+
+// applyLimits lowers the soft limits and sets the OOM score adjustment of a
+// validated process, and saves what it changed. It goes on past values
+// that cannot be changed and reports them in the error.
+func (m *Manager) applyLimits(proc *monitor.ProcessInfo, l safety.Limits) (*LimitRecord, error) {
+	fields := statFields(proc.PID)
+	if len(fields) < 20 {
+		return nil, fmt.Errorf("%s (PID %d) has exited", proc.Name, proc.PID)
+	}
+	started, _ := strconv.ParseUint(fields[19], 10, 64)
+
+	store := m.LimitStore()
+	rec := m.Limited(proc)
+	if rec == nil || rec.Started != started {
+		rec = &LimitRecord{PID: proc.PID, Name: proc.Name, Started: started}
+	}
+	if rec.Limits == nil {
+		rec.Limits = make(map[string]Limit)
+	}
+
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/limits", proc.PID))
+	if err != nil {
+		return nil, fmt.Errorf("reading limits of %d: %w", proc.PID, err)
+	}
+	current := parseLimits(string(data))
+
+	var applied safety.Limits
+	var errs []error
+	set := func(name string, soft uint64) bool {
+		cur, ok := current[name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s of %d is unknown", strings.ToLower(name), proc.PID))
+			return false
+		}
+		// Limits are only lowered, and the hard limit stays so the soft
+		// one can be raised back
+		soft = min(soft, cur.Soft)
+		if err := setLimit(proc.PID, limitResources[name], Limit{Soft: soft, Hard: cur.Hard}); err != nil {
+			errs = append(errs, fmt.Errorf("setting %s of %d: %w", strings.ToLower(name), proc.PID, err))
+			return false
+		}
+		if _, ok := rec.Limits[name]; !ok {
+			rec.Limits[name] = cur
+		}
+		return true
+	}
+	if l.AddressSpaceMB > 0 {
+		if err := addressSpaceFits(proc, l.AddressSpaceMB); err != nil {
+			errs = append(errs, err)
+		} else if set(limitAddressSpace, uint64(l.AddressSpaceMB*(1<<20))) {
+			applied.AddressSpaceMB = l.AddressSpaceMB
+		}
+	}
+	if l.CPUTime > 0 {
+		// RLIMIT_CPU counts from process start; allow l.CPUTime more
+		utime, _ := strconv.ParseUint(fields[11], 10, 64)
+		stime, _ := strconv.ParseUint(fields[12], 10, 64)
+		if set(limitCPUTime, (utime+stime)/clkTck+uint64(l.CPUTime/time.Second)) {
+			applied.CPUTime = l.CPUTime
+		}
+	}
+	if l.OpenFiles > 0 && set(limitOpenFiles, uint64(l.OpenFiles)) {
+		applied.OpenFiles = l.OpenFiles
+	}
+	if l.OOMScoreAdj != 0 {
+		orig, err := readOOMScoreAdj(proc.PID)
+		if err == nil {
+			err = writeOOMScoreAdj(proc.PID, l.OOMScoreAdj)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("setting oom_score_adj of %d: %w", proc.PID, err))
+		} else {
+			if rec.OOMScoreAdj == nil {
+				rec.OOMScoreAdj = &orig
+			}
+			applied.OOMScoreAdj = l.OOMScoreAdj
+		}
+	}
+
+	err = errors.Join(errs...)
+	if applied.IsZero() {
+		return nil, err
+	}
+	rec.Applied = rec.Applied.Merge(applied)
+	rec.LimitedAt = time.Now()
+	if serr := store.Save(rec); serr != nil {
+		err = errors.Join(err, serr)
+	}
+	m.notifyLimits(LimitChange{Record: rec, Err: err})
+	return rec, err
+}
+
+// RevertLimits restores the values a process had before Aura limited it and
+// forgets them. Reverting needs no consent. Values that cannot be restored
+// are reported in the error; the record is dropped anyway. In dry run, the
+// record is kept and nothing is changed.
+func (m *Manager) RevertLimits(pid int) (*LimitRecord, error) {
+	store := m.LimitStore()
+	rec, err := store.Load(pid)
+	if err != nil {
+		return nil, err
+	}
+	if rec == nil {
+		return nil, fmt.Errorf("no limits recorded for PID %d", pid)
+	}
+	if !rec.Running() {
+		_ = store.Remove(pid)
+		return nil, fmt.Errorf("%s (PID %d) has exited", rec.Name, pid)
+	}
+	if m.dryRun {
+		return rec, nil
+	}
+
+	var errs []error
+	for name, l := range rec.Limits {
+		if err := setLimit(pid, limitResources[name], l); err != nil {
+			errs = append(errs, fmt.Errorf("restoring %s of %d: %w", strings.ToLower(name), pid, err))
+		}
+	}
+	if rec.OOMScoreAdj != nil {
+		if err := writeOOMScoreAdj(pid, *rec.OOMScoreAdj); err != nil {
+			errs = append(errs, fmt.Errorf("restoring oom_score_adj of %d: %w", pid, err))
+		}
+	}
+	err = errors.Join(errs...)
+	if rerr := store.Remove(pid); rerr != nil {
+		err = errors.Join(err, rerr)
+	}
+	m.notifyLimits(LimitChange{Record: rec, Reverted: true, Err: err})
+	return rec, err
+}
+
+func (m *Manager) notifyLimits(c LimitChange) {
+	m.mu.Lock()
+	fn := m.onLimits
+	m.mu.Unlock()
+	if fn != nil {
+		fn(c)
+	}
+}
+
+// startTicks returns the start time of pid in clock ticks after boot.
+func startTicks(pid int) (uint64, bool) {
+	fields := statFields(pid)
+	if len(fields) < 20 {
+		return 0, false
+	}
+	started, err := strconv.ParseUint(fields[19], 10, 64)
+	return started, err == nil
+}
+
+// addressSpaceFits checks that an address space limit of mb leaves room for
+// what proc has mapped already (VmSize); 0 always fits.
+func addressSpaceFits(proc *monitor.ProcessInfo, mb float64) error {
+	if mb <= 0 {
+		return nil
+	}
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/status", proc.PID))
+	if err != nil {
+		return fmt.Errorf("reading address space of %d: %w", proc.PID, err)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "VmSize:" {
+			continue
+		}
+		kb, _ := strconv.ParseFloat(fields[1], 64)
+		if used := kb / 1024; mb < used {
+			return fmt.Errorf("address space limit of %.0f MB is below the %.0f MB %s (PID %d) has mapped already", mb, used, proc.Name, proc.PID)
+		}
+	}
+	return nil
+}
+
+func readOOMScoreAdj(pid int) (int, error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/oom_score_adj", pid))
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(data)))
+}
+
+func writeOOMScoreAdj(pid, adj int) error {
+	return os.WriteFile(fmt.Sprintf("/proc/%d/oom_score_adj", pid), []byte(strconv.Itoa(adj)), 0)
+}
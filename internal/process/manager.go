@@ -1,8 +1,11 @@
 package process
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"os"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -14,6 +17,50 @@ import (
 type Manager struct {
 	safetyMgr *safety.Manager
 	timeout   time.Duration
+
+	treeRoots  map[string]bool
+	ownSession bool
+
+	// Default termination scope and the processes of the last scan, from
+	// which scope members are resolved
+	scope safety.Scope
+	procs []*monitor.ProcessInfo
+
+	// Default escalation ladder; nil sends SIGTERM, then SIGKILL after
+	// timeout
+	escalation safety.Escalation
+
+	// What terminations of unit-managed processes do, and how systemctl
+	// is run
+	unitAction safety.UnitAction
+	runner     Runner
+
+	// What terminations of processes in containers do, and the runtime
+	// that does it; nil signals them
+	containerAction safety.ContainerAction
+	containers      *ContainerClient
+
+	// Dry run: evaluate everything, touch nothing
+	dryRun bool
+
+	// Limits throttles apply unless a policy rule sets its own
+	throttleLimits safety.Limits
+
+	// Restore records for undo, captured before each termination
+	mu       sync.Mutex
+	restores *RestoreStore
+	captured map[int]*RestoreRecord
+
+	// Original values of the processes Aura limited
+	limits   *LimitStore
+	onLimits func(LimitChange)
+
+	// Terminations in flight, by target PID
+	inflight map[int]*Termination
+
+	// Termination hooks referenced by policy rules
+	hooks  map[string]Hook
+	onHook func(HookResult)
 }
 
 // NewManager creates a new process manager.
@@ -21,53 +68,200 @@ func NewManager(safetyMgr *safety.Manager, timeout time.Duration) *Manager {
 	return &Manager{
 		safetyMgr: safetyMgr,
 		timeout:   timeout,
+		runner:    ExecRunner{},
 	}
 }
 
-// Terminate sends SIGTERM to a process, then SIGKILL after timeout.
-func (m *Manager) Terminate(pid int, force bool) error {
-	proc, err := os.FindProcess(pid)
-	if err != nil {
-		return fmt.Errorf("finding process %d: %w", pid, err)
-	}
+// SetDryRun makes the manager evaluate interventions without carrying them
+// out: no signals are sent, no priorities changed, no hooks run and no
+// restore records kept. The Safe methods still return the safety errors a
+// real run would.
+func (m *Manager) SetDryRun(on bool) {
+	m.dryRun = on
+}
 
-	if force {
-		if err := proc.Signal(syscall.SIGKILL); err != nil {
-			return fmt.Errorf("sending SIGKILL to %d: %w", pid, err)
+// DryRun reports whether the manager is in dry-run mode.
+func (m *Manager) DryRun() bool {
+	return m.dryRun
+}
+
+// Terminate sends the manager's escalation ladder to a process, by default
+// SIGTERM then SIGKILL after timeout, or SIGKILL alone if force is set. In
+// dry run it only checks that the process exists.
+func (m *Manager) Terminate(pid int, force bool) error {
+	if m.dryRun {
+		if err := syscall.Kill(pid, 0); err == syscall.ESRCH {
+			return fmt.Errorf("finding process %d: %w", pid, err)
 		}
 		return nil
 	}
+	_, err := m.terminate(context.Background(), pid, m.escalationOf(safety.Verdict{}, force), nil)
+	return err
+}
+
+// SafeTerminate validates with safety manager before terminating. The scope
+// of the policy rule matching procInfo, or else the manager's scope, decides
+// which other processes go with it; every member is validated first and
+// none is signalled if any is protected. When a restore store is set, the
+// target is captured first; call SaveRestore with the audit ID of the
+// termination afterwards. Hooks of the matching policy rule run before (and
+// may veto) and after the termination; a pre hook that stops the process
+// itself, e.g. "docker stop", makes the signals unnecessary. The signals
+// climb the escalation ladder of the rule, or else the manager's, and the
+// result reports which one the target exited after. SafeTerminate blocks
+// until the process is gone; StartTerminate runs the same termination in
+// the background.
+func (m *Manager) SafeTerminate(procInfo *monitor.ProcessInfo, force bool) (*TerminationResult, error) {
+	t, err := m.StartTerminate(context.Background(), procInfo, force)
+	if err != nil {
+		return nil, err
+	}
+	return t.Wait()
+}
+
+// override replaces the configured unit or container action, or the
+// escalation ladder, for one termination.
+type override struct {
+	unit      safety.UnitAction
+	container safety.ContainerAction
+	esc       safety.Escalation
+}
 
-	// Send SIGTERM first
-	if err := proc.Signal(syscall.SIGTERM); err != nil {
-		return fmt.Errorf("sending SIGTERM to %d: %w", pid, err)
+// plan is a validated termination waiting to be carried out.
+type plan struct {
+	verdict safety.Verdict
+	esc     safety.Escalation
+	result  *TerminationResult
+}
+
+// validate checks procInfo and the other members of its termination scope,
+// or of its container or unit if the termination goes through the container
+// runtime or systemctl, against the safety rules. The container action comes
+// first: the unit of a container process is only the runtime's scope.
+func (m *Manager) validate(procInfo *monitor.ProcessInfo, force bool, o override) (*plan, error) {
+	verdict := m.safetyMgr.Evaluate(procInfo)
+	if !verdict.Allowed {
+		return nil, fmt.Errorf("termination blocked: %s", verdict.Reason)
+	}
+	esc := m.escalationOf(verdict, force)
+	if o.esc != nil && !force {
+		esc = o.esc
 	}
 
-	// Wait for process to exit, then SIGKILL if still alive
-	done := make(chan struct{})
-	go func() {
-		defer close(done)
-		for i := 0; i < int(m.timeout.Seconds()*10); i++ {
-			if err := proc.Signal(syscall.Signal(0)); err != nil {
-				return // Process is gone
-			}
-			time.Sleep(100 * time.Millisecond)
+	container, err := m.containerPlan(procInfo, verdict, o.container)
+	if err != nil {
+		return nil, err
+	}
+	action, unit := safety.UnitSignal, Unit{}
+	if container == safety.ContainerSignal {
+		if action, unit, err = m.unitPlan(procInfo, verdict, o.unit, esc); err != nil {
+			return nil, err
+		}
+	}
+	scope := m.scopeOf(verdict)
+	switch {
+	case container != safety.ContainerSignal:
+		scope = safety.ScopeContainer
+	case action != safety.UnitSignal:
+		scope = safety.ScopeUnit
+	}
+	members, err := m.Members(procInfo, scope)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range members[:len(members)-1] {
+		v := m.safetyMgr.Evaluate(p)
+		if !v.Allowed {
+			return nil, fmt.Errorf("termination blocked: %s scope includes %s (PID %d): %s", scope, p.Name, p.PID, v.Reason)
+		}
+		// Terminating the target takes the consent every member needs
+		if v.Confirm && !verdict.Confirm {
+			verdict.Confirm = true
+			verdict.Reason = fmt.Sprintf("%s scope includes %s (PID %d): %s", scope, p.Name, p.PID, v.Reason)
 		}
-		// Process still alive — force kill
-		_ = proc.Signal(syscall.SIGKILL)
-	}()
+		verdict.Notify = verdict.Notify || v.Notify
+	}
+	result := &TerminationResult{Scope: scope, Members: members}
+	if action != safety.UnitSignal {
+		result.Unit, result.UnitAction = unit, action
+	}
+	if container != safety.ContainerSignal {
+		result.ContainerAction = container
+	}
+	return &plan{verdict: verdict, esc: esc, result: result}, nil
+}
 
-	<-done
-	return nil
+// TerminationVerdict returns the verdict on terminating procInfo as
+// StartTerminate would carry it out: blocked if any member of its scope is,
+// and needing confirmation or a notification if any member does. Callers
+// asking for consent use it rather than the verdict on procInfo alone.
+func (m *Manager) TerminationVerdict(procInfo *monitor.ProcessInfo) safety.Verdict {
+	return m.terminationVerdict(procInfo, override{})
+}
+
+func (m *Manager) terminationVerdict(procInfo *monitor.ProcessInfo, o override) safety.Verdict {
+	p, err := m.validate(procInfo, false, o)
+	if err != nil {
+		v := m.safetyMgr.Evaluate(procInfo)
+		return safety.Verdict{Rule: v.Rule, Tags: v.Tags, Reason: strings.TrimPrefix(err.Error(), "termination blocked: ")}
+	}
+	return p.verdict
 }
 
-// SafeTerminate validates with safety manager before terminating.
-func (m *Manager) SafeTerminate(procInfo *monitor.ProcessInfo, force bool) error {
-	allowed, reason := m.safetyMgr.ValidateTermination(procInfo)
-	if !allowed {
-		return fmt.Errorf("termination blocked: %s", reason)
+// run carries out a validated termination, reporting progress to t.
+func (m *Manager) run(t *Termination, p *plan, force bool) (*TerminationResult, error) {
+	procInfo, result := t.Proc, p.result
+	if m.dryRun {
+		t.act()
+		return result, m.Terminate(procInfo.PID, force)
+	}
+	if result.UnitAction == "" && result.ContainerAction == "" {
+		// Relaunching a unit's or container's process outside of it would
+		// not undo the termination
+		m.capture(procInfo)
+	}
+
+	if veto := m.runHooks(t.ctx, HookPre, p.verdict.Hooks, procInfo); veto != nil {
+		reason := veto.Err.Error()
+		if veto.Output != "" {
+			reason += ": " + veto.Output
+		}
+		return nil, fmt.Errorf("termination vetoed by hook '%s': %s", veto.Hook, reason)
+	}
+	if err := t.ctx.Err(); err != nil {
+		return nil, fmt.Errorf("termination of %s (PID %d) cancelled: %w", procInfo.Name, procInfo.PID, err)
+	}
+	t.act()
+
+	sent := func(sig syscall.Signal) {
+		t.report(Progress{Stage: StageSignalled, Signal: sig})
 	}
-	return m.Terminate(procInfo.PID, force)
+	switch scope := result.Scope; {
+	case result.ContainerAction != "":
+		if err := m.runContainer(t, result, p.esc); err != nil {
+			return nil, err
+		}
+	case result.UnitAction != "":
+		if err := m.runUnit(t, result, p.esc); err != nil {
+			return nil, err
+		}
+	case scope != safety.ScopeProcess:
+		exit, err := m.terminateScope(t.ctx, procInfo, scope, result.Members, p.esc, sent)
+		if err != nil && !errors.Is(err, syscall.ESRCH) {
+			return nil, err
+		}
+		result.Exit = exit
+	case syscall.Kill(procInfo.PID, 0) != syscall.ESRCH:
+		exit, err := m.terminate(t.ctx, procInfo.PID, p.esc, sent)
+		if err != nil {
+			return nil, err
+		}
+		result.Exit = exit
+	}
+
+	// The process is gone; post hooks run even if the caller gave up
+	m.runHooks(context.WithoutCancel(t.ctx), HookPost, p.verdict.Hooks, procInfo)
+	return result, nil
 }
 
 // Children returns child PIDs of the given PID (from current /proc data).
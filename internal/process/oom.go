@@ -0,0 +1,278 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iamgilwell/aura/internal/monitor"
+	"github.com/iamgilwell/aura/internal/safety"
+)
+
+// OOMLevel is how close the system is to running out of memory.
+type OOMLevel int
+
+const (
+	OOMNormal OOMLevel = iota
+	OOMWarn            // pressure worth reporting
+	OOMKill            // a process is terminated to relieve it
+)
+
+func (l OOMLevel) String() string {
+	switch l {
+	case OOMWarn:
+		return "warn"
+	case OOMKill:
+		return "kill"
+	default:
+		return "normal"
+	}
+}
+
+// OOMThreshold is a memory pressure level, reached when all tasks stalled
+// on memory for at least Pressure percent of the last 10 seconds, or when
+// available memory falls to AvailablePercent of the total. Zero disables
+// either condition.
+type OOMThreshold struct {
+	Pressure         float64
+	AvailablePercent float64
+}
+
+// Reached reports whether p is at or beyond the threshold. The stall
+// condition is ignored on kernels without pressure stall information.
+func (t OOMThreshold) Reached(p monitor.MemoryPressure) bool {
+	if t.Pressure > 0 && p.PSI && p.Full >= t.Pressure {
+		return true
+	}
+	return t.AvailablePercent > 0 && p.TotalMB > 0 && p.AvailablePercent() <= t.AvailablePercent
+}
+
+// Victim is a process the OOM responder may terminate.
+type Victim struct {
+	Proc     *monitor.ProcessInfo
+	OOMScore int     // the kernel's badness, /proc/[pid]/oom_score
+	Rank     float64 // OOMScore plus recent memory growth
+}
+
+// OOMEvent reports a change of pressure level, or a termination the
+// responder started or failed to start.
+type OOMEvent struct {
+	Level    OOMLevel
+	Pressure monitor.MemoryPressure
+
+	Victim      *Victim      // nil for level changes
+	Termination *Termination // nil if none was started
+	Err         error
+}
+
+// OOMResponder terminates processes before the kernel OOM killer has to.
+// It checks memory pressure more often than processes are scanned and, at
+// the kill level, picks the process the kernel would pick among those the
+// safety rules let it terminate without asking, preferring ones still
+// growing. Victims are signalled directly, never through systemctl or a
+// container runtime, so their memory is freed as fast as possible.
+type OOMResponder struct {
+	mgr        *Manager
+	warn, kill OOMThreshold
+	procs      func() []*monitor.ProcessInfo
+
+	cooldown time.Duration
+	esc      safety.Escalation
+	skip     func(*monitor.ProcessInfo) (bool, string)
+	onEvent  func(OOMEvent)
+
+	mu       sync.Mutex
+	level    OOMLevel
+	lastKill time.Time
+}
+
+// NewOOMResponder creates a responder for the warn and kill thresholds,
+// choosing victims among the processes returned by procs, typically those
+// of the latest scan.
+func NewOOMResponder(mgr *Manager, warn, kill OOMThreshold, procs func() []*monitor.ProcessInfo) *OOMResponder {
+	return &OOMResponder{mgr: mgr, warn: warn, kill: kill, procs: procs}
+}
+
+// SetCooldown sets how long to wait after a termination before starting
+// another, giving the kernel time to reclaim the memory and the pressure
+// averages time to fall.
+func (r *OOMResponder) SetCooldown(d time.Duration) {
+	r.cooldown = d
+}
+
+// SetEscalation sets the ladder victims are terminated with; nil uses the
+// policy or configured one.
+func (r *OOMResponder) SetEscalation(esc safety.Escalation) {
+	r.esc = esc
+}
+
+// SetSkip sets a check excluding processes from being victims, such as
+// snoozed ones or programs known to respawn, returning why.
+func (r *OOMResponder) SetSkip(fn func(*monitor.ProcessInfo) (bool, string)) {
+	r.skip = fn
+}
+
+// OnEvent sets a callback invoked on level changes and terminations.
+func (r *OOMResponder) OnEvent(fn func(OOMEvent)) {
+	r.onEvent = fn
+}
+
+// Level returns the pressure level of the last check.
+func (r *OOMResponder) Level() OOMLevel {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.level
+}
+
+// LevelOf returns the pressure level p is at.
+func (r *OOMResponder) LevelOf(p monitor.MemoryPressure) OOMLevel {
+	switch {
+	case r.kill.Reached(p):
+		return OOMKill
+	case r.warn.Reached(p):
+		return OOMWarn
+	}
+	return OOMNormal
+}
+
+// Run checks memory pressure every interval until ctx is cancelled.
+func (r *OOMResponder) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		p, err := monitor.ReadMemoryPressure()
+		if err != nil {
+			return fmt.Errorf("reading memory pressure: %w", err)
+		}
+		r.Check(ctx, p)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check compares p with the thresholds and, at the kill level, starts
+// terminating the best victim unless the last attempt was within the
+// cooldown. It returns the termination started, if any. Terminations are
+// charged to the automatic termination budget once started, and none are
+// started in monitor-only mode. Callers refund a termination that did not
+// act.
+func (r *OOMResponder) Check(ctx context.Context, p monitor.MemoryPressure) (*Termination, error) {
+	level := r.LevelOf(p)
+	now := time.Now()
+	r.mu.Lock()
+	changed := level != r.level
+	r.level = level
+	act := level == OOMKill && now.Sub(r.lastKill) >= r.cooldown
+	if act {
+		r.lastKill = now
+	}
+	r.mu.Unlock()
+
+	if changed {
+		r.report(OOMEvent{Level: level, Pressure: p})
+	}
+	if !act || r.mgr.safetyMgr.IsMonitorOnly() {
+		return nil, nil
+	}
+
+	ev := OOMEvent{Level: level, Pressure: p}
+	victims := r.victims(1)
+	if len(victims) == 0 {
+		ev.Err = fmt.Errorf("no process may be terminated to relieve memory pressure")
+		r.report(ev)
+		return nil, ev.Err
+	}
+	ev.Victim = &victims[0]
+	proc := ev.Victim.Proc
+
+	if ok, reason := r.mgr.safetyMgr.CheckAutomatic(proc); !ok {
+		ev.Err = fmt.Errorf("termination blocked: %s", reason)
+		r.report(ev)
+		return nil, ev.Err
+	}
+	ev.Termination, ev.Err = r.mgr.start(ctx, proc, false,
+		override{unit: safety.UnitSignal, container: safety.ContainerSignal, esc: r.esc})
+	if ev.Err == nil {
+		r.mgr.safetyMgr.ChargeAutomatic(proc)
+	}
+	r.report(ev)
+	return ev.Termination, ev.Err
+}
+
+// Victims returns the processes the responder may terminate, best first.
+func (r *OOMResponder) Victims() []Victim {
+	return r.victims(0)
+}
+
+// victims ranks the candidates and returns the first n the safety rules let
+// the responder terminate without asking, or all of them if n is 0. Ranking
+// first keeps the safety checks, which can inspect open files, to the few
+// processes that matter when memory is short.
+func (r *OOMResponder) victims(n int) []Victim {
+	var ranked []Victim
+	for _, proc := range r.procs() {
+		score, err := readOOMScore(proc.PID)
+		if err != nil || score <= 0 {
+			// Gone, a kernel thread, or exempt through oom_score_adj -1000
+			continue
+		}
+		rank := float64(score)
+		if proc.MemoryTrend > 0 {
+			// oom_score is in tenths of a percent of memory, MemoryTrend
+			// in percent since the previous scan
+			rank += proc.MemoryTrend * 10
+		}
+		ranked = append(ranked, Victim{Proc: proc, OOMScore: score, Rank: rank})
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Rank != ranked[j].Rank {
+			return ranked[i].Rank > ranked[j].Rank
+		}
+		return ranked[i].Proc.MemoryMB > ranked[j].Proc.MemoryMB
+	})
+
+	var victims []Victim
+	for _, v := range ranked {
+		if r.mgr.Terminating(v.Proc.PID) != nil {
+			continue
+		}
+		if r.skip != nil {
+			if skip, _ := r.skip(v.Proc); skip {
+				continue
+			}
+		}
+		verdict := r.mgr.terminationVerdict(v.Proc, override{unit: safety.UnitSignal, container: safety.ContainerSignal, esc: r.esc})
+		if !verdict.Allowed || verdict.Confirm {
+			continue
+		}
+		victims = append(victims, v)
+		if len(victims) == n {
+			break
+		}
+	}
+	return victims
+}
+
+func (r *OOMResponder) report(ev OOMEvent) {
+	if r.onEvent != nil {
+		r.onEvent(ev)
+	}
+}
+
+func readOOMScore(pid int) (int, error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/oom_score", pid))
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(data)))
+}
@@ -0,0 +1,166 @@
+package process
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/iamgilwell/aura/internal/monitor"
+)
+
+// Outcome describes what happened to a process after it was terminated.
+type Outcome string
+
+const (
+	OutcomeRespawned     Outcome = "respawned"      // restarted by init or a supervisor
+	OutcomeUserRestarted Outcome = "user-restarted" // restarted from a user session
+	OutcomeStayedDead    Outcome = "stayed-dead"    // did not come back within the window
+)
+
+// supervisors are parent processes that restart their children automatically.
+var supervisors = map[string]bool{
+	"systemd":         true,
+	"init":            true,
+	"supervisord":     true,
+	"runsv":           true,
+	"s6-supervise":    true,
+	"containerd-shim": true,
+	"dockerd":         true,
+	"conmon":          true,
+	"PM2":             true,
+	"launchd":         true,
+}
+
+// OutcomeEvent reports the outcome of a tracked termination.
+type OutcomeEvent struct {
+	TerminationID string
+	Name          string
+	Outcome       Outcome
+	NewPID        int // PID of the reappeared process, if any
+	After         time.Duration
+}
+
+type trackedTermination struct {
+	id           string
+	identity     string
+	name         string
+	terminatedAt time.Time
+}
+
+type suppression struct {
+	until   time.Time
+	outcome Outcome
+}
+
+// OutcomeTracker watches for terminated processes reappearing and
+// suppresses further automatic terminations of processes that respawn.
+type OutcomeTracker struct {
+	mu          sync.Mutex
+	window      time.Duration
+	suppressFor time.Duration
+	pending     []trackedTermination
+	suppressed  map[string]suppression
+}
+
+// NewOutcomeTracker creates a tracker that watches each termination for
+// window and suppresses respawning identities for suppressFor.
+func NewOutcomeTracker(window, suppressFor time.Duration) *OutcomeTracker {
+	return &OutcomeTracker{
+		window:      window,
+		suppressFor: suppressFor,
+		suppressed:  make(map[string]suppression),
+	}
+}
+
+// Track starts watching a terminated process. id is the audit entry ID of
+// the termination so outcomes can be linked back to it.
+func (t *OutcomeTracker) Track(id string, proc *monitor.ProcessInfo) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending = append(t.pending, trackedTermination{
+		id:           id,
+		identity:     proc.Identity(),
+		name:         proc.Name,
+		terminatedAt: time.Now(),
+	})
+}
+
+// Observe checks the current process list against pending terminations and
+// returns the outcomes that were resolved by this scan.
+func (t *OutcomeTracker) Observe(procs []*monitor.ProcessInfo) []OutcomeEvent {
+	now := time.Now()
+	byPID := make(map[int]*monitor.ProcessInfo, len(procs))
+	byIdentity := make(map[string][]*monitor.ProcessInfo)
+	for _, p := range procs {
+		byPID[p.PID] = p
+		byIdentity[p.Identity()] = append(byIdentity[p.Identity()], p)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var events []OutcomeEvent
+	remaining := t.pending[:0]
+
+	for _, tt := range t.pending {
+		var reappeared *monitor.ProcessInfo
+		for _, p := range byIdentity[tt.identity] {
+			// Start times have one-second resolution
+			if !p.StartTime.Before(tt.terminatedAt.Truncate(time.Second)) {
+				reappeared = p
+				break
+			}
+		}
+
+		switch {
+		case reappeared != nil:
+			outcome := OutcomeUserRestarted
+			if parent := byPID[reappeared.PPid]; reappeared.PPid == 1 || (parent != nil && supervisors[parent.Name]) {
+				outcome = OutcomeRespawned
+			}
+			t.suppressed[tt.identity] = suppression{until: now.Add(t.suppressFor), outcome: outcome}
+			events = append(events, OutcomeEvent{
+				TerminationID: tt.id,
+				Name:          tt.name,
+				Outcome:       outcome,
+				NewPID:        reappeared.PID,
+				After:         now.Sub(tt.terminatedAt),
+			})
+		case now.Sub(tt.terminatedAt) > t.window:
+			events = append(events, OutcomeEvent{
+				TerminationID: tt.id,
+				Name:          tt.name,
+				Outcome:       OutcomeStayedDead,
+				After:         now.Sub(tt.terminatedAt),
+			})
+		default:
+			remaining = append(remaining, tt)
+		}
+	}
+	t.pending = remaining
+	return events
+}
+
+// Suppressed reports whether automatic termination of proc should be
+// skipped because the same program came back after a previous termination.
+func (t *OutcomeTracker) Suppressed(proc *monitor.ProcessInfo) (bool, string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s, ok := t.suppressed[proc.Identity()]
+	if !ok {
+		return false, ""
+	}
+	if time.Now().After(s.until) {
+		delete(t.suppressed, proc.Identity())
+		return false, ""
+	}
+	return true, fmt.Sprintf("%s was %s after a previous termination", proc.Name, s.outcome)
+}
+
+// Pending returns the number of terminations still being watched.
+func (t *OutcomeTracker) Pending() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.pending)
+}
@@ -0,0 +1,77 @@
+package process
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/iamgilwell/aura/internal/monitor"
+)
+
+// ProtectTrees configures tree protection: the subtrees of protected
+// processes named in roots, and when ownSession is set, every process in
+// Aura's session or on its controlling terminal. Aura and its parent chain
+// are always protected once UpdateProtection runs.
+func (m *Manager) ProtectTrees(roots []string, ownSession bool) {
+	m.treeRoots = make(map[string]bool, len(roots))
+	for _, name := range roots {
+		m.treeRoots[name] = true
+	}
+	m.ownSession = ownSession
+}
+
+// UpdateProtection recomputes tree protection from the current process list
+// and installs it in the safety manager, along with the process counts used
+// by its budgets, and keeps the list to resolve termination scopes. Call it
+// after every scan.
+func (m *Manager) UpdateProtection(procs []*monitor.ProcessInfo) {
+	tree := BuildDependencyTree(procs)
+	byPID := make(map[int]*monitor.ProcessInfo, len(procs))
+	for _, p := range procs {
+		byPID[p.PID] = p
+	}
+
+	// Aura and its ancestors
+	self := make(map[int]bool)
+	for pid := os.Getpid(); pid > 0 && !self[pid]; pid = tree.ParentOf(pid) {
+		self[pid] = true
+	}
+
+	inherited := make(map[int]string)
+
+	if m.ownSession {
+		if me := byPID[os.Getpid()]; me != nil {
+			for _, p := range procs {
+				switch {
+				case self[p.PID]:
+				case me.Session > 0 && p.Session == me.Session:
+					inherited[p.PID] = fmt.Sprintf("process '%s' is in Aura's session", p.Name)
+				case me.TTY != 0 && p.TTY == me.TTY:
+					inherited[p.PID] = fmt.Sprintf("process '%s' is on Aura's terminal", p.Name)
+				}
+			}
+		}
+	}
+
+	for _, root := range procs {
+		if !m.treeRoots[root.Name] || !m.safetyMgr.IsProtected(root) {
+			continue
+		}
+		for _, pid := range tree.AllDescendants(root.PID) {
+			if _, ok := inherited[pid]; ok || self[pid] {
+				continue
+			}
+			name := "unknown"
+			if p := byPID[pid]; p != nil {
+				name = p.Name
+			}
+			inherited[pid] = fmt.Sprintf("process '%s' descends from protected '%s' (PID %d)", name, root.Name, root.PID)
+		}
+	}
+
+	m.safetyMgr.SetTreeProtection(self, inherited)
+	m.safetyMgr.ObserveProcesses(procs)
+
+	m.mu.Lock()
+	m.procs = procs
+	m.mu.Unlock()
+}
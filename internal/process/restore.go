@@ -0,0 +1,461 @@
+package process
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"github.com/iamgilwell/aura/internal/monitor"
+)
+
+// Redacted replaces the value of environment variables that look secret.
+const Redacted = "[REDACTED]"
+
+// secretMarkers flag environment variable names whose values are not kept.
+var secretMarkers = []string{
+	"TOKEN", "SECRET", "PASSWORD", "PASSWD", "PASSPHRASE",
+	"CREDENTIAL", "COOKIE", "APIKEY", "_KEY",
+}
+
+// Limit is a soft/hard resource limit pair; unlimited is RLIM_INFINITY.
+type Limit struct {
+	Soft uint64 `json:"soft"`
+	Hard uint64 `json:"hard"`
+}
+
+// limitResources maps /proc/[pid]/limits names to RLIMIT_* resources.
+var limitResources = map[string]int{
+	"Max cpu time":          0,
+	"Max file size":         1,
+	"Max data size":         2,
+	"Max stack size":        3,
+	"Max core file size":    4,
+	"Max resident set":      5,
+	"Max processes":         6,
+	"Max open files":        7,
+	"Max locked memory":     8,
+	"Max address space":     9,
+	"Max file locks":        10,
+	"Max pending signals":   11,
+	"Max msgqueue size":     12,
+	"Max nice priority":     13,
+	"Max realtime priority": 14,
+	"Max realtime timeout":  15,
+}
+
+// RestoreRecord holds what is needed to relaunch a terminated process.
+type RestoreRecord struct {
+	ID           string           `json:"id"` // audit entry ID of the termination
+	PID          int              `json:"pid"`
+	Name         string           `json:"name"`
+	Exe          string           `json:"exe,omitempty"`
+	Argv         []string         `json:"argv"`
+	Cwd          string           `json:"cwd,omitempty"`
+	Env          []string         `json:"env,omitempty"` // KEY=VALUE, secrets redacted
+	User         string           `json:"user,omitempty"`
+	UID          int              `json:"uid"`
+	GID          int              `json:"gid"`
+	Limits       map[string]Limit `json:"limits,omitempty"`
+	TerminatedAt time.Time        `json:"terminated_at"`
+}
+
+// Command returns the command line of the record for display.
+func (r *RestoreRecord) Command() string {
+	return strings.Join(r.Argv, " ")
+}
+
+// CaptureRestore reads the argv, working directory, environment, owner and
+// resource limits of a running process.
+func CaptureRestore(proc *monitor.ProcessInfo) (*RestoreRecord, error) {
+	dir := filepath.Join("/proc", strconv.Itoa(proc.PID))
+
+	argv, err := readNulList(filepath.Join(dir, "cmdline"))
+	if err != nil {
+		return nil, fmt.Errorf("reading command line of %d: %w", proc.PID, err)
+	}
+	if len(argv) == 0 {
+		return nil, fmt.Errorf("process %d has no command line", proc.PID)
+	}
+
+	rec := &RestoreRecord{
+		PID:  proc.PID,
+		Name: proc.Name,
+		Exe:  proc.Exe,
+		Argv: argv,
+		User: proc.User,
+		UID:  proc.UID,
+		GID:  -1,
+	}
+	if rec.Exe == "" {
+		rec.Exe, _ = os.Readlink(filepath.Join(dir, "exe"))
+	}
+	rec.Cwd, _ = os.Readlink(filepath.Join(dir, "cwd"))
+	if env, err := readNulList(filepath.Join(dir, "environ")); err == nil {
+		rec.Env = RedactEnv(env)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "status")); err == nil {
+		for _, line := range strings.Split(string(data), "\n") {
+			fields := strings.Fields(line)
+			if len(fields) < 2 {
+				continue
+			}
+			switch fields[0] {
+			case "Uid:":
+				rec.UID, _ = strconv.Atoi(fields[1])
+			case "Gid:":
+				rec.GID, _ = strconv.Atoi(fields[1])
+			}
+		}
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "limits")); err == nil {
+		rec.Limits = parseLimits(string(data))
+	}
+	return rec, nil
+}
+
+// RedactEnv replaces the values of variables that look like secrets.
+func RedactEnv(env []string) []string {
+	out := make([]string, 0, len(env))
+	for _, kv := range env {
+		key, _, ok := strings.Cut(kv, "=")
+		if ok && isSecret(key) {
+			kv = key + "=" + Redacted
+		}
+		out = append(out, kv)
+	}
+	return out
+}
+
+func isSecret(key string) bool {
+	upper := strings.ToUpper(key)
+	for _, marker := range secretMarkers {
+		if strings.Contains(upper, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+func readNulList(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var list []string
+	for _, s := range strings.Split(string(data), "\x00") {
+		if s != "" {
+			list = append(list, s)
+		}
+	}
+	return list, nil
+}
+
+// parseLimits parses /proc/[pid]/limits. Column boundaries come from the
+// header because limit names contain spaces.
+func parseLimits(content string) map[string]Limit {
+	lines := strings.Split(content, "\n")
+	if len(lines) == 0 {
+		return nil
+	}
+	softCol := strings.Index(lines[0], "Soft Limit")
+	if softCol < 0 {
+		return nil
+	}
+
+	limits := make(map[string]Limit)
+	for _, line := range lines[1:] {
+		if len(line) <= softCol {
+			continue
+		}
+		name := strings.TrimSpace(line[:softCol])
+		if _, ok := limitResources[name]; !ok {
+			continue
+		}
+		fields := strings.Fields(line[softCol:])
+		if len(fields) < 2 {
+			continue
+		}
+		limits[name] = Limit{Soft: parseLimitValue(fields[0]), Hard: parseLimitValue(fields[1])}
+	}
+	return limits
+}
+
+func parseLimitValue(s string) uint64 {
+	if s == "unlimited" {
+		return ^uint64(0)
+	}
+	v, _ := strconv.ParseUint(s, 10, 64)
+	return v
+}
+
+// Relaunch starts the process described by rec in a new session, as its
+// original user when running as root. Secret environment variables, which
+// were not recorded, are left unset. Resource limits are restored on a
+// best-effort basis right after the process starts. It returns the new PID.
+func Relaunch(rec *RestoreRecord) (int, error) {
+	if len(rec.Argv) == 0 {
+		return 0, fmt.Errorf("restore record %s has no command line", rec.ID)
+	}
+	path := rec.Exe
+	if path == "" || strings.HasSuffix(path, " (deleted)") {
+		path = rec.Argv[0]
+	}
+
+	cmd := exec.Command(path)
+	cmd.Args = rec.Argv
+	cmd.Dir = rec.Cwd
+	if fi, err := os.Stat(rec.Cwd); err != nil || !fi.IsDir() {
+		cmd.Dir = "/"
+	}
+	cmd.Env = []string{}
+	for _, kv := range rec.Env {
+		if !strings.HasSuffix(kv, "="+Redacted) {
+			cmd.Env = append(cmd.Env, kv)
+		}
+	}
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+
+	if euid := os.Geteuid(); euid != rec.UID {
+		if euid != 0 {
+			return 0, fmt.Errorf("relaunching a process of UID %d requires root", rec.UID)
+		}
+		cred := &syscall.Credential{Uid: uint32(rec.UID), Gid: uint32(rec.GID)}
+		if rec.GID < 0 {
+			cred.Gid = uint32(rec.UID)
+		}
+		if u, err := user.LookupId(strconv.Itoa(rec.UID)); err == nil {
+			if gids, err := u.GroupIds(); err == nil {
+				for _, g := range gids {
+					if gid, err := strconv.ParseUint(g, 10, 32); err == nil {
+						cred.Groups = append(cred.Groups, uint32(gid))
+					}
+				}
+			}
+		}
+		cmd.SysProcAttr.Credential = cred
+	}
+
+	if err := cmd.Start(); err != nil {
+		return 0, fmt.Errorf("starting %s: %w", path, err)
+	}
+	pid := cmd.Process.Pid
+	for name, l := range rec.Limits {
+		_ = setLimit(pid, limitResources[name], l)
+	}
+	// Reap the child if Aura outlives it
+	go func() { _ = cmd.Wait() }()
+	return pid, nil
+}
+
+// setLimit applies a resource limit to another process with prlimit(2).
+func setLimit(pid, resource int, l Limit) error {
+	lim := syscall.Rlimit{Cur: l.Soft, Max: l.Hard}
+	_, _, errno := syscall.RawSyscall6(syscall.SYS_PRLIMIT64,
+		uintptr(pid), uintptr(resource), uintptr(unsafe.Pointer(&lim)), 0, 0, 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// RestoreStore keeps restore records as one JSON file per termination.
+type RestoreStore struct {
+	dir       string
+	retention time.Duration
+}
+
+// NewRestoreStore creates a store in dir whose records are pruned after
+// retention (zero keeps them forever).
+func NewRestoreStore(dir string, retention time.Duration) *RestoreStore {
+	return &RestoreStore{dir: dir, retention: retention}
+}
+
+// Save writes rec, replacing any record with the same ID, and prunes
+// records past the retention period.
+func (s *RestoreStore) Save(rec *RestoreRecord) error {
+	path, err := s.path(rec.ID)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return fmt.Errorf("creating restore directory: %w", err)
+	}
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding restore record: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("writing restore record: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+	s.prune()
+	return nil
+}
+
+// Load returns the record of the termination with audit ID id.
+func (s *RestoreStore) Load(id string) (*RestoreRecord, error) {
+	path, err := s.path(id)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no restore record for termination %q", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading restore record: %w", err)
+	}
+	var rec RestoreRecord
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("parsing restore record %s: %w", id, err)
+	}
+	return &rec, nil
+}
+
+// Remove deletes the record of termination id.
+func (s *RestoreStore) Remove(id string) error {
+	path, err := s.path(id)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// List returns the stored records, most recent first.
+func (s *RestoreStore) List() ([]*RestoreRecord, error) {
+	entries, err := os.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading restore directory: %w", err)
+	}
+	var recs []*RestoreRecord
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok || e.IsDir() {
+			continue
+		}
+		if rec, err := s.Load(id); err == nil {
+			recs = append(recs, rec)
+		}
+	}
+	sort.Slice(recs, func(i, j int) bool { return recs[i].TerminatedAt.After(recs[j].TerminatedAt) })
+	return recs, nil
+}
+
+func (s *RestoreStore) prune() {
+	if s.retention <= 0 {
+		return
+	}
+	recs, _ := s.List()
+	cutoff := time.Now().Add(-s.retention)
+	for _, rec := range recs {
+		if rec.TerminatedAt.Before(cutoff) {
+			_ = s.Remove(rec.ID)
+		}
+	}
+}
+
+// path maps an ID to its file, refusing IDs that could escape the directory.
+func (s *RestoreStore) path(id string) (string, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) || strings.HasPrefix(id, ".") {
+		return "", fmt.Errorf("invalid termination ID %q", id)
+	}
+	return filepath.Join(s.dir, id+".json"), nil
+}
+
+// SetRestoreStore enables capturing restore records before terminations.
+func (m *Manager) SetRestoreStore(s *RestoreStore) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.restores = s
+}
+
+// Restores returns the restore store, or nil if undo is disabled.
+func (m *Manager) Restores() *RestoreStore {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.restores
+}
+
+// capture records proc before it is terminated, until SaveRestore learns
+// the audit ID of the termination. /proc is read without holding the lock.
+func (m *Manager) capture(proc *monitor.ProcessInfo) {
+	if m.Restores() == nil {
+		return
+	}
+	rec, err := CaptureRestore(proc)
+	if err != nil {
+		return
+	}
+	m.mu.Lock()
+	if m.captured == nil {
+		m.captured = make(map[int]*RestoreRecord)
+	}
+	m.captured[proc.PID] = rec
+	m.mu.Unlock()
+}
+
+// discard drops the record captured for proc when its termination was
+// vetoed, cancelled or failed, and SaveRestore is never called for it.
+func (m *Manager) discard(proc *monitor.ProcessInfo) {
+	m.mu.Lock()
+	delete(m.captured, proc.PID)
+	m.mu.Unlock()
+}
+
+// SaveRestore stores the record captured when proc was terminated under the
+// audit ID of its termination, so "aura undo <id>" can relaunch it.
+func (m *Manager) SaveRestore(id string, proc *monitor.ProcessInfo) error {
+	m.mu.Lock()
+	rec := m.captured[proc.PID]
+	delete(m.captured, proc.PID)
+	store := m.restores
+	m.mu.Unlock()
+
+	if rec == nil || store == nil {
+		return nil
+	}
+	rec.ID = id
+	rec.TerminatedAt = time.Now()
+	return store.Save(rec)
+}
+
+// Undo relaunches the process terminated by the termination with audit ID
+// id and removes its record. It returns the record and the new PID; in dry
+// run the record is kept and nothing is started (PID 0).
+func (m *Manager) Undo(id string) (*RestoreRecord, int, error) {
+	store := m.Restores()
+	if store == nil {
+		return nil, 0, fmt.Errorf("undo is disabled (safety.restore_dir is empty)")
+	}
+	rec, err := store.Load(id)
+	if err != nil {
+		return nil, 0, err
+	}
+	if m.dryRun {
+		return rec, 0, nil
+	}
+	pid, err := Relaunch(rec)
+	if err != nil {
+		return rec, 0, err
+	}
+	_ = store.Remove(id)
+	return rec, pid, nil
+}
@@ -0,0 +1,370 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/iamgilwell/aura/internal/monitor"
+	"github.com/iamgilwell/aura/internal/safety"
+)
+
+// cgroupRoot is where the unified cgroup hierarchy is mounted.
+const cgroupRoot = "/sys/fs/cgroup"
+
+// TerminationResult describes a termination carried out (or, in dry run,
+// simulated) by SafeTerminate. Exit is zero in dry run and when a pre hook
+// stopped the target itself.
+type TerminationResult struct {
+	Scope   safety.Scope
+	Members []*monitor.ProcessInfo // every process taken down, the target last
+	Exit
+
+	// The unit acted on through systemctl instead of signalling, for
+	// ScopeUnit
+	Unit       Unit
+	UnitAction safety.UnitAction
+
+	// What was done to the target's container through its runtime instead
+	// of signalling, for ScopeContainer
+	ContainerAction safety.ContainerAction
+}
+
+// Paused reports whether the target's container was paused rather than
+// terminated: its processes are still there, frozen until it is unpaused.
+func (r *TerminationResult) Paused() bool {
+	return r.ContainerAction == safety.ContainerPause
+}
+
+// PIDs returns the PIDs of the members.
+func (r *TerminationResult) PIDs() []int {
+	pids := make([]int, len(r.Members))
+	for i, p := range r.Members {
+		pids[i] = p.PID
+	}
+	return pids
+}
+
+// Target returns the process the termination was aimed at.
+func (r *TerminationResult) Target() *monitor.ProcessInfo {
+	return r.Members[len(r.Members)-1]
+}
+
+// Extent describes the other members for messages, e.g. " and 3 other
+// processes in its tree", or returns "" for a single process.
+func (r *TerminationResult) Extent() string {
+	switch r.Scope {
+	case safety.ScopeProcess:
+		return ""
+	case safety.ScopeUnit:
+		return fmt.Sprintf(" and %d other processes of %s", len(r.Members)-1, r.Unit.Name)
+	case safety.ScopeContainer:
+		return fmt.Sprintf(" and %d other processes of container %s", len(r.Members)-1, r.Target().ContainerLabel())
+	}
+	return fmt.Sprintf(" and %d other processes in its %s", len(r.Members)-1, r.Scope)
+}
+
+// ActionVia describes the systemctl command or container runtime call the
+// termination goes through, e.g. " via systemctl restart nginx.service", or
+// returns "" for signals.
+func (r *TerminationResult) ActionVia() string {
+	switch {
+	case r.ContainerAction != "":
+		return fmt.Sprintf(" via the container runtime (%s %s)", r.ContainerAction, r.Target().ContainerLabel())
+	case r.UnitAction != "":
+		return fmt.Sprintf(" via systemctl %s %s", r.UnitAction, r.Unit.Name)
+	}
+	return ""
+}
+
+// Verb names what the termination does to the target for messages: the
+// unit or container action, e.g. "restart" or "pause", or "terminate" for
+// signals.
+func (r *TerminationResult) Verb() string {
+	switch {
+	case r.ContainerAction != "":
+		return string(r.ContainerAction)
+	case r.UnitAction != "":
+		return string(r.UnitAction)
+	}
+	return "terminate"
+}
+
+// Outcome describes how the termination went for messages, e.g. "exited
+// after SIGTERM in 1.2s" or "stopped nginx.service in 0.3s".
+func (r *TerminationResult) Outcome() string {
+	switch r.ContainerAction {
+	case safety.ContainerStop:
+		return fmt.Sprintf("stopped container %s in %s", r.Target().ContainerLabel(), r.Elapsed.Round(100*time.Millisecond))
+	case safety.ContainerPause:
+		return fmt.Sprintf("paused container %s", r.Target().ContainerLabel())
+	}
+	switch r.UnitAction {
+	case safety.UnitStop:
+		return fmt.Sprintf("stopped %s in %s", r.Unit.Name, r.Elapsed.Round(100*time.Millisecond))
+	case safety.UnitRestart:
+		return fmt.Sprintf("restarted %s in %s", r.Unit.Name, r.Elapsed.Round(100*time.Millisecond))
+	case safety.UnitKill:
+		s := fmt.Sprintf("killed %s: %s", r.Unit.Name, r.Exit)
+		if RestartsAfter(r.Unit.Restart, r.Signal) {
+			s += fmt.Sprintf(", systemd restarts it (Restart=%s)", r.Unit.Restart)
+		}
+		return s
+	}
+	return r.Exit.String()
+}
+
+// SetScope sets the termination scope used unless the policy rule matching
+// a process sets its own.
+func (m *Manager) SetScope(scope safety.Scope) {
+	m.scope = scope
+}
+
+// ScopeOf returns the termination scope that applies to proc.
+func (m *Manager) ScopeOf(proc *monitor.ProcessInfo) safety.Scope {
+	return m.scopeOf(m.safetyMgr.Evaluate(proc))
+}
+
+func (m *Manager) scopeOf(v safety.Verdict) safety.Scope {
+	switch {
+	case v.Scope != "":
+		return v.Scope
+	case m.scope != "":
+		return m.scope
+	default:
+		return safety.ScopeProcess
+	}
+}
+
+// Members returns the processes a termination of proc in scope would take
+// down, in termination order with proc last, from the processes seen at the
+// last UpdateProtection.
+func (m *Manager) Members(proc *monitor.ProcessInfo, scope safety.Scope) ([]*monitor.ProcessInfo, error) {
+	m.mu.Lock()
+	procs := m.procs
+	m.mu.Unlock()
+
+	byPID := make(map[int]*monitor.ProcessInfo, len(procs))
+	for _, p := range procs {
+		byPID[p.PID] = p
+	}
+
+	var in func(p *monitor.ProcessInfo) bool
+	switch scope {
+	case safety.ScopeProcess, "":
+		return []*monitor.ProcessInfo{proc}, nil
+
+	case safety.ScopeTree:
+		tree := BuildDependencyTree(procs)
+		order := tree.SafeTerminationOrder(proc.PID)
+		members := make([]*monitor.ProcessInfo, 0, len(order))
+		for _, pid := range order[:len(order)-1] {
+			if p := byPID[pid]; p != nil {
+				members = append(members, p)
+			}
+		}
+		return append(members, proc), nil
+
+	case safety.ScopeGroup:
+		if proc.Pgrp <= 1 {
+			return nil, fmt.Errorf("process %d has no process group to terminate", proc.PID)
+		}
+		in = func(p *monitor.ProcessInfo) bool { return p.Pgrp == proc.Pgrp }
+
+	case safety.ScopeSession:
+		if proc.Session <= 1 {
+			return nil, fmt.Errorf("process %d has no session to terminate", proc.PID)
+		}
+		in = func(p *monitor.ProcessInfo) bool { return p.Session == proc.Session }
+
+	case safety.ScopeCgroup:
+		if proc.Cgroup == "" || proc.Cgroup == "/" {
+			return nil, fmt.Errorf("process %d is not in a cgroup that can be terminated", proc.PID)
+		}
+		in = func(p *monitor.ProcessInfo) bool {
+			return p.Cgroup == proc.Cgroup || strings.HasPrefix(p.Cgroup, proc.Cgroup+"/")
+		}
+
+	case safety.ScopeUnit:
+		u, ok := unitOf(proc)
+		if !ok {
+			return nil, fmt.Errorf("process %d is not owned by a systemd unit", proc.PID)
+		}
+		in = func(p *monitor.ProcessInfo) bool {
+			return p.Cgroup == u.Cgroup || strings.HasPrefix(p.Cgroup, u.Cgroup+"/")
+		}
+
+	case safety.ScopeContainer:
+		if proc.Container == "" {
+			return nil, fmt.Errorf("process %d is not running in a container", proc.PID)
+		}
+		in = func(p *monitor.ProcessInfo) bool { return p.Container == proc.Container }
+
+	default:
+		return nil, fmt.Errorf("unknown termination scope %q", scope)
+	}
+
+	// Leaders go last so the group or session is torn down from the inside
+	var members []*monitor.ProcessInfo
+	for _, p := range procs {
+		if p.PID != proc.PID && in(p) {
+			members = append(members, p)
+		}
+	}
+	sort.SliceStable(members, func(i, j int) bool {
+		return !isLeader(members[i]) && isLeader(members[j])
+	})
+	return append(members, proc), nil
+}
+
+func isLeader(p *monitor.ProcessInfo) bool {
+	return p.PID == p.Pgrp || p.PID == p.Session
+}
+
+// terminateScope climbs esc for the members of a validated scoped
+// termination.
+func (m *Manager) terminateScope(ctx context.Context, proc *monitor.ProcessInfo, scope safety.Scope, members []*monitor.ProcessInfo, esc safety.Escalation, sent func(syscall.Signal)) (Exit, error) {
+	pids := func() []int {
+		pids := make([]int, len(members))
+		for i, p := range members {
+			pids[i] = p.PID
+		}
+		return pids
+	}
+	switch scope {
+	case safety.ScopeGroup:
+		return m.escalate(ctx, fmt.Sprintf("process group %d", proc.Pgrp), esc, sent, func(sig syscall.Signal) error {
+			return signalGroup(proc.Pgrp, members, sig)
+		}, pids)
+	case safety.ScopeCgroup:
+		dir := filepath.Join(cgroupRoot, proc.Cgroup)
+		return m.escalate(ctx, "cgroup "+proc.Cgroup, esc, sent, func(sig syscall.Signal) error {
+			return signalCgroup(dir, members, sig)
+		}, func() []int {
+			if live, err := cgroupPIDs(dir); err == nil {
+				return live
+			}
+			return pids()
+		})
+	default:
+		return m.escalate(ctx, fmt.Sprintf("%d processes", len(members)), esc, sent, func(sig syscall.Signal) error {
+			return signalAll(members, sig)
+		}, pids)
+	}
+}
+
+// signalAll sends sig to members in order. It fails with ESRCH once none of
+// them is left.
+func signalAll(members []*monitor.ProcessInfo, sig syscall.Signal) error {
+	alive := false
+	for _, p := range members {
+		if syscall.Kill(p.PID, sig) == nil {
+			alive = true
+		}
+	}
+	if !alive {
+		return syscall.ESRCH
+	}
+	return nil
+}
+
+// signalGroup sends sig to process group pgrp at once, unless processes
+// that were not validated have joined it since the last scan, in which case
+// only the members are signalled.
+func signalGroup(pgrp int, members []*monitor.ProcessInfo, sig syscall.Signal) error {
+	pids, err := groupPIDs(pgrp)
+	if err != nil || !validated(pids, members) {
+		return signalAll(members, sig)
+	}
+	if len(pids) == 0 {
+		return syscall.ESRCH
+	}
+	return syscall.Kill(-pgrp, sig)
+}
+
+// signalCgroup sends sig to every process in the cgroup at dir and its
+// descendants, using cgroup.kill for SIGKILL. Without a readable unified
+// hierarchy, or once processes that were not validated have joined the
+// cgroup since the last scan, it signals only the members.
+func signalCgroup(dir string, members []*monitor.ProcessInfo, sig syscall.Signal) error {
+	pids, err := cgroupPIDs(dir)
+	if err != nil || !validated(pids, members) {
+		return signalAll(members, sig)
+	}
+	if len(pids) == 0 {
+		return syscall.ESRCH
+	}
+	if sig == syscall.SIGKILL {
+		if err := os.WriteFile(filepath.Join(dir, "cgroup.kill"), []byte("1"), 0); err == nil {
+			return nil
+		}
+	}
+	for _, pid := range pids {
+		_ = syscall.Kill(pid, sig)
+	}
+	return nil
+}
+
+// cgroupPIDs lists the processes in the cgroup at dir and its descendants.
+func cgroupPIDs(dir string) ([]int, error) {
+	var pids []int
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || d.Name() != "cgroup.procs" {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, f := range strings.Fields(string(data)) {
+			if pid, err := strconv.Atoi(f); err == nil {
+				pids = append(pids, pid)
+			}
+		}
+		return nil
+	})
+	return pids, err
+}
+
+// groupPIDs lists the processes in process group pgrp.
+func groupPIDs(pgrp int) ([]int, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, err
+	}
+	var pids []int
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		if f := statFields(pid); len(f) > 2 && f[2] == strconv.Itoa(pgrp) {
+			pids = append(pids, pid)
+		}
+	}
+	return pids, nil
+}
+
+// validated reports whether every one of pids is among members.
+func validated(pids []int, members []*monitor.ProcessInfo) bool {
+	known := make(map[int]bool, len(members))
+	for _, p := range members {
+		known[p.PID] = true
+	}
+	for _, pid := range pids {
+		if !known[pid] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,219 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/iamgilwell/aura/internal/monitor"
+	"github.com/iamgilwell/aura/internal/safety"
+)
+
+// systemctlTimeout bounds systemctl queries; stop and restart jobs may take
+// as long as the unit's own stop timeout.
+const systemctlTimeout = 5 * time.Second
+
+// Runner runs an external command and returns its combined output. The
+// manager runs systemctl through it so that tests can substitute a fake.
+type Runner interface {
+	Run(ctx context.Context, name string, args ...string) ([]byte, error)
+}
+
+// ExecRunner runs commands with os/exec.
+type ExecRunner struct{}
+
+// Run implements Runner.
+func (ExecRunner) Run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	out, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return out, fmt.Errorf("%s %s: %w: %s", name, strings.Join(args, " "), err, msg)
+		}
+		return out, fmt.Errorf("%s %s: %w", name, strings.Join(args, " "), err)
+	}
+	return out, nil
+}
+
+// Unit is the systemd unit owning a process.
+type Unit struct {
+	Name    string
+	Cgroup  string // the unit's cgroup, containing the process's
+	User    bool   // managed by a user's service manager (systemctl --user)
+	Restart string // Restart= of a service, once queried
+}
+
+// Service reports whether the unit is a service, the only kind systemd
+// restarts.
+func (u Unit) Service() bool {
+	return strings.HasSuffix(u.Name, ".service")
+}
+
+// unitOf returns the unit owning proc from its cgroup path.
+func unitOf(proc *monitor.ProcessInfo) (Unit, bool) {
+	if proc.Unit == "" {
+		return Unit{}, false
+	}
+	i := strings.LastIndex(proc.Cgroup, "/"+proc.Unit)
+	if i < 0 {
+		return Unit{}, false
+	}
+	cgroup := proc.Cgroup[:i+1+len(proc.Unit)]
+	return Unit{Name: proc.Unit, Cgroup: cgroup, User: strings.Contains(cgroup, "/user@")}, true
+}
+
+// controllable reports whether Aura can reach the service manager of u:
+// the system manager, or the user manager of the user Aura runs as.
+func (u Unit) controllable() bool {
+	if !u.User {
+		return true
+	}
+	i := strings.Index(u.Cgroup, "/user@")
+	uid, err := strconv.Atoi(strings.SplitN(u.Cgroup[i+len("/user@"):], ".", 2)[0])
+	return err == nil && uid == os.Geteuid()
+}
+
+// RestartsAfter reports whether systemd restarts a service with the given
+// Restart= setting once its main process is killed by sig. SIGHUP, SIGINT,
+// SIGTERM and SIGPIPE count as clean exits.
+func RestartsAfter(restart string, sig syscall.Signal) bool {
+	clean := sig == syscall.SIGHUP || sig == syscall.SIGINT || sig == syscall.SIGTERM || sig == syscall.SIGPIPE
+	switch restart {
+	case "always":
+		return true
+	case "on-success":
+		return clean
+	case "on-failure", "on-abnormal":
+		return !clean
+	case "on-abort":
+		return sig == syscall.SIGQUIT || sig == syscall.SIGABRT || sig == syscall.SIGSEGV
+	default: // no, on-watchdog
+		return false
+	}
+}
+
+// SetRunner replaces the runner used for systemctl.
+func (m *Manager) SetRunner(r Runner) {
+	m.runner = r
+}
+
+// SetUnitAction sets what terminations do to unit-managed processes unless
+// the policy rule matching a process sets its own.
+func (m *Manager) SetUnitAction(a safety.UnitAction) {
+	m.unitAction = a
+}
+
+// systemctl runs systemctl for u, addressing the user manager if needed.
+func (m *Manager) systemctl(ctx context.Context, u Unit, args ...string) (string, error) {
+	if u.User {
+		args = append([]string{"--user"}, args...)
+	}
+	out, err := m.runner.Run(ctx, "systemctl", args...)
+	return strings.TrimSpace(string(out)), err
+}
+
+// UnitOf returns the unit owning proc with its Restart= setting, or false
+// if proc has no unit Aura can control.
+func (m *Manager) UnitOf(proc *monitor.ProcessInfo) (Unit, bool) {
+	u, ok := unitOf(proc)
+	if !ok || !u.controllable() {
+		return Unit{}, false
+	}
+	if u.Service() {
+		ctx, cancel := context.WithTimeout(context.Background(), systemctlTimeout)
+		defer cancel()
+		if restart, err := m.systemctl(ctx, u, "show", "--property=Restart", "--value", u.Name); err == nil {
+			u.Restart = restart
+		}
+	}
+	return u, true
+}
+
+// UnitPlan reports how a termination of proc would treat its unit under the
+// matching policy rule and the configured unit action: UnitSignal when the
+// process would be signalled directly.
+func (m *Manager) UnitPlan(proc *monitor.ProcessInfo) (safety.UnitAction, Unit, error) {
+	verdict := m.safetyMgr.Evaluate(proc)
+	return m.unitPlan(proc, verdict, "", m.escalationOf(verdict, false))
+}
+
+// unitPlan decides how to terminate proc with respect to its unit. requested
+// overrides the unit action of the verdict and the manager; the plan is
+// UnitSignal when the process is signalled directly.
+func (m *Manager) unitPlan(proc *monitor.ProcessInfo, verdict safety.Verdict, requested safety.UnitAction, esc safety.Escalation) (safety.UnitAction, Unit, error) {
+	action := requested
+	for _, a := range []safety.UnitAction{verdict.UnitAction, m.unitAction, safety.UnitAuto} {
+		if action == "" {
+			action = a
+		}
+	}
+	if action == safety.UnitSignal || proc.Unit == "" {
+		if requested != "" && requested != safety.UnitSignal {
+			return "", Unit{}, fmt.Errorf("%s (PID %d) is not owned by a systemd unit", proc.Name, proc.PID)
+		}
+		return safety.UnitSignal, Unit{}, nil
+	}
+
+	u, ok := m.UnitOf(proc)
+	switch {
+	case action == safety.UnitAuto:
+		// Only services are restarted, and only Restart= decides it
+		if !ok || !u.Service() {
+			return safety.UnitSignal, Unit{}, nil
+		}
+		for _, step := range esc {
+			if RestartsAfter(u.Restart, step.Signal) {
+				return safety.UnitStop, u, nil
+			}
+		}
+		return safety.UnitSignal, Unit{}, nil
+	case !ok:
+		return "", Unit{}, fmt.Errorf("unit %s of %s (PID %d) belongs to another user's service manager", proc.Unit, proc.Name, proc.PID)
+	case action == safety.UnitRestart && !u.Service():
+		return "", Unit{}, fmt.Errorf("unit %s is not a service and cannot be restarted", u.Name)
+	}
+	return action, u, nil
+}
+
+// runUnit carries out a unit action on the unit of a validated termination
+// and waits for its members to go, except when restarting.
+func (m *Manager) runUnit(t *Termination, result *TerminationResult, esc safety.Escalation) error {
+	u := result.Unit
+	pids := func() []int {
+		if live, err := cgroupPIDs(cgroupRoot + u.Cgroup); err == nil {
+			return live
+		}
+		return result.PIDs()
+	}
+
+	start := time.Now()
+	switch result.UnitAction {
+	case safety.UnitKill:
+		sent := func(sig syscall.Signal) {
+			t.report(Progress{Stage: StageSignalled, Signal: sig})
+		}
+		exit, err := m.escalate(t.ctx, u.Name, esc, sent, func(sig syscall.Signal) error {
+			_, err := m.systemctl(t.ctx, u, "kill", "--signal="+safety.SignalName(sig), u.Name)
+			return err
+		}, pids)
+		result.Exit = exit
+		return err
+	default:
+		if _, err := m.systemctl(t.ctx, u, string(result.UnitAction), u.Name); err != nil {
+			return err
+		}
+	}
+	result.Elapsed = time.Since(start)
+	if result.UnitAction == safety.UnitRestart {
+		return nil
+	}
+	// systemctl stop returns once the stop job is done; zombies may remain
+	if _, zombie := alive(pids()); zombie {
+		result.Zombie = true
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/iamgilwell/aura/internal/monitor"
+	"github.com/iamgilwell/aura/internal/safety"
+)
+
+// Stage is a step of an in-flight termination.
+type Stage string
+
+const (
+	StageStarted   Stage = "started"   // validated; hooks and signals follow
+	StageSignalled Stage = "signalled" // a signal of the escalation ladder was sent
+	StageDone      Stage = "done"      // finished, successfully or not
+)
+
+// Progress is an event of an in-flight termination.
+type Progress struct {
+	Stage   Stage
+	Signal  syscall.Signal // the signal sent, for StageSignalled
+	Elapsed time.Duration  // since the termination started
+	Err     error          // why it failed, for StageDone
+}
+
+// Termination is a handle on a termination running in the background,
+// started by StartTerminate.
+type Termination struct {
+	Proc    *monitor.ProcessInfo
+	Started time.Time
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	events chan Progress
+	done   chan struct{}
+
+	mu     sync.Mutex
+	last   Progress
+	acted  bool
+	result *TerminationResult
+	err    error
+}
+
+// Events delivers the progress of the termination and is closed once it is
+// done. Events are dropped rather than block the termination when nobody
+// reads them; Progress always has the latest.
+func (t *Termination) Events() <-chan Progress {
+	return t.events
+}
+
+// Done is closed once the termination is over.
+func (t *Termination) Done() <-chan struct{} {
+	return t.done
+}
+
+// Progress returns the latest event.
+func (t *Termination) Progress() Progress {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	p := t.last
+	if p.Stage != StageDone {
+		p.Elapsed = time.Since(t.Started)
+	}
+	return p
+}
+
+// Acted reports whether the termination got past its pre-termination hooks
+// and went on to signal or stop anything. Vetoed terminations, and those
+// cancelled before, did not.
+func (t *Termination) Acted() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.acted
+}
+
+func (t *Termination) act() {
+	t.mu.Lock()
+	t.acted = true
+	t.mu.Unlock()
+}
+
+// Wait blocks until the termination is over and returns its outcome.
+func (t *Termination) Wait() (*TerminationResult, error) {
+	<-t.done
+	return t.result, t.err
+}
+
+// Cancel stops the escalation: no further signal is sent and Wait returns
+// an error wrapping context.Canceled. Signals already sent are not undone.
+func (t *Termination) Cancel() {
+	t.cancel()
+}
+
+func (t *Termination) report(p Progress) {
+	p.Elapsed = time.Since(t.Started)
+	t.mu.Lock()
+	t.last = p
+	t.mu.Unlock()
+	select {
+	case t.events <- p:
+	default:
+	}
+}
+
+func (t *Termination) finish(result *TerminationResult, err error) {
+	t.mu.Lock()
+	t.result, t.err = result, err
+	t.mu.Unlock()
+	t.report(Progress{Stage: StageDone, Err: err})
+	t.cancel()
+	close(t.events)
+	close(t.done)
+}
+
+// StartTerminate validates procInfo like SafeTerminate and, if it may be
+// terminated, carries out the termination in the background, returning a
+// handle to follow or cancel it. Cancelling ctx cancels the termination.
+// Terminations of different processes proceed concurrently; a process
+// already being terminated is refused.
+func (m *Manager) StartTerminate(ctx context.Context, procInfo *monitor.ProcessInfo, force bool) (*Termination, error) {
+	return m.start(ctx, procInfo, force, override{})
+}
+
+// StartUnitAction is StartTerminate with action overriding the configured
+// unit action: stop, restart or kill the unit owning procInfo through
+// systemctl, or signal the process directly. UnitAuto leaves the choice to
+// the policy and configuration.
+func (m *Manager) StartUnitAction(ctx context.Context, procInfo *monitor.ProcessInfo, action safety.UnitAction) (*Termination, error) {
+	if action == safety.UnitAuto {
+		action = ""
+	}
+	return m.start(ctx, procInfo, false, override{unit: action})
+}
+
+// StartContainerAction is StartTerminate with action overriding the
+// configured container action: stop or pause the container running
+// procInfo through its runtime, or signal the process directly.
+func (m *Manager) StartContainerAction(ctx context.Context, procInfo *monitor.ProcessInfo, action safety.ContainerAction) (*Termination, error) {
+	return m.start(ctx, procInfo, false, override{container: action})
+}
+
+func (m *Manager) start(ctx context.Context, procInfo *monitor.ProcessInfo, force bool, o override) (*Termination, error) {
+	p, err := m.validate(procInfo, force, o)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	if m.inflight[procInfo.PID] != nil {
+		m.mu.Unlock()
+		return nil, fmt.Errorf("%s (PID %d) is already being terminated", procInfo.Name, procInfo.PID)
+	}
+	t := &Termination{
+		Proc:    procInfo,
+		Started: time.Now(),
+		events:  make(chan Progress, 16),
+		done:    make(chan struct{}),
+	}
+	t.ctx, t.cancel = context.WithCancel(ctx)
+	if m.inflight == nil {
+		m.inflight = make(map[int]*Termination)
+	}
+	m.inflight[procInfo.PID] = t
+	m.mu.Unlock()
+
+	t.report(Progress{Stage: StageStarted})
+	go func() {
+		result, err := m.run(t, p, force)
+		if err != nil {
+			m.discard(procInfo)
+		}
+		m.mu.Lock()
+		delete(m.inflight, procInfo.PID)
+		m.mu.Unlock()
+		t.finish(result, err)
+	}()
+	return t, nil
+}
+
+// Terminations returns the terminations in flight, oldest first.
+func (m *Manager) Terminations() []*Termination {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ts := make([]*Termination, 0, len(m.inflight))
+	for _, t := range m.inflight {
+		ts = append(ts, t)
+	}
+	sort.Slice(ts, func(i, j int) bool { return ts[i].Started.Before(ts[j].Started) })
+	return ts
+}
+
+// Terminating returns the termination of pid in flight, or nil.
+func (m *Manager) Terminating(pid int) *Termination {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.inflight[pid]
+}
@@ -0,0 +1,165 @@
+package safety
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/iamgilwell/aura/internal/monitor"
+)
+
+// Budget limits the blast radius of automatic terminations. Zero values
+// disable the corresponding limit.
+type Budget struct {
+	MaxPerMinute int
+	MaxPerHour   int
+	MaxPerUser   int // per user, per hour
+
+	MaxMemoryMB  float64 // estimated memory freed per MemoryWindow
+	MemoryWindow time.Duration
+
+	// Maximum fraction of the processes sharing an executable that may be
+	// terminated within an hour. Only applies to groups of two or more.
+	MaxExeShare float64
+}
+
+type budgetRecord struct {
+	at       time.Time
+	pid      int
+	user     string
+	exe      string
+	memoryMB float64
+}
+
+// SetBudget installs the limits checked by CheckAutomatic.
+func (m *Manager) SetBudget(b Budget) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.budget = b
+}
+
+// ObserveProcesses records how many live processes run each executable,
+// for the same-executable share limit. Call it after every scan.
+func (m *Manager) ObserveProcesses(procs []*monitor.ProcessInfo) {
+	counts := make(map[string]int)
+	for _, p := range procs {
+		if p.Exe != "" {
+			counts[p.Exe]++
+		}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.exeCounts = counts
+	m.observedAt = time.Now()
+}
+
+// CheckAutomatic checks an automatic termination of proc against the
+// budgets. If a budget would be exceeded the manager trips into
+// monitor-only mode and the reason is returned. Once the termination has
+// started, count it with ChargeAutomatic.
+func (m *Manager) CheckAutomatic(proc *monitor.ProcessInfo) (bool, string) {
+	if m.IsMonitorOnly() {
+		return false, "monitor-only mode"
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	m.pruneBudget(now)
+
+	if reason := m.exceeded(proc, now); reason != "" {
+		m.tripped = fmt.Sprintf("budget exceeded: %s", reason)
+		m.consentMgr.SetLevel(ConsentMonitorOnly)
+		return false, m.tripped
+	}
+	return true, ""
+}
+
+// ChargeAutomatic counts an automatic termination of proc against the
+// budgets.
+func (m *Manager) ChargeAutomatic(proc *monitor.ProcessInfo) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.charged = append(m.charged, budgetRecord{at: time.Now(), pid: proc.PID, user: proc.User, exe: proc.Exe, memoryMB: proc.MemoryMB})
+}
+
+// RefundAutomatic takes back the last charge for proc, for a termination
+// that was vetoed or cancelled before it took anything down.
+func (m *Manager) RefundAutomatic(proc *monitor.ProcessInfo) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := len(m.charged) - 1; i >= 0; i-- {
+		if m.charged[i].pid == proc.PID {
+			m.charged = append(m.charged[:i], m.charged[i+1:]...)
+			return
+		}
+	}
+}
+
+// Tripped returns the reason the budget tripped, or "" if it has not.
+func (m *Manager) Tripped() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.tripped
+}
+
+func (m *Manager) exceeded(proc *monitor.ProcessInfo, now time.Time) string {
+	b := m.budget
+	var lastMinute, lastHour, user, exe, unobserved int
+	var memory float64
+	for _, r := range m.charged {
+		if now.Sub(r.at) <= time.Minute {
+			lastMinute++
+		}
+		if now.Sub(r.at) <= time.Hour {
+			lastHour++
+			if r.user == proc.User {
+				user++
+			}
+			if proc.Exe != "" && r.exe == proc.Exe {
+				exe++
+				if r.at.After(m.observedAt) {
+					unobserved++
+				}
+			}
+		}
+		if now.Sub(r.at) <= b.MemoryWindow {
+			memory += r.memoryMB
+		}
+	}
+
+	switch {
+	case b.MaxPerMinute > 0 && lastMinute >= b.MaxPerMinute:
+		return fmt.Sprintf("%d terminations in the last minute (max %d)", lastMinute, b.MaxPerMinute)
+	case b.MaxPerHour > 0 && lastHour >= b.MaxPerHour:
+		return fmt.Sprintf("%d terminations in the last hour (max %d)", lastHour, b.MaxPerHour)
+	case b.MaxPerUser > 0 && user >= b.MaxPerUser:
+		return fmt.Sprintf("%d terminations of %s's processes in the last hour (max %d)", user, proc.User, b.MaxPerUser)
+	case b.MaxMemoryMB > 0 && memory+proc.MemoryMB > b.MaxMemoryMB:
+		return fmt.Sprintf("%.0f MB freed in %s (max %.0f MB)", memory+proc.MemoryMB, b.MemoryWindow, b.MaxMemoryMB)
+	}
+
+	if b.MaxExeShare > 0 && proc.Exe != "" {
+		// Processes terminated before the last scan are gone from the live
+		// count; those terminated since are still in it
+		group := m.exeCounts[proc.Exe] - unobserved + exe
+		if group > 1 && float64(exe+1)/float64(group) > b.MaxExeShare {
+			return fmt.Sprintf("%d of %d %s processes terminated in the last hour (max %.0f%%)",
+				exe+1, group, proc.Exe, b.MaxExeShare*100)
+		}
+	}
+	return ""
+}
+
+// pruneBudget drops records older than every budget window.
+func (m *Manager) pruneBudget(now time.Time) {
+	keep := time.Hour
+	if m.budget.MemoryWindow > keep {
+		keep = m.budget.MemoryWindow
+	}
+	i := 0
+	for i < len(m.charged) && now.Sub(m.charged[i].at) > keep {
+		i++
+	}
+	m.charged = m.charged[i:]
+}
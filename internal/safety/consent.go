@@ -1,34 +1,138 @@
 package safety
 
 import (
+	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/iamgilwell/aura/internal/monitor"
 )
 
-// Consent levels.
+// Consent levels, kept as presets for the consent matrix.
 const (
-	ConsentAutomatic   = 0 // Fully automatic
+	ConsentAutomatic    = 0 // Fully automatic
 	ConsentNotifySystem = 1 // Notify for system processes
-	ConsentConfirmAll  = 2 // Confirm all terminations
-	ConsentMonitorOnly = 3 // Monitoring only, no terminations
+	ConsentConfirmAll   = 2 // Confirm all terminations
+	ConsentMonitorOnly  = 3 // Monitoring only, no terminations
 )
 
-// ConsentManager handles user consent levels for process termination.
+// Action is an intervention Aura can take on a process.
+type Action string
+
+const (
+	ActionTerminate Action = "terminate"
+	ActionThrottle  Action = "throttle"
+	ActionSuspend   Action = "suspend"
+	ActionLimit     Action = "limit"
+)
+
+// Actions lists every action in the consent matrix.
+var Actions = []Action{ActionTerminate, ActionThrottle, ActionSuspend, ActionLimit}
+
+// Past returns the past tense of the action, as used for audit events.
+func (a Action) Past() string {
+	switch a {
+	case ActionThrottle:
+		return "throttled"
+	case ActionSuspend:
+		return "suspended"
+	case ActionLimit:
+		return "limited"
+	default:
+		return "terminated"
+	}
+}
+
+// Mode is how much consent an action needs.
+type Mode string
+
+const (
+	ModeAuto    Mode = "auto"    // act without asking
+	ModeNotify  Mode = "notify"  // act and tell the user
+	ModeConfirm Mode = "confirm" // ask before acting
+	ModeForbid  Mode = "forbid"  // never act
+)
+
+var modeRank = map[Mode]int{ModeAuto: 0, ModeNotify: 1, ModeConfirm: 2, ModeForbid: 3}
+
+// Matrix maps a process category ("user", "system", "essential",
+// "kernel"), a policy tag ("tag:<name>") or "*" to the mode of each action.
+type Matrix map[string]map[Action]Mode
+
+// Preset returns the matrix equivalent to one of the consent levels.
+func Preset(level int) Matrix {
+	fill := func(mode Mode) map[Action]Mode {
+		row := make(map[Action]Mode, len(Actions))
+		for _, a := range Actions {
+			row[a] = mode
+		}
+		return row
+	}
+
+	m := Matrix{"kernel": fill(ModeForbid)}
+	switch level {
+	case ConsentAutomatic:
+		m["*"] = fill(ModeAuto)
+	case ConsentNotifySystem:
+		m["*"] = fill(ModeAuto)
+		m["system"] = fill(ModeConfirm)
+		m["essential"] = fill(ModeConfirm)
+	case ConsentConfirmAll:
+		m["*"] = fill(ModeConfirm)
+	default:
+		m["*"] = fill(ModeForbid)
+	}
+	return m
+}
+
+// ParseMatrix converts a configuration map such as
+// {"user": {"terminate": "confirm", "throttle": "auto"}} into a Matrix.
+func ParseMatrix(raw map[string]map[string]string) (Matrix, error) {
+	m := make(Matrix, len(raw))
+	for key, row := range raw {
+		key = strings.ToLower(key)
+		if key != "*" && !strings.HasPrefix(key, "tag:") {
+			if _, ok := parseCategory(key); !ok {
+				return nil, fmt.Errorf("consent matrix: unknown category %q", key)
+			}
+		}
+		m[key] = make(map[Action]Mode, len(row))
+		for action, mode := range row {
+			a, md := Action(strings.ToLower(action)), Mode(strings.ToLower(mode))
+			if !validAction(a) {
+				return nil, fmt.Errorf("consent matrix: unknown action %q for %q", action, key)
+			}
+			if _, ok := modeRank[md]; !ok {
+				return nil, fmt.Errorf("consent matrix: unknown mode %q for %s/%s", mode, key, action)
+			}
+			m[key][a] = md
+		}
+	}
+	return m, nil
+}
+
+func validAction(a Action) bool {
+	for _, known := range Actions {
+		if a == known {
+			return true
+		}
+	}
+	return false
+}
+
+// ConsentManager handles user consent for interventions on processes.
 type ConsentManager struct {
-	mu    sync.RWMutex
-	level int
+	mu        sync.RWMutex
+	level     int
+	overrides Matrix
+	matrix    Matrix
 }
 
 // NewConsentManager creates a consent manager with the given level.
 func NewConsentManager(level int) *ConsentManager {
-	if level < 0 {
-		level = 0
-	}
-	if level > 3 {
-		level = 3
-	}
-	return &ConsentManager{level: level}
+	c := &ConsentManager{}
+	c.SetLevel(level)
+	return c
 }
 
 // Level returns the current consent level.
@@ -38,7 +142,8 @@ func (c *ConsentManager) Level() int {
 	return c.level
 }
 
-// SetLevel sets the consent level (0-3).
+// SetLevel sets the consent level (0-3), resetting the matrix to its
+// preset with the configured overrides applied on top.
 func (c *ConsentManager) SetLevel(level int) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -49,26 +154,62 @@ func (c *ConsentManager) SetLevel(level int) {
 		level = 3
 	}
 	c.level = level
+	c.rebuild()
 }
 
-// NeedsConfirmation returns whether user confirmation is needed.
-func (c *ConsentManager) NeedsConfirmation(proc *monitor.ProcessInfo) bool {
+// SetOverrides installs matrix cells that take precedence over the preset.
+func (c *ConsentManager) SetOverrides(m Matrix) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.overrides = m
+	c.rebuild()
+}
+
+func (c *ConsentManager) rebuild() {
+	m := Preset(c.level)
+	// Monitor-only is absolute: overrides cannot re-enable actions
+	if c.level != ConsentMonitorOnly {
+		for key, row := range c.overrides {
+			if m[key] == nil {
+				m[key] = make(map[Action]Mode)
+			}
+			for a, mode := range row {
+				m[key][a] = mode
+			}
+		}
+	}
+	c.matrix = m
+}
+
+// Mode returns the consent mode for action on proc. Policy tags take
+// precedence over the category (the most restrictive tag wins), then the
+// "*" row; cells missing everywhere default to confirm.
+func (c *ConsentManager) Mode(proc *monitor.ProcessInfo, action Action, tags []string) Mode {
 	c.mu.RLock()
-	level := c.level
-	c.mu.RUnlock()
+	defer c.mu.RUnlock()
 
-	switch level {
-	case ConsentAutomatic:
-		return false
-	case ConsentNotifySystem:
-		return proc.Category == monitor.CategorySystem || proc.Category == monitor.CategoryEssential
-	case ConsentConfirmAll:
-		return true
-	case ConsentMonitorOnly:
-		return true // Always block in monitor-only mode
-	default:
-		return true
+	var found Mode
+	for _, tag := range tags {
+		if mode, ok := c.matrix["tag:"+strings.ToLower(tag)][action]; ok && (found == "" || modeRank[mode] > modeRank[found]) {
+			found = mode
+		}
+	}
+	if found != "" {
+		return found
+	}
+	if mode, ok := c.matrix[strings.ToLower(proc.Category.String())][action]; ok {
+		return mode
+	}
+	if mode, ok := c.matrix["*"][action]; ok {
+		return mode
 	}
+	return ModeConfirm
+}
+
+// NeedsConfirmation returns whether terminating proc needs user
+// confirmation (or is forbidden outright).
+func (c *ConsentManager) NeedsConfirmation(proc *monitor.ProcessInfo) bool {
+	return modeRank[c.Mode(proc, ActionTerminate, nil)] >= modeRank[ModeConfirm]
 }
 
 // LevelDescription returns a human-readable description of a consent level.
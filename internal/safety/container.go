@@ -0,0 +1,30 @@
+package safety
+
+import "fmt"
+
+// ContainerAction is what a termination does to a process running in a
+// container.
+type ContainerAction string
+
+const (
+	ContainerSignal ContainerAction = "signal" // signal the process, ignoring its container
+	ContainerStop   ContainerAction = "stop"   // stop the container through its runtime
+	ContainerPause  ContainerAction = "pause"  // freeze the container; unpausing brings it back as it was
+)
+
+// ContainerActions lists every container action.
+var ContainerActions = []ContainerAction{ContainerSignal, ContainerStop, ContainerPause}
+
+// ParseContainerAction validates a container action name. An empty name is
+// ContainerSignal.
+func ParseContainerAction(s string) (ContainerAction, error) {
+	if s == "" {
+		return ContainerSignal, nil
+	}
+	for _, a := range ContainerActions {
+		if ContainerAction(s) == a {
+			return a, nil
+		}
+	}
+	return "", fmt.Errorf("unknown container action %q (want signal, stop or pause)", s)
+}
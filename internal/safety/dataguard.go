@@ -0,0 +1,186 @@
+package safety
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/iamgilwell/aura/internal/monitor"
+)
+
+// swapSuffixes identify editor swap and autosave files.
+var swapSuffixes = []string{".swp", ".swo", ".swn", ".swx", ".kate-swp"}
+
+// DataRisk lists signs that terminating a process could lose data.
+type DataRisk struct {
+	Locks     []string // held file locks, e.g. "POSIX WRITE /var/lib/app/db"
+	Writable  []string // regular files open for writing, excluding append-only logs
+	SwapFiles []string // editor swap files held open
+	WriteRate float64  // write_bytes growth in bytes per second
+}
+
+// Possible reports whether the process holds anything that could be lost.
+func (r DataRisk) Possible() bool {
+	return len(r.Locks) > 0 || len(r.Writable) > 0 || len(r.SwapFiles) > 0
+}
+
+// String describes the risk for verdict reasons.
+func (r DataRisk) String() string {
+	var parts []string
+	if len(r.SwapFiles) > 0 {
+		parts = append(parts, "editor swap file "+summarize(r.SwapFiles))
+	}
+	if len(r.Locks) > 0 {
+		parts = append(parts, "holds lock on "+summarize(r.Locks))
+	}
+	if len(r.Writable) > 0 {
+		parts = append(parts, "open for writing: "+summarize(r.Writable))
+	}
+	if r.WriteRate > 0 {
+		parts = append(parts, fmt.Sprintf("writing %.0f KB/s", r.WriteRate/1024))
+	}
+	return strings.Join(parts, "; ")
+}
+
+func summarize(items []string) string {
+	if len(items) <= 2 {
+		return strings.Join(items, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(items[:2], ", "), len(items)-2)
+}
+
+// DataGuard inspects processes for held locks, files open for writing and
+// editor swap files before they are terminated.
+type DataGuard struct {
+	// WriteRate is the write_bytes growth, in bytes per second, above which
+	// a process holding locks or writable files is considered mid-write
+	WriteRate float64
+}
+
+// Likely reports whether terminating a process with risk r would probably
+// lose data: it is writing right now to files it holds.
+func (g *DataGuard) Likely(r DataRisk) bool {
+	return g.WriteRate > 0 && r.WriteRate >= g.WriteRate && (len(r.Locks) > 0 || len(r.Writable) > 0)
+}
+
+// Inspect reads the locks and open files of proc. Processes whose file
+// descriptors cannot be read (other users without root) report only what
+// /proc/locks shows.
+func (g *DataGuard) Inspect(proc *monitor.ProcessInfo) DataRisk {
+	risk := DataRisk{WriteRate: proc.IOWriteRate}
+
+	fdDir := fmt.Sprintf("/proc/%d/fd", proc.PID)
+	entries, _ := os.ReadDir(fdDir)
+	byInode := make(map[string]string)
+	for _, e := range entries {
+		link := filepath.Join(fdDir, e.Name())
+		target, err := os.Readlink(link)
+		if err != nil || !strings.HasPrefix(target, "/") || strings.HasSuffix(target, " (deleted)") {
+			continue
+		}
+		if strings.HasPrefix(target, "/dev/") || strings.HasPrefix(target, "/proc/") || strings.HasPrefix(target, "/sys/") {
+			continue
+		}
+
+		var st syscall.Stat_t
+		if err := syscall.Stat(link, &st); err != nil || st.Mode&syscall.S_IFMT != syscall.S_IFREG {
+			continue
+		}
+		byInode[lockKey(st.Dev, st.Ino)] = target
+
+		if isSwapFile(target) {
+			risk.SwapFiles = appendUnique(risk.SwapFiles, target)
+			continue
+		}
+		flags := fdFlags(proc.PID, e.Name())
+		if acc := flags & syscall.O_ACCMODE; (acc == syscall.O_WRONLY || acc == syscall.O_RDWR) && flags&syscall.O_APPEND == 0 {
+			risk.Writable = appendUnique(risk.Writable, target)
+		}
+	}
+
+	data, err := os.ReadFile("/proc/locks")
+	if err != nil {
+		return risk
+	}
+	pid := strconv.Itoa(proc.PID)
+	for _, line := range strings.Split(string(data), "\n") {
+		// 1: POSIX  ADVISORY  WRITE 1234 08:01:5678 0 EOF
+		fields := strings.Fields(line)
+		if len(fields) < 6 || fields[1] == "->" || fields[4] != pid {
+			continue
+		}
+		file, ok := byInode[fields[5]]
+		if !ok {
+			file = "inode " + fields[5]
+		}
+		risk.Locks = appendUnique(risk.Locks, fmt.Sprintf("%s %s %s", fields[1], fields[3], file))
+	}
+	return risk
+}
+
+// lockKey formats a device and inode the way /proc/locks does.
+func lockKey(dev, ino uint64) string {
+	major := (dev>>8)&0xfff | (dev>>32)&^uint64(0xfff)
+	minor := dev&0xff | (dev>>12)&^uint64(0xff)
+	return fmt.Sprintf("%02x:%02x:%d", major, minor, ino)
+}
+
+// fdFlags returns the open flags of a file descriptor from fdinfo.
+func fdFlags(pid int, fd string) int {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/fdinfo/%s", pid, fd))
+	if err != nil {
+		return 0
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if v, ok := strings.CutPrefix(line, "flags:"); ok {
+			flags, _ := strconv.ParseInt(strings.TrimSpace(v), 8, 64)
+			return int(flags)
+		}
+	}
+	return 0
+}
+
+func isSwapFile(path string) bool {
+	base := filepath.Base(path)
+	if strings.HasPrefix(base, "#") && strings.HasSuffix(base, "#") && len(base) > 2 {
+		return true
+	}
+	for _, suffix := range swapSuffixes {
+		if strings.HasSuffix(base, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, existing := range list {
+		if existing == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
+
+// SetDataGuard enables the data-loss check for terminations. A nil guard
+// disables it.
+func (m *Manager) SetDataGuard(g *DataGuard) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.guard = g
+}
+
+// DataRisk inspects proc with the installed data guard. ok is false when
+// the guard is disabled.
+func (m *Manager) DataRisk(proc *monitor.ProcessInfo) (risk DataRisk, ok bool) {
+	m.mu.RLock()
+	g := m.guard
+	m.mu.RUnlock()
+	if g == nil {
+		return DataRisk{}, false
+	}
+	return g.Inspect(proc), true
+}
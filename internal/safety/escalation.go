@@ -0,0 +1,111 @@
+package safety
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Step is one rung of an escalation ladder: a signal, then how long to wait
+// for the process to exit before the next rung. A zero Wait on the last rung
+// means safety.terminate_timeout.
+type Step struct {
+	Signal syscall.Signal
+	Wait   time.Duration
+}
+
+// Escalation is the sequence of signals a termination sends until the
+// process exits, e.g. "SIGINT 3s SIGTERM 10s SIGKILL". A ladder that does
+// not end in SIGKILL leaves a process that outlasts it running.
+type Escalation []Step
+
+// signals are the signal names accepted in escalation ladders.
+var signals = map[string]syscall.Signal{
+	"SIGHUP":  syscall.SIGHUP,
+	"SIGINT":  syscall.SIGINT,
+	"SIGQUIT": syscall.SIGQUIT,
+	"SIGUSR1": syscall.SIGUSR1,
+	"SIGUSR2": syscall.SIGUSR2,
+	"SIGTERM": syscall.SIGTERM,
+	"SIGKILL": syscall.SIGKILL,
+}
+
+// DefaultEscalation is SIGTERM, then SIGKILL after timeout.
+func DefaultEscalation(timeout time.Duration) Escalation {
+	return Escalation{{Signal: syscall.SIGTERM, Wait: timeout}, {Signal: syscall.SIGKILL}}
+}
+
+// ParseEscalation parses a ladder of signal names (with or without the SIG
+// prefix) or numbers, each optionally followed by a duration to wait,
+// separated by spaces or commas. An empty ladder is nil.
+func ParseEscalation(s string) (Escalation, error) {
+	var esc Escalation
+	for _, tok := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' }) {
+		if d, err := time.ParseDuration(tok); err == nil {
+			switch {
+			case len(esc) == 0:
+				return nil, fmt.Errorf("escalation %q: wait %s before any signal", s, tok)
+			case esc[len(esc)-1].Wait != 0:
+				return nil, fmt.Errorf("escalation %q: two waits after %s", s, esc[len(esc)-1].Signal)
+			case d <= 0:
+				return nil, fmt.Errorf("escalation %q: wait %s is not positive", s, tok)
+			}
+			esc[len(esc)-1].Wait = d
+			continue
+		}
+		sig, err := parseSignal(tok)
+		if err != nil {
+			return nil, fmt.Errorf("escalation %q: %w", s, err)
+		}
+		esc = append(esc, Step{Signal: sig})
+	}
+	return esc, nil
+}
+
+func parseSignal(s string) (syscall.Signal, error) {
+	if n, err := strconv.Atoi(s); err == nil {
+		for _, sig := range signals {
+			if int(sig) == n {
+				return sig, nil
+			}
+		}
+		return 0, fmt.Errorf("signal %d cannot terminate a process", n)
+	}
+	name := strings.ToUpper(s)
+	if !strings.HasPrefix(name, "SIG") {
+		name = "SIG" + name
+	}
+	if sig, ok := signals[name]; ok {
+		return sig, nil
+	}
+	return 0, fmt.Errorf("unknown signal %q (want HUP, INT, QUIT, USR1, USR2, TERM or KILL)", s)
+}
+
+// SignalName returns the conventional name of sig, e.g. "SIGTERM".
+func SignalName(sig syscall.Signal) string {
+	for name, s := range signals {
+		if s == sig {
+			return name
+		}
+	}
+	return fmt.Sprintf("signal %d", int(sig))
+}
+
+// Kills reports whether the ladder ends in SIGKILL.
+func (e Escalation) Kills() bool {
+	return len(e) > 0 && e[len(e)-1].Signal == syscall.SIGKILL
+}
+
+// String formats the ladder the way ParseEscalation reads it.
+func (e Escalation) String() string {
+	parts := make([]string, 0, 2*len(e))
+	for _, st := range e {
+		parts = append(parts, SignalName(st.Signal))
+		if st.Wait > 0 {
+			parts = append(parts, st.Wait.String())
+		}
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,78 @@
+package safety
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Limits are resource limits and an OOM score adjustment applied to a
+// process instead of terminating it. Only soft limits are lowered, so
+// whoever applied them can revert them. Zero fields are left alone.
+type Limits struct {
+	AddressSpaceMB float64       `mapstructure:"address_space_mb" json:"address_space_mb,omitempty"` // RLIMIT_AS
+	CPUTime        time.Duration `mapstructure:"cpu_time" json:"cpu_time,omitempty"`                 // RLIMIT_CPU, counted from when the limit is applied
+	OpenFiles      int           `mapstructure:"open_files" json:"open_files,omitempty"`             // RLIMIT_NOFILE
+	OOMScoreAdj    int           `mapstructure:"oom_score_adj" json:"oom_score_adj,omitempty"`       // -1000 to 1000
+}
+
+// IsZero reports whether l changes nothing.
+func (l Limits) IsZero() bool {
+	return l == Limits{}
+}
+
+// Validate checks that every field is in range.
+func (l Limits) Validate() error {
+	switch {
+	case l.AddressSpaceMB < 0:
+		return fmt.Errorf("address_space_mb must not be negative")
+	case l.CPUTime < 0:
+		return fmt.Errorf("cpu_time must not be negative")
+	case l.CPUTime > 0 && l.CPUTime < time.Second:
+		return fmt.Errorf("cpu_time must be at least 1s")
+	case l.OpenFiles < 0:
+		return fmt.Errorf("open_files must not be negative")
+	case l.OOMScoreAdj < -1000 || l.OOMScoreAdj > 1000:
+		return fmt.Errorf("oom_score_adj must be between -1000 and 1000")
+	}
+	return nil
+}
+
+// Merge returns l with the non-zero fields of o replacing its own.
+func (l Limits) Merge(o Limits) Limits {
+	if o.AddressSpaceMB != 0 {
+		l.AddressSpaceMB = o.AddressSpaceMB
+	}
+	if o.CPUTime != 0 {
+		l.CPUTime = o.CPUTime
+	}
+	if o.OpenFiles != 0 {
+		l.OpenFiles = o.OpenFiles
+	}
+	if o.OOMScoreAdj != 0 {
+		l.OOMScoreAdj = o.OOMScoreAdj
+	}
+	return l
+}
+
+// String describes the limits, e.g. "address space 2048 MB, 256 open
+// files, oom_score_adj 500".
+func (l Limits) String() string {
+	var parts []string
+	if l.AddressSpaceMB > 0 {
+		parts = append(parts, fmt.Sprintf("address space %.0f MB", l.AddressSpaceMB))
+	}
+	if l.CPUTime > 0 {
+		parts = append(parts, fmt.Sprintf("%s more CPU time", l.CPUTime))
+	}
+	if l.OpenFiles > 0 {
+		parts = append(parts, fmt.Sprintf("%d open files", l.OpenFiles))
+	}
+	if l.OOMScoreAdj != 0 {
+		parts = append(parts, fmt.Sprintf("oom_score_adj %d", l.OOMScoreAdj))
+	}
+	if len(parts) == 0 {
+		return "no limits"
+	}
+	return strings.Join(parts, ", ")
+}
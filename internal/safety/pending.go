@@ -0,0 +1,243 @@
+package safety
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/iamgilwell/aura/internal/monitor"
+)
+
+// ErrPendingExpired is returned by Take for an item whose approval window
+// has passed. The expired item is still returned so it can be audited.
+var ErrPendingExpired = errors.New("approval request expired")
+
+// PendingItem is a recommended action waiting for user approval.
+type PendingItem struct {
+	ID          string    `json:"id"`
+	Action      Action    `json:"action,omitempty"` // empty means terminate
+	PID         int       `json:"pid"`
+	Name        string    `json:"name"`
+	Exe         string    `json:"exe,omitempty"`
+	Cmdline     string    `json:"cmdline,omitempty"`
+	User        string    `json:"user,omitempty"`
+	StartTime   time.Time `json:"start_time"`
+	Reason      string    `json:"reason"`
+	Confidence  float64   `json:"confidence,omitempty"`
+	SavingsWatt float64   `json:"savings_watt,omitempty"`
+	Source      string    `json:"source,omitempty"` // yolo, interactive
+	CreatedAt   time.Time `json:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// NewPendingItem describes action on proc for the approval queue.
+func NewPendingItem(proc *monitor.ProcessInfo, action Action, reason string) PendingItem {
+	return PendingItem{
+		Action:    action,
+		PID:       proc.PID,
+		Name:      proc.Name,
+		Exe:       proc.Exe,
+		Cmdline:   proc.Cmdline,
+		User:      proc.User,
+		StartTime: proc.StartTime,
+		Reason:    reason,
+	}
+}
+
+// Intervention returns the action to take on approval.
+func (it PendingItem) Intervention() Action {
+	if it.Action == "" {
+		return ActionTerminate
+	}
+	return it.Action
+}
+
+// Matches reports whether proc is the process the item was created for,
+// guarding against PID reuse between parking and approval.
+func (it PendingItem) Matches(proc *monitor.ProcessInfo) bool {
+	return proc.PID == it.PID &&
+		proc.StartTime.Equal(it.StartTime) &&
+		proc.Exe == it.Exe &&
+		proc.Cmdline == it.Cmdline
+}
+
+// PendingQueue is a durable queue of items awaiting approval, shared
+// between Aura processes through a JSON file guarded by a lock file.
+type PendingQueue struct {
+	mu    sync.Mutex
+	path  string
+	ttl   time.Duration
+	items []PendingItem // used when path is empty
+}
+
+// NewPendingQueue creates a queue stored at path whose items expire after
+// ttl. An empty path keeps the queue in memory.
+func NewPendingQueue(path string, ttl time.Duration) *PendingQueue {
+	return &PendingQueue{path: path, ttl: ttl}
+}
+
+// Add parks item unless the same action on the same process is already
+// waiting. It returns the stored item and whether it was newly added.
+func (q *PendingQueue) Add(item PendingItem) (PendingItem, bool, error) {
+	var added bool
+	err := q.update(func(items []PendingItem) []PendingItem {
+		now := time.Now()
+		for _, existing := range items {
+			if existing.PID == item.PID && existing.StartTime.Equal(item.StartTime) &&
+				existing.Intervention() == item.Intervention() && now.Before(existing.ExpiresAt) {
+				item = existing
+				return items
+			}
+		}
+		item.ID = newID()
+		item.CreatedAt = now
+		item.ExpiresAt = now.Add(q.ttl)
+		added = true
+		return append(items, item)
+	})
+	return item, added, err
+}
+
+// List returns the items that have not expired, oldest first.
+func (q *PendingQueue) List() ([]PendingItem, error) {
+	var result []PendingItem
+	err := q.update(func(items []PendingItem) []PendingItem {
+		now := time.Now()
+		for _, it := range items {
+			if now.Before(it.ExpiresAt) {
+				result = append(result, it)
+			}
+		}
+		return items
+	})
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.Before(result[j].CreatedAt) })
+	return result, err
+}
+
+// Take removes and returns the item with the given ID. Expired items are
+// removed too and reported with ErrPendingExpired.
+func (q *PendingQueue) Take(id string) (PendingItem, error) {
+	var found *PendingItem
+	err := q.update(func(items []PendingItem) []PendingItem {
+		kept := items[:0]
+		for _, it := range items {
+			if it.ID == id {
+				it := it
+				found = &it
+				continue
+			}
+			kept = append(kept, it)
+		}
+		return kept
+	})
+	if err != nil {
+		return PendingItem{}, err
+	}
+	if found == nil {
+		return PendingItem{}, fmt.Errorf("no pending approval with ID %q", id)
+	}
+	if !time.Now().Before(found.ExpiresAt) {
+		return *found, ErrPendingExpired
+	}
+	return *found, nil
+}
+
+// Requeue puts back an item taken for approval whose action failed, under
+// its ID and approval window, so it can be approved again or denied. It does
+// nothing if the same action on the same process was parked again since.
+func (q *PendingQueue) Requeue(item PendingItem) error {
+	return q.update(func(items []PendingItem) []PendingItem {
+		for _, existing := range items {
+			if existing.PID == item.PID && existing.StartTime.Equal(item.StartTime) &&
+				existing.Intervention() == item.Intervention() {
+				return items
+			}
+		}
+		return append(items, item)
+	})
+}
+
+// Expire removes and returns items whose approval window has passed.
+func (q *PendingQueue) Expire() ([]PendingItem, error) {
+	var expired []PendingItem
+	err := q.update(func(items []PendingItem) []PendingItem {
+		now := time.Now()
+		kept := items[:0]
+		for _, it := range items {
+			if now.Before(it.ExpiresAt) {
+				kept = append(kept, it)
+			} else {
+				expired = append(expired, it)
+			}
+		}
+		return kept
+	})
+	return expired, err
+}
+
+// update runs fn on the stored items under an exclusive lock and writes the
+// result back.
+func (q *PendingQueue) update(fn func([]PendingItem) []PendingItem) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.path == "" {
+		q.items = fn(q.items)
+		return nil
+	}
+
+	return updateFile(q.path, "pending queue", fn)
+}
+
+// updateFile runs fn on the JSON list stored at path under an exclusive
+// lock file and atomically writes the result back. what names the store in
+// error messages.
+func updateFile[T any](path, what string, fn func([]T) []T) error {
+	lock, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return fmt.Errorf("opening %s lock: %w", what, err)
+	}
+	defer lock.Close()
+	if err := syscall.Flock(int(lock.Fd()), syscall.LOCK_EX); err != nil {
+		return fmt.Errorf("locking %s: %w", what, err)
+	}
+	defer syscall.Flock(int(lock.Fd()), syscall.LOCK_UN)
+
+	var items []T
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &items); err != nil {
+			return fmt.Errorf("parsing %s: %w", what, err)
+		}
+	case !os.IsNotExist(err):
+		return fmt.Errorf("reading %s: %w", what, err)
+	}
+
+	items = fn(items)
+
+	data, err = json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", what, err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("writing %s: %w", what, err)
+	}
+	return os.Rename(tmp, path)
+}
+
+func newID() string {
+	var b [4]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return fmt.Sprintf("%08x", time.Now().UnixNano()&0xffffffff)
+	}
+	return hex.EncodeToString(b[:])
+}
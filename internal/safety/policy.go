@@ -0,0 +1,309 @@
+package safety
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/iamgilwell/aura/internal/monitor"
+)
+
+// Effect is what a policy rule does to a matching process.
+type Effect string
+
+const (
+	EffectAllow   Effect = "allow"   // termination is allowed
+	EffectDeny    Effect = "deny"    // termination is refused
+	EffectConfirm Effect = "confirm" // termination requires user confirmation
+)
+
+// Match describes which processes a rule applies to. Every non-empty field
+// must match; list fields match if any element does.
+type Match struct {
+	Names       []string      `mapstructure:"name"`      // globs on the process name
+	Exe         []string      `mapstructure:"exe"`       // globs on the executable path
+	Cmdline     string        `mapstructure:"cmdline"`   // regular expression on the command line
+	Users       []string      `mapstructure:"user"`      // user names
+	UIDRange    []int         `mapstructure:"uid_range"` // inclusive [min, max]
+	Cgroup      string        `mapstructure:"cgroup"`    // glob on the cgroup path
+	Units       []string      `mapstructure:"unit"`      // globs on the systemd unit
+	Containers  []string      `mapstructure:"container"` // globs on the container name or ID
+	Categories  []string      `mapstructure:"category"`  // user, system, essential, kernel
+	MinAge      time.Duration `mapstructure:"min_age"`
+	MaxAge      time.Duration `mapstructure:"max_age"`
+	MinCPU      float64       `mapstructure:"min_cpu"`       // percent
+	MinMemory   float64       `mapstructure:"min_memory"`    // percent
+	MinMemoryMB float64       `mapstructure:"min_memory_mb"` // megabytes
+
+	Listening       bool  `mapstructure:"listening"`        // has any listening socket, including unix
+	ListenPorts     []int `mapstructure:"listen_ports"`     // listens on one of these TCP/UDP ports
+	ConnectionPorts []int `mapstructure:"connection_ports"` // established connection with one of these local or remote ports
+	MinConnections  int   `mapstructure:"min_connections"`  // at least this many established TCP/UDP connections
+
+	cmdlineRe *regexp.Regexp
+}
+
+// Rule is a named policy rule.
+type Rule struct {
+	Name     string   `mapstructure:"name"`
+	Effect   Effect   `mapstructure:"effect"`
+	Match    Match    `mapstructure:"match"`
+	Tags     []string `mapstructure:"tags"`     // consent matrix rows ("tag:<name>") for matching processes
+	Hooks    []string `mapstructure:"hooks"`    // names of safety.hooks run around terminations
+	Schedule string   `mapstructure:"schedule"` // name of a safety.schedules window the rule is limited to
+	Scope    Scope    `mapstructure:"scope"`    // termination scope for matching processes, overriding safety.termination_scope
+
+	// Signals sent to terminate matching processes, overriding
+	// safety.escalation, e.g. "SIGINT 3s SIGTERM 10s SIGKILL"
+	Escalation string `mapstructure:"escalation"`
+
+	// What to do to matching processes owned by a systemd unit, overriding
+	// safety.unit_action
+	UnitAction UnitAction `mapstructure:"unit_action"`
+
+	// What to do to matching processes running in a container, overriding
+	// safety.container_action
+	ContainerAction ContainerAction `mapstructure:"container_action"`
+
+	// Resource limits and OOM score adjustment for matching processes,
+	// applied by the limit action and by throttles instead of
+	// safety.throttle_limits
+	Limits Limits `mapstructure:"limits"`
+
+	escalation Escalation
+}
+
+// RuleTrace records whether a rule matched a process and, if not, why.
+type RuleTrace struct {
+	Rule     *Rule
+	Matched  bool
+	Mismatch string
+}
+
+// Policy is an ordered list of rules; the first matching rule wins.
+type Policy struct {
+	Rules []Rule
+
+	schedule *Schedule // windows referenced by rules, installed by the manager
+}
+
+// LoadPolicy reads a policy file with a top-level "rules" list.
+func LoadPolicy(path string) (*Policy, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("reading policy file: %w", err)
+	}
+
+	var file struct {
+		Rules []Rule `mapstructure:"rules"`
+	}
+	if err := v.Unmarshal(&file); err != nil {
+		return nil, fmt.Errorf("parsing policy file: %w", err)
+	}
+	return NewPolicy(file.Rules)
+}
+
+// NewPolicy validates rules and compiles their patterns.
+func NewPolicy(rules []Rule) (*Policy, error) {
+	for i := range rules {
+		r := &rules[i]
+		if r.Name == "" {
+			return nil, fmt.Errorf("rule %d: name is required", i+1)
+		}
+		switch r.Effect {
+		case EffectAllow, EffectDeny, EffectConfirm:
+		default:
+			return nil, fmt.Errorf("rule %q: unknown effect %q", r.Name, r.Effect)
+		}
+		if r.Match.Cmdline != "" {
+			re, err := regexp.Compile(r.Match.Cmdline)
+			if err != nil {
+				return nil, fmt.Errorf("rule %q: invalid cmdline pattern: %w", r.Name, err)
+			}
+			r.Match.cmdlineRe = re
+		}
+		if r.Scope != "" {
+			if _, err := ParseScope(string(r.Scope)); err != nil {
+				return nil, fmt.Errorf("rule %q: %w", r.Name, err)
+			}
+		}
+		if r.UnitAction != "" {
+			if _, err := ParseUnitAction(string(r.UnitAction)); err != nil {
+				return nil, fmt.Errorf("rule %q: %w", r.Name, err)
+			}
+		}
+		if r.ContainerAction != "" {
+			if _, err := ParseContainerAction(string(r.ContainerAction)); err != nil {
+				return nil, fmt.Errorf("rule %q: %w", r.Name, err)
+			}
+		}
+		if err := r.Limits.Validate(); err != nil {
+			return nil, fmt.Errorf("rule %q: limits: %w", r.Name, err)
+		}
+		esc, err := ParseEscalation(r.Escalation)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: %w", r.Name, err)
+		}
+		r.escalation = esc
+		if n := len(r.Match.UIDRange); n != 0 && n != 2 {
+			return nil, fmt.Errorf("rule %q: uid_range must be [min, max]", r.Name)
+		}
+		for _, c := range r.Match.Categories {
+			if _, ok := parseCategory(c); !ok {
+				return nil, fmt.Errorf("rule %q: unknown category %q", r.Name, c)
+			}
+		}
+		globs := append(append(append([]string{r.Match.Cgroup}, r.Match.Names...), r.Match.Exe...), r.Match.Units...)
+		for _, g := range append(globs, r.Match.Containers...) {
+			if _, err := filepath.Match(g, ""); err != nil {
+				return nil, fmt.Errorf("rule %q: invalid pattern %q: %w", r.Name, g, err)
+			}
+		}
+	}
+	return &Policy{Rules: rules}, nil
+}
+
+// Evaluate returns the first rule matching proc, or nil if none does.
+func (p *Policy) Evaluate(proc *monitor.ProcessInfo) *Rule {
+	if p == nil {
+		return nil
+	}
+	now := time.Now()
+	for i := range p.Rules {
+		if p.mismatch(&p.Rules[i], proc, now) == "" {
+			return &p.Rules[i]
+		}
+	}
+	return nil
+}
+
+// Trace evaluates every rule against proc, stopping at the first match.
+func (p *Policy) Trace(proc *monitor.ProcessInfo) []RuleTrace {
+	if p == nil {
+		return nil
+	}
+	var traces []RuleTrace
+	now := time.Now()
+	for i := range p.Rules {
+		why := p.mismatch(&p.Rules[i], proc, now)
+		traces = append(traces, RuleTrace{Rule: &p.Rules[i], Matched: why == "", Mismatch: why})
+		if why == "" {
+			break
+		}
+	}
+	return traces
+}
+
+// mismatch returns why rule does not apply to proc at now, or "" if it
+// does. A rule limited to a schedule only applies while the window is active.
+func (p *Policy) mismatch(rule *Rule, proc *monitor.ProcessInfo, now time.Time) string {
+	if rule.Schedule != "" && !p.schedule.Active(rule.Schedule, now) {
+		return fmt.Sprintf("outside schedule %q", rule.Schedule)
+	}
+	return rule.Match.mismatch(proc)
+}
+
+// mismatch returns the first criterion proc fails, or "" if it matches.
+func (m *Match) mismatch(proc *monitor.ProcessInfo) string {
+	if len(m.Names) > 0 && !matchAny(m.Names, proc.Name) {
+		return fmt.Sprintf("name %q", proc.Name)
+	}
+	if len(m.Exe) > 0 && !matchAny(m.Exe, proc.Exe) {
+		return fmt.Sprintf("exe %q", proc.Exe)
+	}
+	if m.cmdlineRe != nil && !m.cmdlineRe.MatchString(proc.Cmdline) {
+		return "cmdline"
+	}
+	if len(m.Users) > 0 && !containsFold(m.Users, proc.User) {
+		return fmt.Sprintf("user %q", proc.User)
+	}
+	if len(m.UIDRange) == 2 {
+		if proc.UID < m.UIDRange[0] || proc.UID > m.UIDRange[1] {
+			return fmt.Sprintf("uid %d", proc.UID)
+		}
+	}
+	if m.Cgroup != "" && !matchAny([]string{m.Cgroup}, proc.Cgroup) {
+		return fmt.Sprintf("cgroup %q", proc.Cgroup)
+	}
+	if len(m.Units) > 0 && !matchAny(m.Units, proc.Unit) {
+		return fmt.Sprintf("unit %q", proc.Unit)
+	}
+	if len(m.Containers) > 0 && (proc.Container == "" || !matchAny(m.Containers, proc.ContainerName) && !matchAny(m.Containers, proc.Container)) {
+		return fmt.Sprintf("container %q", proc.ContainerLabel())
+	}
+	if len(m.Categories) > 0 {
+		ok := false
+		for _, c := range m.Categories {
+			if cat, _ := parseCategory(c); cat == proc.Category {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return fmt.Sprintf("category %s", proc.Category)
+		}
+	}
+	age := time.Since(proc.StartTime)
+	if m.MinAge > 0 && age < m.MinAge {
+		return fmt.Sprintf("age %s < %s", age.Truncate(time.Second), m.MinAge)
+	}
+	if m.MaxAge > 0 && age > m.MaxAge {
+		return fmt.Sprintf("age %s > %s", age.Truncate(time.Second), m.MaxAge)
+	}
+	if m.MinCPU > 0 && proc.CPU < m.MinCPU {
+		return fmt.Sprintf("cpu %.1f%% < %.1f%%", proc.CPU, m.MinCPU)
+	}
+	if m.MinMemory > 0 && proc.Memory < m.MinMemory {
+		return fmt.Sprintf("memory %.1f%% < %.1f%%", proc.Memory, m.MinMemory)
+	}
+	if m.MinMemoryMB > 0 && proc.MemoryMB < m.MinMemoryMB {
+		return fmt.Sprintf("memory %.1fMB < %.1fMB", proc.MemoryMB, m.MinMemoryMB)
+	}
+	if m.Listening && len(proc.Listeners) == 0 {
+		return "no listening sockets"
+	}
+	if len(m.ListenPorts) > 0 && !proc.ListensOn(m.ListenPorts) {
+		return fmt.Sprintf("not listening on %v", m.ListenPorts)
+	}
+	if len(m.ConnectionPorts) > 0 && !proc.ConnectedOn(m.ConnectionPorts) {
+		return fmt.Sprintf("no connections on %v", m.ConnectionPorts)
+	}
+	if m.MinConnections > 0 && proc.NetConnections() < m.MinConnections {
+		return fmt.Sprintf("connections %d < %d", proc.NetConnections(), m.MinConnections)
+	}
+	return ""
+}
+
+func matchAny(patterns []string, s string) bool {
+	for _, p := range patterns {
+		if ok, _ := filepath.Match(p, s); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseCategory(s string) (monitor.ProcessCategory, bool) {
+	for _, c := range []monitor.ProcessCategory{
+		monitor.CategoryUser, monitor.CategorySystem, monitor.CategoryEssential, monitor.CategoryKernel,
+	} {
+		if strings.EqualFold(c.String(), s) {
+			return c, true
+		}
+	}
+	return 0, false
+}
@@ -2,7 +2,9 @@ package safety
 
 import (
 	"fmt"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/iamgilwell/aura/internal/monitor"
 )
@@ -13,6 +15,26 @@ type Manager struct {
 	protectedProcs map[string]bool
 	neverTerminate map[string]bool
 	consentMgr     *ConsentManager
+	baseLevel      int // consent level outside schedule windows
+	policy         *Policy
+	pins           *monitor.PinSet
+	guard          *DataGuard
+
+	// Maintenance windows switching the consent level by time
+	schedule *Schedule
+	applied  Settings // as of the last ApplySchedule
+
+	// Tree protection, recomputed every scan by the process manager
+	self      map[int]bool   // Aura and its ancestors
+	inherited map[int]string // PID → reason for descendant/session protection
+
+	// Blast-radius budgets for automatic terminations
+	budget    Budget
+	charged   []budgetRecord
+	exeCounts map[string]int
+	tripped   string
+
+	observedAt time.Time // when exeCounts was taken
 }
 
 // NewManager creates a new safety manager.
@@ -29,59 +51,206 @@ func NewManager(protectedNames, neverTermNames []string, consentLevel int) *Mana
 		protectedProcs: protected,
 		neverTerminate: never,
 		consentMgr:     NewConsentManager(consentLevel),
+		baseLevel:      consentLevel,
 	}
 }
 
-// IsProtected returns true if the process must not be terminated.
-func (m *Manager) IsProtected(proc *monitor.ProcessInfo) bool {
+// Verdict is the result of evaluating a process against the safety rules.
+type Verdict struct {
+	Allowed bool
+	Confirm bool     // a policy rule or the consent matrix requires confirmation
+	Notify  bool     // the consent matrix asks to tell the user when acting
+	Rule    string   // name of the policy rule that decided, if any
+	Tags    []string // tags of the matching policy rule
+	Hooks   []string // termination hooks of the matching policy rule
+	Scope   Scope    // termination scope of the matching policy rule, if set
+	Reason  string
+
+	Escalation Escalation // signal ladder of the matching policy rule, if set
+	UnitAction UnitAction // unit action of the matching policy rule, if set
+
+	ContainerAction ContainerAction // container action of the matching policy rule, if set
+	Limits          Limits          // limits of the matching policy rule, if set
+}
+
+// SetPolicy installs the declarative policy evaluated after the built-in
+// protections. A nil policy disables policy rules.
+func (m *Manager) SetPolicy(p *Policy) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if p != nil {
+		p.schedule = m.schedule
+	}
+	m.policy = p
+}
+
+// Policy returns the installed policy, or nil.
+func (m *Manager) Policy() *Policy {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
+	return m.policy
+}
 
-	// PID 1 and 2 are always protected
-	if proc.PID <= 2 {
-		return true
-	}
+// SetPins enables verification of protected names against pinned
+// executables and hashes.
+func (m *Manager) SetPins(pins *monitor.PinSet) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pins = pins
+}
 
-	// Kernel threads are always protected
-	if proc.Category == monitor.CategoryKernel {
-		return true
+// Pins returns the installed pin set, or nil.
+func (m *Manager) Pins() *monitor.PinSet {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.pins
+}
+
+// SetTreeProtection replaces the PIDs protected because of their place in
+// the process tree. self holds Aura and its ancestors, which can never be
+// terminated; inherited maps other PIDs to the reason they are protected.
+func (m *Manager) SetTreeProtection(self map[int]bool, inherited map[int]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.self = self
+	m.inherited = inherited
+}
+
+// SetConsentMatrix installs consent matrix cells that override the preset
+// of the current consent level.
+func (m *Manager) SetConsentMatrix(overrides Matrix) {
+	m.consentMgr.SetOverrides(overrides)
+}
+
+// Evaluate checks a termination of proc.
+func (m *Manager) Evaluate(proc *monitor.ProcessInfo) Verdict {
+	return m.EvaluateAction(proc, ActionTerminate)
+}
+
+// EvaluateAction checks action on proc against the built-in protections,
+// the policy rules and the protected process list, in that order, and then
+// applies the consent matrix and, for terminations, the data guard.
+func (m *Manager) EvaluateAction(proc *monitor.ProcessInfo, action Action) Verdict {
+	v := m.protection(proc)
+	if !v.Allowed {
+		return v
 	}
 
-	// Check never-terminate list
-	if m.neverTerminate[proc.Name] {
-		return true
+	switch m.consentMgr.Mode(proc, action, v.Tags) {
+	case ModeForbid:
+		return Verdict{Rule: v.Rule, Tags: v.Tags,
+			Reason: fmt.Sprintf("%s of %s processes is forbidden by consent settings", action, strings.ToLower(proc.Category.String()))}
+	case ModeConfirm:
+		v.Confirm = true
+	case ModeNotify:
+		v.Notify = true
 	}
 
-	// Check protected processes list
-	if m.protectedProcs[proc.Name] {
-		return true
+	// Killing a process mid-write is refused; holding anything that could
+	// be lost needs confirmation
+	if action == ActionTerminate {
+		m.mu.RLock()
+		guard := m.guard
+		m.mu.RUnlock()
+		if guard != nil {
+			risk := guard.Inspect(proc)
+			switch {
+			case guard.Likely(risk):
+				return Verdict{Rule: v.Rule, Tags: v.Tags, Reason: "data loss likely: " + risk.String()}
+			case risk.Possible():
+				v.Confirm = true
+				v.Reason = "possible data loss: " + risk.String()
+			}
+		}
 	}
+	return v
+}
 
-	return false
+// Consent returns the consent mode for action on proc.
+func (m *Manager) Consent(proc *monitor.ProcessInfo, action Action) Mode {
+	var tags []string
+	if rule := m.Policy().Evaluate(proc); rule != nil {
+		tags = rule.Tags
+	}
+	return m.consentMgr.Mode(proc, action, tags)
 }
 
-// ValidateTermination checks if a process can be safely terminated.
-func (m *Manager) ValidateTermination(proc *monitor.ProcessInfo) (bool, string) {
+// protection applies the protections that do not depend on the action.
+func (m *Manager) protection(proc *monitor.ProcessInfo) Verdict {
+	// PID 1 and 2 are always protected
 	if proc.PID <= 2 {
-		return false, fmt.Sprintf("PID %d is a critical system process", proc.PID)
+		return Verdict{Reason: fmt.Sprintf("PID %d is a critical system process", proc.PID)}
 	}
 
+	// Kernel threads are always protected
 	if proc.Category == monitor.CategoryKernel {
-		return false, fmt.Sprintf("process '%s' is a kernel thread", proc.Name)
+		return Verdict{Reason: fmt.Sprintf("process '%s' is a kernel thread", proc.Name)}
 	}
 
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	if m.neverTerminate[proc.Name] {
-		return false, fmt.Sprintf("process '%s' is on the never-terminate list", proc.Name)
+	// Names on the protection lists only count if they are not spoofed;
+	// a verified pin protects the binary under any name
+	pin, pinInfo := m.pins.Verify(proc)
+	spoofed := pin == monitor.PinMismatch
+	pinned := ""
+	if pin == monitor.PinVerified {
+		pinned = pinInfo
+	}
+
+	// Aura never terminates itself or the processes it runs under
+	if m.self[proc.PID] {
+		return Verdict{Reason: fmt.Sprintf("process '%s' is Aura or one of its ancestors", proc.Name)}
 	}
 
-	if m.protectedProcs[proc.Name] {
-		return false, fmt.Sprintf("process '%s' is protected", proc.Name)
+	// The never-terminate list cannot be overridden by policy
+	if (m.neverTerminate[proc.Name] && !spoofed) || m.neverTerminate[pinned] {
+		return Verdict{Reason: fmt.Sprintf("process '%s' is on the never-terminate list", proc.Name)}
 	}
 
-	return true, "termination allowed"
+	// Tree protection comes before policy rules so that no allow rule can
+	// reach into a protected subtree or Aura's own session
+	if reason, ok := m.inherited[proc.PID]; ok {
+		return Verdict{Reason: reason}
+	}
+
+	if rule := m.policy.Evaluate(proc); rule != nil {
+		switch rule.Effect {
+		case EffectDeny:
+			return Verdict{Rule: rule.Name, Tags: rule.Tags, Reason: fmt.Sprintf("denied by policy rule '%s'", rule.Name)}
+		case EffectConfirm:
+			return Verdict{Allowed: true, Confirm: true, Rule: rule.Name, Tags: rule.Tags, Hooks: rule.Hooks, Scope: rule.Scope, Escalation: rule.escalation, UnitAction: rule.UnitAction, ContainerAction: rule.ContainerAction, Limits: rule.Limits,
+				Reason: fmt.Sprintf("confirmation required by policy rule '%s'", rule.Name)}
+		default:
+			return Verdict{Allowed: true, Rule: rule.Name, Tags: rule.Tags, Hooks: rule.Hooks, Scope: rule.Scope, Escalation: rule.escalation, UnitAction: rule.UnitAction, ContainerAction: rule.ContainerAction, Limits: rule.Limits,
+				Reason: fmt.Sprintf("allowed by policy rule '%s'", rule.Name)}
+		}
+	}
+
+	if m.protectedProcs[proc.Name] && !spoofed {
+		return Verdict{Reason: fmt.Sprintf("process '%s' is protected", proc.Name)}
+	}
+	if pinned != "" {
+		return Verdict{Reason: fmt.Sprintf("process '%s' runs the pinned executable of '%s'", proc.Name, pinned)}
+	}
+
+	if spoofed {
+		return Verdict{Allowed: true, Reason: "termination allowed; suspicious: " + pinInfo}
+	}
+	return Verdict{Allowed: true, Reason: "termination allowed"}
+}
+
+// IsProtected returns true if the process is shielded by the safety rules,
+// regardless of what the consent matrix currently permits.
+func (m *Manager) IsProtected(proc *monitor.ProcessInfo) bool {
+	return !m.protection(proc).Allowed
+}
+
+// ValidateTermination checks if a process can be safely terminated.
+func (m *Manager) ValidateTermination(proc *monitor.ProcessInfo) (bool, string) {
+	v := m.Evaluate(proc)
+	return v.Allowed, v.Reason
 }
 
 // ConsentLevel returns the current consent level.
@@ -89,14 +258,22 @@ func (m *Manager) ConsentLevel() int {
 	return m.consentMgr.Level()
 }
 
-// SetConsentLevel updates the consent level.
+// SetConsentLevel updates the consent level. Schedule windows that set a
+// level still take precedence while they are active.
 func (m *Manager) SetConsentLevel(level int) {
+	m.mu.Lock()
+	m.baseLevel = level
+	m.mu.Unlock()
 	m.consentMgr.SetLevel(level)
 }
 
-// NeedsConfirmation checks if the consent level requires user confirmation.
+// NeedsConfirmation checks if the consent matrix or a policy rule requires
+// user confirmation to terminate proc.
 func (m *Manager) NeedsConfirmation(proc *monitor.ProcessInfo) bool {
-	return m.consentMgr.NeedsConfirmation(proc)
+	if m.consentMgr.NeedsConfirmation(proc) {
+		return true
+	}
+	return m.Evaluate(proc).Confirm
 }
 
 // IsMonitorOnly returns true if consent level is 3 (monitor only).
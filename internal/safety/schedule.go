@@ -0,0 +1,311 @@
+package safety
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cron is a five-field cron expression (minute, hour, day of month, month,
+// day of week) evaluated in a time zone. A schedule window is active during
+// every minute the expression matches, so "* 0-6 * * *" covers 00:00 to
+// 06:59 each night.
+type Cron struct {
+	expr string
+	loc  *time.Location
+
+	minute, hour, dom, month, dow uint64 // bit sets of allowed values
+	domAny, dowAny                bool
+}
+
+var (
+	monthNames = []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+	dayNames   = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+)
+
+// ParseCron parses expr in the named time zone; an empty zone is the local
+// time zone. Fields accept *, values, ranges (a-b), lists (a,b) and steps
+// (*/n, a-b/n, and a/n for a through the maximum); months and weekdays also
+// accept three-letter names, and weekday 7 is Sunday.
+func ParseCron(expr, zone string) (*Cron, error) {
+	loc := time.Local
+	if zone != "" {
+		l, err := time.LoadLocation(zone)
+		if err != nil {
+			return nil, fmt.Errorf("cron %q: %w", expr, err)
+		}
+		loc = l
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron %q: want 5 fields (minute hour day month weekday), got %d", expr, len(fields))
+	}
+	c := &Cron{expr: expr, loc: loc, domAny: fields[2] == "*", dowAny: fields[4] == "*"}
+	specs := []struct {
+		set      *uint64
+		min, max int
+		names    []string
+		nameBase int
+	}{
+		{&c.minute, 0, 59, nil, 0},
+		{&c.hour, 0, 23, nil, 0},
+		{&c.dom, 1, 31, nil, 0},
+		{&c.month, 1, 12, monthNames, 1},
+		{&c.dow, 0, 7, dayNames, 0},
+	}
+	for i, s := range specs {
+		set, err := parseCronField(fields[i], s.min, s.max, s.names, s.nameBase)
+		if err != nil {
+			return nil, fmt.Errorf("cron %q: field %d: %w", expr, i+1, err)
+		}
+		*s.set = set
+	}
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1
+	}
+	return c, nil
+}
+
+func parseCronField(field string, min, max int, names []string, nameBase int) (uint64, error) {
+	value := func(s string) (int, error) {
+		for i, n := range names {
+			if strings.EqualFold(s, n) {
+				return i + nameBase, nil
+			}
+		}
+		v, err := strconv.Atoi(s)
+		if err != nil || v < min || v > max {
+			return 0, fmt.Errorf("%q is not in %d-%d", s, min, max)
+		}
+		return v, nil
+	}
+
+	var set uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, step := part, 1
+		if i := strings.IndexByte(part, '/'); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			rng, step = part[:i], n
+		}
+
+		lo, hi := min, max
+		switch {
+		case rng == "*":
+		case strings.Contains(rng, "-"):
+			a, b, _ := strings.Cut(rng, "-")
+			var err error
+			if lo, err = value(a); err != nil {
+				return 0, err
+			}
+			if hi, err = value(b); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("empty range %q", rng)
+			}
+		default:
+			v, err := value(rng)
+			if err != nil {
+				return 0, err
+			}
+			lo, hi = v, v
+			if rng != part { // a/n runs from a to the maximum
+				hi = max
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			set |= 1 << v
+		}
+	}
+	return set, nil
+}
+
+// Matches reports whether t falls in a minute the expression matches. As in
+// cron, when both the day of month and the day of week are restricted,
+// either may match.
+func (c *Cron) Matches(t time.Time) bool {
+	t = t.In(c.loc)
+	if c.minute&(1<<t.Minute()) == 0 || c.hour&(1<<t.Hour()) == 0 || c.month&(1<<int(t.Month())) == 0 {
+		return false
+	}
+	domOK := c.dom&(1<<t.Day()) != 0
+	dowOK := c.dow&(1<<int(t.Weekday())) != 0
+	if c.domAny || c.dowAny {
+		return domOK && dowOK
+	}
+	return domOK || dowOK
+}
+
+// String returns the expression and its time zone.
+func (c *Cron) String() string {
+	return fmt.Sprintf("%s (%s)", c.expr, c.loc)
+}
+
+// Window is a named schedule that changes the consent level and the AI
+// aggressiveness while its cron expression matches. Policy rules can also
+// be limited to a window by name.
+type Window struct {
+	Name           string
+	Cron           *Cron
+	ConsentLevel   *int // nil leaves the consent level unchanged
+	Aggressiveness int  // 0 leaves the aggressiveness unchanged
+}
+
+// Schedule is the set of configured windows.
+type Schedule struct {
+	Windows []Window
+}
+
+// NewSchedule validates windows.
+func NewSchedule(windows []Window) (*Schedule, error) {
+	seen := make(map[string]bool, len(windows))
+	for _, w := range windows {
+		if w.Name == "" {
+			return nil, fmt.Errorf("schedule name is required")
+		}
+		if seen[w.Name] {
+			return nil, fmt.Errorf("schedule %q is defined twice", w.Name)
+		}
+		seen[w.Name] = true
+		if w.Cron == nil {
+			return nil, fmt.Errorf("schedule %q: cron is required", w.Name)
+		}
+		if w.ConsentLevel != nil && (*w.ConsentLevel < ConsentAutomatic || *w.ConsentLevel > ConsentMonitorOnly) {
+			return nil, fmt.Errorf("schedule %q: consent_level must be 0-3", w.Name)
+		}
+		if w.Aggressiveness < 0 || w.Aggressiveness > 10 {
+			return nil, fmt.Errorf("schedule %q: aggressiveness must be 1-10", w.Name)
+		}
+	}
+	return &Schedule{Windows: windows}, nil
+}
+
+// Has reports whether a window called name is defined.
+func (s *Schedule) Has(name string) bool {
+	if s == nil {
+		return false
+	}
+	for _, w := range s.Windows {
+		if w.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Active reports whether the window called name is active at t.
+func (s *Schedule) Active(name string, t time.Time) bool {
+	if s == nil {
+		return false
+	}
+	for _, w := range s.Windows {
+		if w.Name == name {
+			return w.Cron.Matches(t)
+		}
+	}
+	return false
+}
+
+// Settings are the consent level and aggressiveness in effect.
+type Settings struct {
+	ConsentLevel   int
+	Aggressiveness int
+	Windows        []string // names of the active windows
+}
+
+// String summarizes the settings for messages and the audit trail.
+func (s Settings) String() string {
+	windows := "no schedule windows active"
+	if len(s.Windows) > 0 {
+		windows = "schedule " + strings.Join(s.Windows, ", ") + " active"
+	}
+	return fmt.Sprintf("%s: consent level %d (%s), aggressiveness %d/10",
+		windows, s.ConsentLevel, LevelDescription(s.ConsentLevel), s.Aggressiveness)
+}
+
+// Resolve applies the windows active at t to base. When several windows
+// overlap, the most conservative values win: the highest consent level and
+// the lowest aggressiveness.
+func (s *Schedule) Resolve(t time.Time, base Settings) Settings {
+	if s == nil {
+		return base
+	}
+	out := Settings{ConsentLevel: -1}
+	for _, w := range s.Windows {
+		if !w.Cron.Matches(t) {
+			continue
+		}
+		out.Windows = append(out.Windows, w.Name)
+		if w.ConsentLevel != nil && *w.ConsentLevel > out.ConsentLevel {
+			out.ConsentLevel = *w.ConsentLevel
+		}
+		if w.Aggressiveness > 0 && (out.Aggressiveness == 0 || w.Aggressiveness < out.Aggressiveness) {
+			out.Aggressiveness = w.Aggressiveness
+		}
+	}
+	if out.ConsentLevel < 0 {
+		out.ConsentLevel = base.ConsentLevel
+	}
+	if out.Aggressiveness == 0 {
+		out.Aggressiveness = base.Aggressiveness
+	}
+	return out
+}
+
+// SetSchedule installs the schedule windows. The policy rules and the
+// consent level follow it from then on; see ApplySchedule.
+func (m *Manager) SetSchedule(s *Schedule) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.schedule = s
+	if m.policy != nil {
+		m.policy.schedule = s
+	}
+}
+
+// Schedule returns the installed schedule, or nil.
+func (m *Manager) Schedule() *Schedule {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.schedule
+}
+
+// ApplySchedule sets the consent level for the windows active at now and
+// returns the settings in effect, including the aggressiveness to use
+// instead of aggressiveness. changed is set when the active windows differ
+// from the previous call. Once a budget has tripped, the level stays
+// monitor-only whatever the windows say.
+func (m *Manager) ApplySchedule(now time.Time, aggressiveness int) (s Settings, changed bool) {
+	m.mu.Lock()
+	s = m.schedule.Resolve(now, Settings{ConsentLevel: m.baseLevel, Aggressiveness: aggressiveness})
+	if m.tripped != "" {
+		s.ConsentLevel = ConsentMonitorOnly
+	}
+	changed = strings.Join(s.Windows, ",") != strings.Join(m.applied.Windows, ",")
+	m.applied = s
+	m.mu.Unlock()
+
+	if m.consentMgr.Level() != s.ConsentLevel {
+		m.consentMgr.SetLevel(s.ConsentLevel)
+	}
+	return s, changed
+}
+
+// ActiveWindows returns the windows active at the last ApplySchedule.
+func (m *Manager) ActiveWindows() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.applied.Windows
+}
+
+// Settings returns the settings resolved by the last ApplySchedule.
+func (m *Manager) Settings() Settings {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.applied
+}
@@ -0,0 +1,39 @@
+package safety
+
+import "fmt"
+
+// Scope is the set of processes a termination takes down along with its
+// target.
+type Scope string
+
+const (
+	ScopeProcess Scope = "process" // the target alone
+	ScopeTree    Scope = "tree"    // the target and its descendants, children first
+	ScopeGroup   Scope = "group"   // the target's process group
+	ScopeSession Scope = "session" // every process in the target's session
+	ScopeCgroup  Scope = "cgroup"  // the target's cgroup, through cgroup.kill
+
+	// ScopeUnit is the scope of a termination carried out through systemctl
+	// (see UnitAction); it cannot be configured
+	ScopeUnit Scope = "unit"
+
+	// ScopeContainer is the scope of a termination carried out through the
+	// container runtime (see ContainerAction); it cannot be configured
+	ScopeContainer Scope = "container"
+)
+
+// Scopes lists every termination scope.
+var Scopes = []Scope{ScopeProcess, ScopeTree, ScopeGroup, ScopeSession, ScopeCgroup}
+
+// ParseScope validates a scope name. An empty name is ScopeProcess.
+func ParseScope(s string) (Scope, error) {
+	if s == "" {
+		return ScopeProcess, nil
+	}
+	for _, scope := range Scopes {
+		if Scope(s) == scope {
+			return scope, nil
+		}
+	}
+	return "", fmt.Errorf("unknown termination scope %q (want process, tree, group, session or cgroup)", s)
+}
@@ -0,0 +1,217 @@
+package safety
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/iamgilwell/aura/internal/monitor"
+)
+
+// Snooze is a decision to leave processes alone, either until a deadline or,
+// when Until is zero, for good. It targets either a process identity (the
+// executable and command line, so restarts stay covered) or a glob on the
+// process name or executable path.
+type Snooze struct {
+	ID        string    `json:"id"`
+	Pattern   string    `json:"pattern,omitempty"`
+	Name      string    `json:"name,omitempty"`
+	Exe       string    `json:"exe,omitempty"`
+	Cmdline   string    `json:"cmdline,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+	Source    string    `json:"source,omitempty"` // cli, interactive
+	CreatedAt time.Time `json:"created_at"`
+	Until     time.Time `json:"until"` // zero keeps the processes for good
+}
+
+// NewSnooze snoozes the identity of proc for d; zero keeps it for good.
+func NewSnooze(proc *monitor.ProcessInfo, d time.Duration) Snooze {
+	return Snooze{Name: proc.Name, Exe: proc.Exe, Cmdline: proc.Cmdline, Until: snoozeUntil(d)}
+}
+
+// NewPatternSnooze snoozes processes whose name or executable path matches
+// the glob pattern for d; zero keeps them for good.
+func NewPatternSnooze(pattern string, d time.Duration) (Snooze, error) {
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return Snooze{}, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	return Snooze{Pattern: pattern, Until: snoozeUntil(d)}, nil
+}
+
+func snoozeUntil(d time.Duration) time.Time {
+	if d <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(d)
+}
+
+// Permanent reports whether the snooze never expires.
+func (s Snooze) Permanent() bool {
+	return s.Until.IsZero()
+}
+
+// Active reports whether the snooze is in effect at t.
+func (s Snooze) Active(t time.Time) bool {
+	return s.Permanent() || t.Before(s.Until)
+}
+
+// Target describes what the snooze applies to.
+func (s Snooze) Target() string {
+	if s.Pattern != "" {
+		return s.Pattern
+	}
+	return s.Name
+}
+
+// Expiry describes how long the snooze lasts, e.g. "kept for good" or
+// "snoozed until 18:30".
+func (s Snooze) Expiry() string {
+	switch {
+	case s.Permanent():
+		return "kept for good"
+	case time.Until(s.Until) < 24*time.Hour:
+		return "snoozed until " + s.Until.Format("15:04")
+	default:
+		return "snoozed until " + s.Until.Format("2006-01-02 15:04")
+	}
+}
+
+// Matches reports whether the snooze covers proc.
+func (s Snooze) Matches(proc *monitor.ProcessInfo) bool {
+	if s.Pattern == "" {
+		id := monitor.ProcessInfo{Name: s.Name, Exe: s.Exe, Cmdline: s.Cmdline}
+		return id.Identity() == proc.Identity()
+	}
+	if ok, _ := filepath.Match(s.Pattern, proc.Name); ok {
+		return true
+	}
+	ok, _ := filepath.Match(s.Pattern, proc.Exe)
+	return ok && proc.Exe != ""
+}
+
+// sameTarget reports whether both snoozes cover the same processes.
+func (s Snooze) sameTarget(o Snooze) bool {
+	if s.Pattern != "" || o.Pattern != "" {
+		return s.Pattern == o.Pattern
+	}
+	return s.Name == o.Name && s.Exe == o.Exe && s.Cmdline == o.Cmdline
+}
+
+// Snoozes is a list of active snoozes.
+type Snoozes []Snooze
+
+// Find returns the first snooze covering proc.
+func (ss Snoozes) Find(proc *monitor.ProcessInfo) (Snooze, bool) {
+	for _, s := range ss {
+		if s.Matches(proc) {
+			return s, true
+		}
+	}
+	return Snooze{}, false
+}
+
+// Filter returns the processes of procs no snooze covers.
+func (ss Snoozes) Filter(procs []*monitor.ProcessInfo) []*monitor.ProcessInfo {
+	var left []*monitor.ProcessInfo
+	for _, p := range procs {
+		if _, ok := ss.Find(p); !ok {
+			left = append(left, p)
+		}
+	}
+	return left
+}
+
+// SnoozeStore persists snoozes in a JSON file guarded by a lock file, so
+// that yolo, the TUI and "aura snooze" share them across restarts.
+type SnoozeStore struct {
+	mu    sync.Mutex
+	path  string
+	items []Snooze // used when path is empty
+}
+
+// NewSnoozeStore creates a store at path. An empty path keeps the snoozes
+// in memory.
+func NewSnoozeStore(path string) *SnoozeStore {
+	return &SnoozeStore{path: path}
+}
+
+// Add stores s, replacing any snooze of the same target, and returns it
+// with its ID set.
+func (st *SnoozeStore) Add(s Snooze) (Snooze, error) {
+	s.ID = newID()
+	s.CreatedAt = time.Now()
+	err := st.update(func(items []Snooze) []Snooze {
+		kept := items[:0]
+		for _, it := range items {
+			if !it.sameTarget(s) {
+				kept = append(kept, it)
+			}
+		}
+		return append(kept, s)
+	})
+	return s, err
+}
+
+// List returns the active snoozes, oldest first, dropping expired ones.
+func (st *SnoozeStore) List() (Snoozes, error) {
+	var active Snoozes
+	err := st.update(func(items []Snooze) []Snooze {
+		now := time.Now()
+		for _, it := range items {
+			if it.Active(now) {
+				active = append(active, it)
+			}
+		}
+		return active
+	})
+	return active, err
+}
+
+// Remove deletes the snoozes whose ID or pattern is key and returns them.
+func (st *SnoozeStore) Remove(key string) (Snoozes, error) {
+	var removed Snoozes
+	err := st.update(func(items []Snooze) []Snooze {
+		kept := items[:0]
+		for _, it := range items {
+			if it.ID == key || (it.Pattern != "" && it.Pattern == key) {
+				removed = append(removed, it)
+				continue
+			}
+			kept = append(kept, it)
+		}
+		return kept
+	})
+	if err == nil && len(removed) == 0 {
+		err = fmt.Errorf("no snooze with ID or pattern %q", key)
+	}
+	return removed, err
+}
+
+// RemoveProcess deletes the snoozes covering proc and returns them.
+func (st *SnoozeStore) RemoveProcess(proc *monitor.ProcessInfo) (Snoozes, error) {
+	var removed Snoozes
+	err := st.update(func(items []Snooze) []Snooze {
+		kept := items[:0]
+		for _, it := range items {
+			if it.Matches(proc) {
+				removed = append(removed, it)
+				continue
+			}
+			kept = append(kept, it)
+		}
+		return kept
+	})
+	return removed, err
+}
+
+func (st *SnoozeStore) update(fn func([]Snooze) []Snooze) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if st.path == "" {
+		st.items = fn(st.items)
+		return nil
+	}
+	return updateFile(st.path, "snooze file", fn)
+}
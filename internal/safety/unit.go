@@ -0,0 +1,31 @@
+package safety
+
+import "fmt"
+
+// UnitAction is what a termination does to a process owned by a systemd
+// unit.
+type UnitAction string
+
+const (
+	UnitAuto    UnitAction = "auto"    // stop the unit if systemd would restart the signalled process
+	UnitSignal  UnitAction = "signal"  // signal the process, ignoring its unit
+	UnitStop    UnitAction = "stop"    // systemctl stop
+	UnitRestart UnitAction = "restart" // systemctl restart
+	UnitKill    UnitAction = "kill"    // systemctl kill, climbing the escalation ladder
+)
+
+// UnitActions lists every unit action.
+var UnitActions = []UnitAction{UnitAuto, UnitSignal, UnitStop, UnitRestart, UnitKill}
+
+// ParseUnitAction validates a unit action name. An empty name is UnitAuto.
+func ParseUnitAction(s string) (UnitAction, error) {
+	if s == "" {
+		return UnitAuto, nil
+	}
+	for _, a := range UnitActions {
+		if UnitAction(s) == a {
+			return a, nil
+		}
+	}
+	return "", fmt.Errorf("unknown unit action %q (want auto, signal, stop, restart or kill)", s)
+}
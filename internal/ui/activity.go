@@ -0,0 +1,91 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/iamgilwell/aura/internal/process"
+	"github.com/iamgilwell/aura/internal/safety"
+)
+
+const decisionTitle = " AI Decisions "
+
+var spinnerFrames = []rune("⠋⠙⠹⠸⠼⠴⠦⠧⠇⠏")
+
+// watchTerminations shows a spinner with the terminations in flight in the
+// decision panel title until none is left. Only one watcher runs at a time.
+func watchTerminations(app *App) {
+	app.mu.Lock()
+	if app.watching {
+		app.mu.Unlock()
+		return
+	}
+	app.watching = true
+	app.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		for frame := 0; ; frame++ {
+			// Checked under app.mu so that a termination started meanwhile
+			// either is seen here or starts a new watcher
+			app.mu.Lock()
+			ts := app.procMgr.Terminations()
+			app.watching = len(ts) > 0
+			app.mu.Unlock()
+
+			title := terminationsTitle(ts, spinnerFrames[frame%len(spinnerFrames)])
+			app.tapp.QueueUpdateDraw(func() {
+				app.decisionPanel.view.SetTitle(decisionTitle + title)
+			})
+			if len(ts) == 0 {
+				return
+			}
+			select {
+			case <-app.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// terminationsTitle describes the terminations in flight, e.g. "⠋ node
+// (PID 4242) SIGTERM 1.2s +1 more — x to cancel", or returns "" if there
+// are none.
+func terminationsTitle(ts []*process.Termination, spinner rune) string {
+	if len(ts) == 0 {
+		return ""
+	}
+	t := ts[0]
+	p := t.Progress()
+	step := "starting"
+	if p.Stage == process.StageSignalled {
+		step = safety.SignalName(p.Signal)
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "[yellow]%c Terminating %s (PID %d) %s %.1fs", spinner, t.Proc.Name, t.Proc.PID, step, p.Elapsed.Seconds())
+	if len(ts) > 1 {
+		fmt.Fprintf(&sb, " +%d more", len(ts)-1)
+	}
+	sb.WriteString(" — x to cancel[-] ")
+	return sb.String()
+}
+
+// cancelTerminations cancels the termination of pid if one is in flight,
+// and otherwise every termination in flight.
+func cancelTerminations(app *App, pid int) {
+	ts := app.procMgr.Terminations()
+	if t := app.procMgr.Terminating(pid); t != nil {
+		ts = []*process.Termination{t}
+	}
+	for _, t := range ts {
+		t.Cancel()
+	}
+	if len(ts) > 0 {
+		app.tapp.QueueUpdateDraw(func() {
+			fmt.Fprintf(app.decisionPanel.view, "[yellow]Cancelled %d termination(s); signals already sent are not undone\n", len(ts))
+		})
+	}
+}
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
@@ -23,13 +24,21 @@ type App struct {
 	cfg          *config.Config
 	mon          *monitor.ProcessMonitor
 	aiEngine     *ai.Engine
+	decider      ai.Decider
 	safetyMgr    *safety.Manager
 	procMgr      *process.Manager
+	tracker      *process.OutcomeTracker
+	queue        *safety.PendingQueue
+	snoozes      *safety.SnoozeStore
 	powerCalc    *power.Calculator
 	powerMetrics *power.Metrics
 	notifier     *notification.Notifier
 	auditor      *notification.Auditor
 
+	// Re-applies the schedule windows, auditing changes
+	applySchedule func() (safety.Settings, bool)
+
+	pages         *tview.Pages
 	dashboard     *Dashboard
 	processTable  *ProcessTable
 	decisionPanel *DecisionPanel
@@ -39,40 +48,54 @@ type App struct {
 	sysMetrics   *monitor.SystemMetrics
 	startTime    time.Time
 	showAISugg   bool
+	watching     bool // a goroutine follows the terminations in flight
 
 	ctx    context.Context
 	cancel context.CancelFunc
 }
 
-// NewApp creates the TUI application.
+// NewApp creates the TUI application. applySchedule re-applies the schedule
+// windows active now and reports whether they changed; it is called on every
+// scan and whenever the aggressiveness changes.
 func NewApp(
 	cfg *config.Config,
 	mon *monitor.ProcessMonitor,
 	aiEngine *ai.Engine,
+	decider ai.Decider,
 	safetyMgr *safety.Manager,
 	procMgr *process.Manager,
+	tracker *process.OutcomeTracker,
+	queue *safety.PendingQueue,
+	snoozes *safety.SnoozeStore,
 	powerCalc *power.Calculator,
 	powerMetrics *power.Metrics,
 	notifier *notification.Notifier,
 	auditor *notification.Auditor,
+	applySchedule func() (safety.Settings, bool),
 ) *App {
 	app := &App{
 		tapp:         tview.NewApplication(),
 		cfg:          cfg,
 		mon:          mon,
 		aiEngine:     aiEngine,
+		decider:      decider,
 		safetyMgr:    safetyMgr,
 		procMgr:      procMgr,
+		tracker:      tracker,
+		queue:        queue,
+		snoozes:      snoozes,
 		powerCalc:    powerCalc,
 		powerMetrics: powerMetrics,
 		notifier:     notifier,
 		auditor:      auditor,
+		applySchedule: applySchedule,
 		startTime:    time.Now(),
 		showAISugg:   true,
 	}
 
 	app.ctx, app.cancel = context.WithCancel(context.Background())
 
+	app.pages = tview.NewPages()
 	app.dashboard = NewDashboard(app)
 	app.processTable = NewProcessTable(app)
 	app.decisionPanel = NewDecisionPanel(app)
@@ -89,7 +112,8 @@ func (a *App) Run() error {
 		AddItem(a.decisionPanel.view, 10, 0, false).
 		AddItem(a.createFooter(), 1, 0, false)
 
-	a.tapp.SetRoot(mainFlex, true)
+	a.pages.AddPage(pageMain, mainFlex, true, true)
+	a.tapp.SetRoot(a.pages, true)
 	setupKeybindings(a)
 
 	// Start monitor in background
@@ -98,10 +122,36 @@ func (a *App) Run() error {
 		a.processes = procs
 		a.sysMetrics = metrics
 		a.mu.Unlock()
+		schedule, scheduleChanged := a.applySchedule()
+		a.procMgr.UpdateProtection(procs)
+		notification.ExpirePending(a.queue, a.auditor)
+
+		outcomes := a.tracker.Observe(procs)
+		for _, ev := range outcomes {
+			a.auditor.LogOutcome(ev.TerminationID, ev.Name, string(ev.Outcome), ev.NewPID, ev.After)
+		}
+		var suspicious []string
+		for _, p := range procs {
+			if p.Suspicious != "" && a.auditor.LogSuspicious(p) {
+				suspicious = append(suspicious, p.Suspicious)
+			}
+		}
 
 		a.tapp.QueueUpdateDraw(func() {
 			a.dashboard.Update(metrics)
 			a.processTable.Update(procs)
+			if scheduleChanged {
+				fmt.Fprintf(a.decisionPanel.view, "[yellow]Now %s\n", schedule)
+			}
+			for _, ev := range outcomes {
+				if ev.Outcome != process.OutcomeStayedDead {
+					fmt.Fprintf(a.decisionPanel.view, "[yellow]%s was %s after %s (new PID %d)\n",
+						ev.Name, ev.Outcome, ev.After.Truncate(time.Second), ev.NewPID)
+				}
+			}
+			for _, msg := range suspicious {
+				fmt.Fprintf(a.decisionPanel.view, "[red]Suspicious process: %s\n", msg)
+			}
 		})
 	})
 
@@ -113,11 +163,17 @@ func (a *App) Run() error {
 func (a *App) createFooter() *tview.TextView {
 	footer := tview.NewTextView().
 		SetDynamicColors(true).
-		SetText(" [yellow]F1[white]:AI History [yellow]F2[white]:Suggestions [yellow]F3[white]:Power [yellow]F5[white]:Refresh [yellow]F6[white]:Sort [yellow]F7[white]:Aggr- [yellow]F8[white]:Aggr+ [yellow]F9[white]:Kill [yellow]F10[white]:Quit")
+		SetText(" [yellow]F1[white]:AI History [yellow]F2[white]:Suggestions [yellow]F3[white]:Power [yellow]F5[white]:Refresh [yellow]F6[white]:Sort [yellow]F7[white]:Aggr- [yellow]F8[white]:Aggr+ [yellow]F9[white]:Kill [yellow]s[white]:Suspend [yellow]l[white]:Limit [yellow]u[white]:Undo [yellow]z[white]:Snooze [yellow]x[white]:Cancel kill [yellow]k[white]:Keep [yellow]p[white]:Pending [yellow]:[white]Ask [yellow]F10[white]:Quit")
 	footer.SetBackgroundColor(tcell.ColorDarkSlateGray)
 	return footer
 }
 
+// overlayActive reports whether a prompt or dialog is on top of the main page.
+func (a *App) overlayActive() bool {
+	name, _ := a.pages.GetFrontPage()
+	return name != pageMain
+}
+
 func (a *App) stop() {
 	a.cancel()
 	a.tapp.Stop()
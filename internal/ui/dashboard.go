@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/rivo/tview"
@@ -40,6 +41,9 @@ func (d *Dashboard) Update(metrics *monitor.SystemMetrics) {
 	if d.app.safetyMgr.IsMonitorOnly() {
 		mode = "Monitor Only"
 	}
+	if d.app.procMgr.DryRun() {
+		mode += " [::b]DRY RUN[::-]"
+	}
 
 	consentLevel := d.app.safetyMgr.ConsentLevel()
 	consentDesc := safety.LevelDescription(consentLevel)
@@ -49,15 +53,23 @@ func (d *Dashboard) Update(metrics *monitor.SystemMetrics) {
 		aiStatus = "[green]ON"
 	}
 
-	powerSaved := d.app.powerMetrics.TotalSaved()
+	schedule := ""
+	if windows := d.app.safetyMgr.ActiveWindows(); len(windows) > 0 {
+		schedule = fmt.Sprintf("[yellow]Schedule:[white] %s | ", strings.Join(windows, ", "))
+	}
+
+	powerLabel, powerSaved := "Power Saved", d.app.powerMetrics.TotalSaved()
+	if d.app.powerMetrics.DryRun() {
+		powerLabel, powerSaved = "Would Save", d.app.powerMetrics.WouldSave()
+	}
 
 	text := fmt.Sprintf(
-		" [yellow]Runtime:[white] %s | [yellow]Mode:[white] %s | [yellow]Safety:[white] %s (L%d) | [yellow]AI:[white] %s[white] | "+
+		" [yellow]Runtime:[white] %s | [yellow]Mode:[white] %s | [yellow]Safety:[white] %s (L%d) | %s[yellow]AI:[white] %s[white] | "+
 			"[yellow]Procs:[white] %d | [yellow]CPU:[white] %.1f%% | [yellow]Mem:[white] %.1f%% | [yellow]Load:[white] %.2f | "+
-			"[yellow]Power Saved:[white] %.2fW",
-		runtime, mode, consentDesc, consentLevel, aiStatus,
+			"[yellow]%s:[white] %.2fW",
+		runtime, mode, consentDesc, consentLevel, schedule, aiStatus,
 		metrics.NumProcs, metrics.TotalCPU, metrics.TotalMemory, metrics.LoadAvg1,
-		powerSaved,
+		powerLabel, powerSaved,
 	)
 
 	d.view.SetText(text)
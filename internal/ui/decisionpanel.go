@@ -25,7 +25,7 @@ func NewDecisionPanel(app *App) *DecisionPanel {
 		})
 
 	tv.SetBorder(true).
-		SetTitle(" AI Decisions ").
+		SetTitle(decisionTitle).
 		SetBorderPadding(0, 0, 1, 1)
 
 	return &DecisionPanel{app: app, view: tv}
@@ -97,14 +97,21 @@ func (dp *DecisionPanel) ShowPowerMetrics() {
 		pm.MonthlyProjection(),
 		pm.Count(),
 	)
+	if pm.DryRun() {
+		text += fmt.Sprintf("Dry run, would save: [yellow]%.2f W[white]\n\n", pm.WouldSave())
+	}
 
 	recent := pm.RecentSavings(5)
 	if len(recent) > 0 {
 		text += "[yellow]Recent Savings:[white]\n"
 		for _, e := range recent {
-			text += fmt.Sprintf("  %s  %-20s PID=%-7d [green]+%.2fW[white]  %s\n",
+			color := "green"
+			if e.DryRun {
+				color = "yellow"
+			}
+			text += fmt.Sprintf("  %s  %-20s PID=%-7d [%s]+%.2fW[white]  %s\n",
 				notification.FormatTimestamp(e.Timestamp),
-				e.ProcessName, e.PID, e.WattsSaved, e.Reason)
+				e.ProcessName, e.PID, color, e.WattsSaved, e.Reason)
 		}
 	}
 
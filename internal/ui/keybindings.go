@@ -3,14 +3,24 @@ package ui
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/gdamore/tcell/v2"
 
+	"github.com/iamgilwell/aura/internal/ai"
 	"github.com/iamgilwell/aura/internal/monitor"
+	"github.com/iamgilwell/aura/internal/notification"
+	"github.com/iamgilwell/aura/internal/process"
+	"github.com/iamgilwell/aura/internal/safety"
 )
 
 func setupKeybindings(app *App) {
 	app.tapp.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		// Prompts and dialogs handle their own keys
+		if app.overlayActive() {
+			return event
+		}
+
 		switch event.Key() {
 		case tcell.KeyF1:
 			// Show AI decision history
@@ -71,9 +81,9 @@ func setupKeybindings(app *App) {
 			if app.cfg.AI.Aggressiveness > 1 {
 				app.cfg.AI.Aggressiveness--
 			}
+			text := aggressivenessText(app)
 			app.tapp.QueueUpdateDraw(func() {
-				app.decisionPanel.view.SetText(
-					fmt.Sprintf("[yellow]Aggressiveness: %d/10", app.cfg.AI.Aggressiveness))
+				app.decisionPanel.view.SetText(text)
 			})
 			return nil
 
@@ -82,9 +92,9 @@ func setupKeybindings(app *App) {
 			if app.cfg.AI.Aggressiveness < 10 {
 				app.cfg.AI.Aggressiveness++
 			}
+			text := aggressivenessText(app)
 			app.tapp.QueueUpdateDraw(func() {
-				app.decisionPanel.view.SetText(
-					fmt.Sprintf("[yellow]Aggressiveness: %d/10", app.cfg.AI.Aggressiveness))
+				app.decisionPanel.view.SetText(text)
 			})
 			return nil
 
@@ -109,10 +119,52 @@ func setupKeybindings(app *App) {
 			case 'a', 'A':
 				// AI evaluate selected process
 				pid := app.processTable.SelectedPID()
-				if pid > 0 && app.aiEngine != nil {
+				if pid > 0 && app.decider != nil {
 					go evaluateProcess(app, pid)
 				}
 				return nil
+			case ':':
+				// Natural-language request
+				showAskPrompt(app)
+				return nil
+			case 'p', 'P':
+				// Pending approvals
+				showPending(app)
+				return nil
+			case 'u', 'U':
+				// Undo a termination
+				showUndo(app)
+				return nil
+			case 'z', 'Z':
+				// Snooze selected process, or evaluate it again
+				pid := app.processTable.SelectedPID()
+				if pid > 0 {
+					showSnoozePrompt(app, pid)
+				}
+				return nil
+			case 'k', 'K':
+				// Keep selected process for good
+				if proc := findProcess(app, app.processTable.SelectedPID()); proc != nil {
+					go snoozeProcess(app, proc, 0)
+				}
+				return nil
+			case 'x', 'X':
+				// Cancel the termination of the selected process, or all
+				cancelTerminations(app, app.processTable.SelectedPID())
+				return nil
+			case 's', 'S':
+				// Suspend or resume selected process
+				pid := app.processTable.SelectedPID()
+				if pid > 0 {
+					suspendSelected(app, pid)
+				}
+				return nil
+			case 'l', 'L':
+				// Limit selected process, or restore its limits
+				if proc := findProcess(app, app.processTable.SelectedPID()); proc != nil {
+					limitSelected(app, proc)
+				}
+				return nil
 			}
 		}
 
@@ -135,7 +187,16 @@ func evaluateProcess(app *App, pid int) {
 		return
 	}
 
-	decision, err := app.aiEngine.EvaluateProcess(context.Background(), proc, metrics)
+	snoozes, _ := app.snoozes.List()
+	if sn, ok := snoozes.Find(proc); ok {
+		app.tapp.QueueUpdateDraw(func() {
+			app.decisionPanel.view.SetText(
+				fmt.Sprintf("[yellow]Not evaluating %s (PID %d): %s %s — press z to evaluate it again", proc.Name, proc.PID, sn.Target(), sn.Expiry()))
+		})
+		return
+	}
+
+	decision, err := app.decider.EvaluateProcess(context.Background(), proc, metrics)
 	if err != nil {
 		app.tapp.QueueUpdateDraw(func() {
 			app.decisionPanel.view.SetText(
@@ -145,8 +206,35 @@ func evaluateProcess(app *App, pid int) {
 	}
 
 	app.auditor.LogDecision(decision)
+
+	// Recommendations that need consent wait in the approval queue
+	parked := ""
+	var action safety.Action
+	switch decision.Action {
+	case ai.ActionTerminate:
+		action = safety.ActionTerminate
+	case ai.ActionThrottle:
+		action = safety.ActionThrottle
+	}
+	if action != "" && decision.Confidence >= app.cfg.AI.ConfidenceThreshold {
+		verdict := app.safetyMgr.EvaluateAction(proc, action)
+		if action == safety.ActionTerminate {
+			verdict = app.procMgr.TerminationVerdict(proc)
+		}
+		if verdict.Allowed && verdict.Confirm {
+			if item, _, err := notification.ParkForApproval(app.queue, app.auditor, proc, action, decision, "interactive"); err != nil {
+				parked = fmt.Sprintf("[red]Could not queue for approval: %v", err)
+			} else {
+				parked = fmt.Sprintf("[yellow]%s of %s waits for approval (%s) — press p", action, proc.Name, item.ID)
+			}
+		}
+	}
+
 	app.tapp.QueueUpdateDraw(func() {
 		app.decisionPanel.AddDecision(decision)
+		if parked != "" {
+			fmt.Fprintf(app.decisionPanel.view, "%s\n", parked)
+		}
 	})
 }
 
@@ -164,15 +252,70 @@ func terminateSelected(app *App, pid int) {
 		return
 	}
 
-	if app.safetyMgr.IsProtected(proc) {
+	if verdict := app.safetyMgr.Evaluate(proc); !verdict.Allowed {
+		app.auditor.LogBlocked(proc.PID, proc.Name, verdict.Reason, verdict.Rule)
 		app.tapp.QueueUpdateDraw(func() {
 			app.decisionPanel.view.SetText(
-				fmt.Sprintf("[red]Cannot terminate protected process: %s (PID %d)", proc.Name, proc.PID))
+				fmt.Sprintf("[red]Cannot terminate %s (PID %d): %s", proc.Name, proc.PID, verdict.Reason))
+		})
+		return
+	}
+
+	if proc.Container != "" {
+		// Choosing what to do to the container doubles as the confirmation
+		showContainerChoice(app, proc)
+		return
+	}
+	if proc.Unit != "" {
+		// Choosing what to do to the unit doubles as the confirmation
+		go func() {
+			unit, ok := app.procMgr.UnitOf(proc)
+			app.tapp.QueueUpdateDraw(func() {
+				if ok {
+					showUnitChoice(app, proc, unit)
+				} else {
+					confirmTermination(app, proc)
+				}
+			})
+		}()
+		return
+	}
+	confirmTermination(app, proc)
+}
+
+// confirmTermination terminates proc, asking first if the consent level
+// requires it for proc or any process terminated with it.
+func confirmTermination(app *App, proc *monitor.ProcessInfo) {
+	if app.procMgr.TerminationVerdict(proc).Confirm {
+		text := fmt.Sprintf("Terminate %s (PID %d)?", proc.Name, proc.PID)
+		showConfirm(app, text, func() {
+			go terminateConfirmed(app, proc, startTerminate(app, proc))
 		})
 		return
 	}
+	go terminateConfirmed(app, proc, startTerminate(app, proc))
+}
+
+// startTerminate returns a start function for terminateConfirmed doing
+// what the configuration says.
+func startTerminate(app *App, proc *monitor.ProcessInfo) func() (*process.Termination, error) {
+	return func() (*process.Termination, error) {
+		return app.procMgr.StartTerminate(app.ctx, proc, false)
+	}
+}
 
-	err := app.procMgr.SafeTerminate(proc, false)
+// terminateConfirmed terminates a process the user chose manually, started
+// by start.
+func terminateConfirmed(app *App, proc *monitor.ProcessInfo, start func() (*process.Termination, error)) {
+	pid := proc.PID
+	t, err := start()
+	var result *process.TerminationResult
+	var savings float64
+	var note string
+	if err == nil {
+		watchTerminations(app)
+		result, savings, note, err = finishTermination(app, t, "manual termination")
+	}
 	if err != nil {
 		app.tapp.QueueUpdateDraw(func() {
 			app.decisionPanel.view.SetText(
@@ -181,16 +324,207 @@ func terminateSelected(app *App, pid int) {
 		return
 	}
 
-	savings := app.powerCalc.EstimateSavings(proc)
-	app.powerMetrics.RecordSaving(proc.Name, proc.PID, savings, "manual termination")
-	app.auditor.LogTermination(proc.PID, proc.Name, "manual termination")
-
 	app.tapp.QueueUpdateDraw(func() {
+		if result.Paused() {
+			app.decisionPanel.view.SetText(
+				fmt.Sprintf("[green]%s: %s (PID %d)%s%s", label(app, "Paused", "Would pause"),
+					proc.Name, proc.PID, result.Extent(), result.ActionVia()))
+			return
+		}
 		app.decisionPanel.view.SetText(
-			fmt.Sprintf("[green]Terminated: %s (PID %d) | Saved: %.2fW", proc.Name, proc.PID, savings))
+			fmt.Sprintf("[green]%s: %s (PID %d)%s%s | %s: %.2fW%s", label(app, "Terminated", "Would "+result.Verb()),
+				proc.Name, proc.PID, result.Extent(), result.ActionVia(), label(app, "Saved", "Would save"), savings, note))
 	})
 }
 
+// terminateProcess terminates proc and the rest of its termination scope
+// through the safety-checked process manager and records it as
+// finishTermination does. It returns once the termination is over, which the
+// decision panel title follows meanwhile; callers run it off the UI
+// goroutine.
+func terminateProcess(app *App, proc *monitor.ProcessInfo, reason string) (*process.TerminationResult, float64, string, error) {
+	t, err := startTermination(app, proc)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	return finishTermination(app, t, reason)
+}
+
+// startTermination starts terminating proc in the background.
+func startTermination(app *App, proc *monitor.ProcessInfo) (*process.Termination, error) {
+	t, err := app.procMgr.StartTerminate(app.ctx, proc, false)
+	if err != nil {
+		return nil, err
+	}
+	watchTerminations(app)
+	return t, nil
+}
+
+// finishTermination waits for t and records its savings, audit entry and
+// restore record. note is "" unless the restore record could not be saved,
+// in which case it says so for the status message: the termination cannot
+// be undone.
+func finishTermination(app *App, t *process.Termination, reason string) (result *process.TerminationResult, savings float64, note string, err error) {
+	result, err = t.Wait()
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	proc := t.Proc
+	if result.Paused() {
+		// The processes keep their memory and cannot respawn or be relaunched
+		app.auditor.LogContainerPause(proc, notification.TerminationDetailsOf(result), reason)
+		return result, 0, "", nil
+	}
+	savings = app.powerCalc.EstimateGroupSavings(result.Members)
+	app.powerMetrics.RecordSaving(proc.Name, proc.PID, savings, reason)
+	id := app.auditor.LogScopedTermination(proc, notification.TerminationDetailsOf(result), reason, savings)
+	if app.procMgr.DryRun() {
+		return result, savings, "", nil
+	}
+	app.tracker.Track(id, proc)
+	if err := app.procMgr.SaveRestore(id, proc); err != nil {
+		note = fmt.Sprintf(" [yellow](cannot be undone: %v)", err)
+	}
+	return result, savings, note, nil
+}
+
+// suspendSelected resumes a stopped process, or suspends a running one
+// after checking the consent matrix.
+func suspendSelected(app *App, pid int) {
+	var proc *monitor.ProcessInfo
+	for _, p := range app.getProcesses() {
+		if p.PID == pid {
+			proc = p
+			break
+		}
+	}
+	if proc == nil {
+		return
+	}
+
+	if proc.State == "T" {
+		go func() {
+			text := fmt.Sprintf("[green]%s: %s (PID %d)", label(app, "Resumed", "Would resume"), proc.Name, proc.PID)
+			if err := app.procMgr.Resume(proc.PID); err != nil {
+				text = fmt.Sprintf("[red]Failed to resume PID %d: %v", proc.PID, err)
+			} else {
+				app.auditor.LogAction("resumed", proc, "manual resume")
+			}
+			app.tapp.QueueUpdateDraw(func() {
+				app.decisionPanel.view.SetText(text)
+			})
+		}()
+		return
+	}
+
+	verdict := app.safetyMgr.EvaluateAction(proc, safety.ActionSuspend)
+	if !verdict.Allowed {
+		app.auditor.LogBlocked(proc.PID, proc.Name, verdict.Reason, verdict.Rule)
+		app.tapp.QueueUpdateDraw(func() {
+			app.decisionPanel.view.SetText(
+				fmt.Sprintf("[red]Cannot suspend %s (PID %d): %s", proc.Name, proc.PID, verdict.Reason))
+		})
+		return
+	}
+
+	suspend := func() {
+		text := fmt.Sprintf("[green]%s: %s (PID %d) — press s again to resume", label(app, "Suspended", "Would suspend"), proc.Name, proc.PID)
+		if err := applyAction(app, proc, safety.ActionSuspend, "manual suspend"); err != nil {
+			text = fmt.Sprintf("[red]Failed to suspend PID %d: %v", proc.PID, err)
+		}
+		app.tapp.QueueUpdateDraw(func() {
+			app.decisionPanel.view.SetText(text)
+		})
+	}
+	if verdict.Confirm {
+		showConfirm(app, fmt.Sprintf("Suspend %s (PID %d)?", proc.Name, proc.PID), func() {
+			go suspend()
+		})
+		return
+	}
+	go suspend()
+}
+
+// limitSelected restores the limits of a process Aura limited, or applies
+// the limits of its policy rule or the throttle limits after checking the
+// consent matrix.
+func limitSelected(app *App, proc *monitor.ProcessInfo) {
+	show := func(text string) {
+		app.tapp.QueueUpdateDraw(func() {
+			app.decisionPanel.view.SetText(text)
+		})
+	}
+
+	if app.procMgr.Limited(proc) != nil {
+		go func() {
+			rec, err := app.procMgr.RevertLimits(proc.PID)
+			switch {
+			case rec == nil:
+				show(fmt.Sprintf("[red]Failed to restore the limits of PID %d: %v", proc.PID, err))
+			case err != nil:
+				show(fmt.Sprintf("[yellow]Restored %s (PID %d) to %s, except: %v", proc.Name, proc.PID, rec.Originals(), err))
+			default:
+				show(fmt.Sprintf("[green]%s %s (PID %d) to %s", label(app, "Restored", "Would restore"), proc.Name, proc.PID, rec.Originals()))
+			}
+		}()
+		return
+	}
+
+	limits := app.procMgr.LimitsFor(proc)
+	if limits.IsZero() {
+		app.decisionPanel.view.SetText("[yellow]No limits to apply: set safety.throttle_limits or the limits of a policy rule")
+		return
+	}
+	verdict := app.procMgr.LimitVerdict(proc, limits)
+	if !verdict.Allowed {
+		app.auditor.LogBlocked(proc.PID, proc.Name, verdict.Reason, verdict.Rule)
+		app.decisionPanel.view.SetText(
+			fmt.Sprintf("[red]Cannot limit %s (PID %d): %s", proc.Name, proc.PID, verdict.Reason))
+		return
+	}
+
+	limit := func() {
+		rec, err := app.procMgr.SafeLimit(proc, limits)
+		if rec == nil {
+			show(fmt.Sprintf("[red]Failed to limit PID %d: %v", proc.PID, err))
+			return
+		}
+		app.auditor.LogAction(safety.ActionLimit.Past(), proc, "manual limit: "+limits.String())
+		text := fmt.Sprintf("[green]%s %s (PID %d) to %s — press l again to restore", label(app, "Limited", "Would limit"), proc.Name, proc.PID, rec.Applied)
+		if err != nil {
+			text = fmt.Sprintf("[yellow]Limited %s (PID %d) to %s, except: %v", proc.Name, proc.PID, rec.Applied, err)
+		}
+		show(text)
+	}
+	if verdict.Confirm {
+		showConfirm(app, fmt.Sprintf("Limit %s (PID %d) to %s?", proc.Name, proc.PID, limits), func() {
+			go limit()
+		})
+		return
+	}
+	go limit()
+}
+
+// label returns done, or dryRun when the process manager only simulates
+// actions.
+func label(app *App, done, dryRun string) string {
+	if app.procMgr.DryRun() {
+		return dryRun
+	}
+	return done
+}
+
+// applyAction throttles, suspends or limits proc through the safety-checked process
+// manager and records the audit entry.
+func applyAction(app *App, proc *monitor.ProcessInfo, action safety.Action, reason string) error {
+	if err := app.procMgr.SafeApply(proc, action); err != nil {
+		return err
+	}
+	app.auditor.LogAction(action.Past(), proc, reason)
+	return nil
+}
+
 func showDependencyGraph(app *App, pid int) {
 	procs := app.getProcesses()
 
@@ -230,3 +564,14 @@ func showDependencyGraph(app *App, pid int) {
 
 	app.decisionPanel.view.SetText(text)
 }
+
+// aggressivenessText applies a changed aggressiveness and describes it,
+// noting when a schedule window overrides it.
+func aggressivenessText(app *App) string {
+	s, _ := app.applySchedule()
+	text := fmt.Sprintf("[yellow]Aggressiveness: %d/10", app.cfg.AI.Aggressiveness)
+	if s.Aggressiveness != app.cfg.AI.Aggressiveness {
+		text += fmt.Sprintf(" (%d/10 during schedule %s)", s.Aggressiveness, strings.Join(s.Windows, ", "))
+	}
+	return text
+}
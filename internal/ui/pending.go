@@ -0,0 +1,139 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/iamgilwell/aura/internal/monitor"
+	"github.com/iamgilwell/aura/internal/safety"
+)
+
+const pagePending = "pending"
+
+// showPending opens the approval pane: y approves, n denies, Esc closes.
+func showPending(app *App) {
+	table := tview.NewTable().SetSelectable(true, false).SetFixed(1, 0)
+	table.SetBorder(true).SetTitle(" Pending approvals — [y] approve  [n] deny  [Esc] close ")
+
+	var items []safety.PendingItem
+	refresh := func() {
+		items, _ = app.queue.List()
+		table.Clear()
+		for col, h := range []string{"ID", "ACTION", "PID", "NAME", "USER", "SOURCE", "CONF", "EXPIRES", "REASON"} {
+			table.SetCell(0, col, tview.NewTableCell(h).SetTextColor(tcell.ColorYellow).SetSelectable(false))
+		}
+		for i, it := range items {
+			row := i + 1
+			table.SetCell(row, 0, tview.NewTableCell(it.ID))
+			table.SetCell(row, 1, tview.NewTableCell(string(it.Intervention())))
+			table.SetCell(row, 2, tview.NewTableCell(fmt.Sprintf("%d", it.PID)))
+			table.SetCell(row, 3, tview.NewTableCell(it.Name))
+			table.SetCell(row, 4, tview.NewTableCell(it.User))
+			table.SetCell(row, 5, tview.NewTableCell(it.Source))
+			table.SetCell(row, 6, tview.NewTableCell(fmt.Sprintf("%.2f", it.Confidence)))
+			table.SetCell(row, 7, tview.NewTableCell(time.Until(it.ExpiresAt).Truncate(time.Minute).String()))
+			table.SetCell(row, 8, tview.NewTableCell(it.Reason).SetExpansion(1))
+		}
+		if len(items) == 0 {
+			table.SetCell(1, 0, tview.NewTableCell("No actions are waiting for approval").
+				SetTextColor(tcell.ColorGray).SetSelectable(false))
+		}
+	}
+	refresh()
+
+	closePane := func() {
+		app.pages.RemovePage(pagePending)
+		app.tapp.SetFocus(app.processTable.table)
+	}
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			closePane()
+			return nil
+		}
+		row, _ := table.GetSelection()
+		if row < 1 || row > len(items) {
+			return event
+		}
+		it := items[row-1]
+
+		switch event.Rune() {
+		case 'y', 'Y':
+			go approvePending(app, it.ID)
+			closePane()
+			return nil
+		case 'n', 'N':
+			if taken, err := app.queue.Take(it.ID); err == nil {
+				app.auditor.LogApproval("denied", taken.ID, taken.PID, taken.Name, taken.Reason)
+				app.decisionPanel.view.SetText(fmt.Sprintf("[yellow]Denied %s of %s (PID %d)", taken.Intervention(), taken.Name, taken.PID))
+			}
+			refresh()
+			return nil
+		}
+		return event
+	})
+
+	app.pages.AddPage(pagePending, table, true, true)
+	app.tapp.SetFocus(table)
+}
+
+// approvePending resolves an approval and acts on the process if it is
+// still the one that was parked.
+func approvePending(app *App, id string) {
+	show := func(text string) {
+		app.tapp.QueueUpdateDraw(func() {
+			app.decisionPanel.view.SetText(text)
+		})
+	}
+
+	item, err := app.queue.Take(id)
+	if err != nil {
+		if errors.Is(err, safety.ErrPendingExpired) {
+			app.auditor.LogApproval("approval_expired", item.ID, item.PID, item.Name, item.Reason)
+		}
+		show(fmt.Sprintf("[red]%v", err))
+		return
+	}
+	app.auditor.LogApproval("approved", item.ID, item.PID, item.Name, item.Reason)
+
+	var proc *monitor.ProcessInfo
+	for _, p := range app.getProcesses() {
+		if item.Matches(p) {
+			proc = p
+			break
+		}
+	}
+	if proc == nil {
+		show(fmt.Sprintf("[yellow]%s (PID %d) is no longer running", item.Name, item.PID))
+		return
+	}
+
+	// A failed action leaves the approval pending rather than losing it
+	requeue := func() string {
+		if err := app.queue.Requeue(item); err != nil {
+			return fmt.Sprintf(" (could not keep %s pending: %v)", item.ID, err)
+		}
+		return fmt.Sprintf("; %s is still pending", item.ID)
+	}
+
+	if action := item.Intervention(); action != safety.ActionTerminate {
+		if err := applyAction(app, proc, action, "approved: "+item.Reason); err != nil {
+			show(fmt.Sprintf("[red]Failed to %s PID %d: %v%s", action, proc.PID, err, requeue()))
+			return
+		}
+		show(fmt.Sprintf("[green]%s %s to %s (PID %d)", label(app, "Applied", "Would apply"), action, proc.Name, proc.PID))
+		return
+	}
+
+	result, savings, note, err := terminateProcess(app, proc, "approved: "+item.Reason)
+	if err != nil {
+		show(fmt.Sprintf("[red]Failed to terminate PID %d: %v%s", proc.PID, err, requeue()))
+		return
+	}
+	show(fmt.Sprintf("[green]%s: %s (PID %d)%s | %s: %.2fW%s", label(app, "Terminated", "Would terminate"),
+		proc.Name, proc.PID, result.Extent(), label(app, "Saved", "Would save"), savings, note))
+}
@@ -0,0 +1,169 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/iamgilwell/aura/internal/ai"
+	"github.com/iamgilwell/aura/internal/monitor"
+	"github.com/iamgilwell/aura/internal/process"
+)
+
+const (
+	pageMain    = "main"
+	pagePrompt  = "prompt"
+	pageConfirm = "confirm"
+)
+
+// showAskPrompt opens an input line for a natural-language request.
+func showAskPrompt(app *App) {
+	if app.aiEngine == nil {
+		app.decisionPanel.view.SetText("[yellow]AI engine is not configured. Set ANTHROPIC_API_KEY to enable.")
+		return
+	}
+
+	input := tview.NewInputField().
+		SetLabel("Ask Aura: ").
+		SetFieldBackgroundColor(tcell.ColorDarkSlateGray)
+	input.SetBorder(true)
+
+	input.SetDoneFunc(func(key tcell.Key) {
+		app.pages.RemovePage(pagePrompt)
+		app.tapp.SetFocus(app.processTable.table)
+
+		request := strings.TrimSpace(input.GetText())
+		if key != tcell.KeyEnter || request == "" {
+			return
+		}
+		app.decisionPanel.view.SetText(fmt.Sprintf("[yellow]Thinking about: %s", request))
+		go askAura(app, request)
+	})
+
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(nil, 0, 1, false).
+		AddItem(input, 3, 0, true)
+
+	app.pages.AddPage(pagePrompt, layout, true, true)
+	app.tapp.SetFocus(input)
+}
+
+// askAura interprets a request and shows the resulting plan. Actions are
+// only carried out after the user confirms them in a dialog.
+func askAura(app *App, request string) {
+	// Snoozed and kept processes are neither sent to the AI nor acted on
+	snoozes, err := app.snoozes.List()
+	if err != nil {
+		app.tapp.QueueUpdateDraw(func() {
+			app.decisionPanel.view.SetText(fmt.Sprintf("[red]Could not read snoozed processes: %v", err))
+		})
+		return
+	}
+	procs := snoozes.Filter(app.getProcesses())
+	plan, err := app.aiEngine.Interpret(context.Background(), request, procs, app.getMetrics())
+	if err != nil {
+		app.tapp.QueueUpdateDraw(func() {
+			app.decisionPanel.view.SetText(fmt.Sprintf("[red]Could not interpret request: %v", err))
+		})
+		return
+	}
+	app.auditor.LogEvent("ask", fmt.Sprintf("request=%q intent=%s action=%s", request, plan.Intent, plan.Action))
+
+	targets := plan.Query.Filter(procs)
+
+	var sb strings.Builder
+	if plan.Answer != "" {
+		sb.WriteString(fmt.Sprintf("[white]%s\n", plan.Answer))
+	}
+
+	var allowed []*monitor.ProcessInfo
+	for _, p := range targets {
+		status := ""
+		if plan.Intent == ai.IntentAction {
+			if v := app.procMgr.TerminationVerdict(p); v.Allowed {
+				allowed = append(allowed, p)
+				status = "[red]will " + string(plan.Action)
+			} else {
+				status = "[gray]blocked: " + v.Reason
+			}
+		}
+		sb.WriteString(fmt.Sprintf("[white]PID=%-7d %-20s CPU=%.1f%% MEM=%.1fMB %s\n",
+			p.PID, p.Name, p.CPU, p.MemoryMB, status))
+	}
+	if len(targets) == 0 && plan.Intent != ai.IntentExplain {
+		sb.WriteString("[gray]No matching processes.")
+	}
+
+	app.tapp.QueueUpdateDraw(func() {
+		app.decisionPanel.view.SetText(sb.String())
+		if plan.Intent == ai.IntentAction && len(allowed) > 0 {
+			text := fmt.Sprintf("%s %d process(es) matching %q?", plan.Action, len(allowed), request)
+			showConfirm(app, text, func() {
+				go func() {
+					// A snooze may have been added while the dialog was open
+					snoozes, err := app.snoozes.List()
+					if err != nil {
+						app.tapp.QueueUpdateDraw(func() {
+							app.decisionPanel.view.SetText(fmt.Sprintf("[red]Could not read snoozed processes: %v", err))
+						})
+						return
+					}
+					terminateAll(app, snoozes.Filter(allowed), fmt.Sprintf("ask: %s", request))
+				}()
+			})
+		}
+	})
+}
+
+// showConfirm displays a yes/no dialog and runs onYes if confirmed.
+func showConfirm(app *App, text string, onYes func()) {
+	modal := tview.NewModal().
+		SetText(text).
+		AddButtons([]string{"No", "Yes"}).
+		SetDoneFunc(func(_ int, label string) {
+			app.pages.RemovePage(pageConfirm)
+			app.tapp.SetFocus(app.processTable.table)
+			if label == "Yes" {
+				onYes()
+			}
+		})
+
+	app.pages.AddPage(pageConfirm, modal, true, true)
+	app.tapp.SetFocus(modal)
+}
+
+func terminateAll(app *App, procs []*monitor.ProcessInfo, reason string) {
+	var sb strings.Builder
+	var total float64
+
+	// Start every termination first so they proceed concurrently
+	started := make([]*process.Termination, len(procs))
+	for i, p := range procs {
+		t, err := startTermination(app, p)
+		if err != nil {
+			sb.WriteString(fmt.Sprintf("[red]Failed to terminate PID %d: %v\n", p.PID, err))
+			continue
+		}
+		started[i] = t
+	}
+	for i, p := range procs {
+		if started[i] == nil {
+			continue
+		}
+		result, savings, note, err := finishTermination(app, started[i], reason)
+		if err != nil {
+			sb.WriteString(fmt.Sprintf("[red]Failed to terminate PID %d: %v\n", p.PID, err))
+			continue
+		}
+		total += savings
+		sb.WriteString(fmt.Sprintf("[green]%s: %s (PID %d)%s%s\n", label(app, "Terminated", "Would terminate"), p.Name, p.PID, result.Extent(), note))
+	}
+	sb.WriteString(fmt.Sprintf("[yellow]%s: %.2fW", label(app, "Saved", "Would save"), total))
+
+	app.tapp.QueueUpdateDraw(func() {
+		app.decisionPanel.view.SetText(sb.String())
+	})
+}
@@ -0,0 +1,103 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/iamgilwell/aura/internal/ai"
+	"github.com/iamgilwell/aura/internal/monitor"
+	"github.com/iamgilwell/aura/internal/safety"
+)
+
+// showSnoozePrompt asks how long to leave the selected process alone. If it
+// is already kept or snoozed, the snooze is lifted instead.
+func showSnoozePrompt(app *App, pid int) {
+	proc := findProcess(app, pid)
+	if proc == nil {
+		return
+	}
+
+	snoozes, _ := app.snoozes.List()
+	if _, ok := snoozes.Find(proc); ok {
+		go unsnoozeProcess(app, proc)
+		return
+	}
+
+	input := tview.NewInputField().
+		SetLabel(fmt.Sprintf("Snooze %s (PID %d) for (e.g. 30m, 4h, 7d, keep): ", proc.Name, proc.PID)).
+		SetFieldBackgroundColor(tcell.ColorDarkSlateGray)
+	input.SetBorder(true)
+
+	input.SetDoneFunc(func(key tcell.Key) {
+		app.pages.RemovePage(pagePrompt)
+		app.tapp.SetFocus(app.processTable.table)
+
+		text := strings.TrimSpace(input.GetText())
+		if key != tcell.KeyEnter || text == "" {
+			return
+		}
+		var d time.Duration
+		if text != "keep" {
+			var err error
+			if d, err = ai.ParseDuration(text); err != nil || d <= 0 {
+				app.decisionPanel.view.SetText(fmt.Sprintf("[red]Invalid duration %q: use e.g. 30m, 4h, 7d or keep", text))
+				return
+			}
+		}
+		go snoozeProcess(app, proc, d)
+	})
+
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(nil, 0, 1, false).
+		AddItem(input, 3, 0, true)
+
+	app.pages.AddPage(pagePrompt, layout, true, true)
+	app.tapp.SetFocus(input)
+}
+
+// snoozeProcess leaves the identity of proc alone for d, or for good if d
+// is zero.
+func snoozeProcess(app *App, proc *monitor.ProcessInfo, d time.Duration) {
+	s := safety.NewSnooze(proc, d)
+	s.Source = "interactive"
+
+	s, err := app.snoozes.Add(s)
+	text := fmt.Sprintf("[green]%s %s — press z to evaluate it again", s.Target(), s.Expiry())
+	if err != nil {
+		text = fmt.Sprintf("[red]Could not snooze %s: %v", proc.Name, err)
+	} else {
+		app.auditor.LogEvent("snooze", fmt.Sprintf("id=%s target=%q %s, reason=%q", s.ID, s.Target(), s.Expiry(), s.Reason))
+	}
+	app.tapp.QueueUpdateDraw(func() {
+		app.decisionPanel.view.SetText(text)
+	})
+}
+
+// unsnoozeProcess lifts the snoozes covering proc.
+func unsnoozeProcess(app *App, proc *monitor.ProcessInfo) {
+	removed, err := app.snoozes.RemoveProcess(proc)
+	text := fmt.Sprintf("[green]Aura evaluates %s again", proc.Name)
+	if err != nil {
+		text = fmt.Sprintf("[red]Could not lift the snooze of %s: %v", proc.Name, err)
+	}
+	for _, s := range removed {
+		app.auditor.LogEvent("unsnooze", fmt.Sprintf("id=%s target=%q", s.ID, s.Target()))
+	}
+	app.tapp.QueueUpdateDraw(func() {
+		app.decisionPanel.view.SetText(text)
+	})
+}
+
+// findProcess returns the process with the given PID from the last scan.
+func findProcess(app *App, pid int) *monitor.ProcessInfo {
+	for _, p := range app.getProcesses() {
+		if p.PID == pid {
+			return p
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/iamgilwell/aura/internal/process"
+)
+
+const pageUndo = "undo"
+
+// showUndo opens the list of terminations that can be undone: u or Enter
+// relaunches the selected process, Esc closes.
+func showUndo(app *App) {
+	store := app.procMgr.Restores()
+	if store == nil {
+		app.decisionPanel.view.SetText("[yellow]Undo is disabled (safety.restore_dir is empty)")
+		return
+	}
+
+	table := tview.NewTable().SetSelectable(true, false).SetFixed(1, 0)
+	table.SetBorder(true).SetTitle(" Undo terminations — [u/Enter] relaunch  [Esc] close ")
+
+	recs, _ := store.List()
+	for col, h := range []string{"ID", "NAME", "PID", "USER", "TERMINATED", "COMMAND"} {
+		table.SetCell(0, col, tview.NewTableCell(h).SetTextColor(tcell.ColorYellow).SetSelectable(false))
+	}
+	for i, r := range recs {
+		row := i + 1
+		table.SetCell(row, 0, tview.NewTableCell(r.ID))
+		table.SetCell(row, 1, tview.NewTableCell(r.Name))
+		table.SetCell(row, 2, tview.NewTableCell(fmt.Sprintf("%d", r.PID)))
+		table.SetCell(row, 3, tview.NewTableCell(r.User))
+		table.SetCell(row, 4, tview.NewTableCell(time.Since(r.TerminatedAt).Truncate(time.Minute).String()+" ago"))
+		table.SetCell(row, 5, tview.NewTableCell(r.Command()).SetExpansion(1))
+	}
+	if len(recs) == 0 {
+		table.SetCell(1, 0, tview.NewTableCell("No terminations can be undone").
+			SetTextColor(tcell.ColorGray).SetSelectable(false))
+	}
+
+	closePane := func() {
+		app.pages.RemovePage(pageUndo)
+		app.tapp.SetFocus(app.processTable.table)
+	}
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			closePane()
+			return nil
+		}
+		row, _ := table.GetSelection()
+		if row < 1 || row > len(recs) {
+			return event
+		}
+		if event.Key() == tcell.KeyEnter || event.Rune() == 'u' || event.Rune() == 'U' {
+			go relaunch(app, recs[row-1])
+			closePane()
+			return nil
+		}
+		return event
+	})
+
+	app.pages.AddPage(pageUndo, table, true, true)
+	app.tapp.SetFocus(table)
+}
+
+// relaunch undoes a termination and reports the result.
+func relaunch(app *App, rec *process.RestoreRecord) {
+	_, pid, err := app.procMgr.Undo(rec.ID)
+	app.auditor.LogRelaunch(rec.ID, rec.Name, pid, err)
+
+	text := fmt.Sprintf("[green]Relaunched %s as PID %d", rec.Name, pid)
+	if app.procMgr.DryRun() {
+		text = fmt.Sprintf("[green]Would relaunch %s: %s", rec.Name, rec.Command())
+	}
+	if err != nil {
+		text = fmt.Sprintf("[red]Failed to relaunch %s: %v", rec.Name, err)
+	}
+	app.tapp.QueueUpdateDraw(func() {
+		app.decisionPanel.view.SetText(text)
+	})
+}
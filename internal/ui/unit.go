@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"fmt"
+	"syscall"
+
+	"github.com/rivo/tview"
+
+	"github.com/iamgilwell/aura/internal/monitor"
+	"github.com/iamgilwell/aura/internal/process"
+	"github.com/iamgilwell/aura/internal/safety"
+)
+
+const (
+	pageUnit      = "unit"
+	pageContainer = "container"
+)
+
+// unitChoices maps the buttons of the unit dialog to unit actions.
+var unitChoices = map[string]safety.UnitAction{
+	"Stop unit":      safety.UnitStop,
+	"Restart unit":   safety.UnitRestart,
+	"Kill unit":      safety.UnitKill,
+	"Signal process": safety.UnitSignal,
+}
+
+// showUnitChoice asks what to do about a process owned by a systemd unit:
+// stop, restart or kill the unit through systemctl, or signal the process
+// as usual.
+func showUnitChoice(app *App, proc *monitor.ProcessInfo, unit process.Unit) {
+	text := fmt.Sprintf("%s (PID %d) belongs to %s.", proc.Name, proc.PID, unit.Name)
+	if unit.Restart != "" {
+		text += fmt.Sprintf(" Restart=%s", unit.Restart)
+		if process.RestartsAfter(unit.Restart, syscall.SIGTERM) || process.RestartsAfter(unit.Restart, syscall.SIGKILL) {
+			text += ": systemd restarts it if only the process is killed"
+		}
+		text += "."
+	}
+
+	buttons := []string{"Stop unit", "Restart unit", "Kill unit", "Signal process", "Cancel"}
+	if !unit.Service() {
+		buttons = []string{"Stop unit", "Kill unit", "Signal process", "Cancel"}
+	}
+	modal := tview.NewModal().
+		SetText(text).
+		AddButtons(buttons).
+		SetDoneFunc(func(_ int, label string) {
+			app.pages.RemovePage(pageUnit)
+			app.tapp.SetFocus(app.processTable.table)
+			if action, ok := unitChoices[label]; ok {
+				go terminateConfirmed(app, proc, func() (*process.Termination, error) {
+					return app.procMgr.StartUnitAction(app.ctx, proc, action)
+				})
+			}
+		})
+
+	app.pages.AddPage(pageUnit, modal, true, true)
+	app.tapp.SetFocus(modal)
+}
+
+// containerChoices maps the buttons of the container dialog to container
+// actions.
+var containerChoices = map[string]safety.ContainerAction{
+	"Stop container":  safety.ContainerStop,
+	"Pause container": safety.ContainerPause,
+	"Signal process":  safety.ContainerSignal,
+}
+
+// showContainerChoice asks what to do about a process running in a
+// container: stop or pause the container through its runtime, or signal the
+// process as usual.
+func showContainerChoice(app *App, proc *monitor.ProcessInfo) {
+	text := fmt.Sprintf("%s (PID %d) runs in container %s.", proc.Name, proc.PID, proc.ContainerLabel())
+	modal := tview.NewModal().
+		SetText(text).
+		AddButtons([]string{"Stop container", "Pause container", "Signal process", "Cancel"}).
+		SetDoneFunc(func(_ int, label string) {
+			app.pages.RemovePage(pageContainer)
+			app.tapp.SetFocus(app.processTable.table)
+			if action, ok := containerChoices[label]; ok {
+				go terminateConfirmed(app, proc, func() (*process.Termination, error) {
+					return app.procMgr.StartContainerAction(app.ctx, proc, action)
+				})
+			}
+		})
+
+	app.pages.AddPage(pageContainer, modal, true, true)
+	app.tapp.SetFocus(modal)
+}
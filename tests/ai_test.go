@@ -1,6 +1,10 @@
 package tests
 
 import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -131,3 +135,191 @@ func TestActionConstants(t *testing.T) {
 		t.Error("ActionNotify wrong")
 	}
 }
+
+func TestParseCommandPlan(t *testing.T) {
+	text := `Here is the plan:
+{"intent":"action","query":{"name":"node","min_age":"1d","category":"User"},"action":"terminate"}`
+
+	plan, err := ai.ParseCommandPlan(text)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if plan.Intent != ai.IntentAction || plan.Action != ai.ActionTerminate {
+		t.Errorf("got intent=%s action=%s", plan.Intent, plan.Action)
+	}
+	if time.Duration(plan.Query.MinAge) != 24*time.Hour {
+		t.Errorf("min_age = %s, want 24h", time.Duration(plan.Query.MinAge))
+	}
+
+	if _, err := ai.ParseCommandPlan(`{"intent":"action","action":"keep"}`); err == nil {
+		t.Error("expected error for non-terminate action")
+	}
+	if _, err := ai.ParseCommandPlan(`{"intent":"reboot"}`); err == nil {
+		t.Error("expected error for unknown intent")
+	}
+}
+
+func TestProcessQueryFilter(t *testing.T) {
+	now := time.Now()
+	procs := []*monitor.ProcessInfo{
+		{PID: 10, Name: "node", User: "dev", CPU: 5, Category: monitor.CategoryUser, StartTime: now.Add(-48 * time.Hour)},
+		{PID: 11, Name: "node", User: "dev", CPU: 50, Category: monitor.CategoryUser, StartTime: now.Add(-time.Hour)},
+		{PID: 12, Name: "nodemon", User: "dev", CPU: 1, Category: monitor.CategoryUser, StartTime: now.Add(-72 * time.Hour)},
+		{PID: 13, Name: "sshd", User: "root", CPU: 0, Category: monitor.CategoryEssential, StartTime: now.Add(-72 * time.Hour)},
+	}
+
+	q := ai.ProcessQuery{Name: "node*", MinAge: ai.JSONDuration(24 * time.Hour)}
+	got := q.Filter(procs)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 stale node processes, got %d", len(got))
+	}
+	if got[0].PID != 10 {
+		t.Errorf("expected results sorted by CPU, got PID %d first", got[0].PID)
+	}
+
+	q = ai.ProcessQuery{Category: "essential"}
+	if got := q.Filter(procs); len(got) != 1 || got[0].PID != 13 {
+		t.Errorf("category filter returned %v", got)
+	}
+
+	q = ai.ProcessQuery{Limit: 1, SortBy: "age"}
+	if got := q.Filter(procs); len(got) != 1 || got[0].StartTime.After(now.Add(-72*time.Hour)) {
+		t.Errorf("expected the oldest process, got %v", got)
+	}
+}
+
+func TestTrainLocalModel(t *testing.T) {
+	var samples []ai.TrainingSample
+	for i := 0; i < 40; i++ {
+		// Idle, long-running user processes were terminated; busy ones kept
+		samples = append(samples,
+			ai.TrainingSample{Features: &ai.ProcessFeatures{CPU: 0.1, MemoryMB: 800, AgeSeconds: 86400 * 3, Category: "User", UID: 1000}, Label: 1},
+			ai.TrainingSample{Features: &ai.ProcessFeatures{CPU: 40, MemoryMB: 200, AgeSeconds: 600, Category: "User", UID: 1000}, Label: 0},
+		)
+	}
+
+	model, err := ai.TrainLocalModel(samples, 300, 0.1, 0.001)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if model.Accuracy < 0.99 {
+		t.Errorf("expected separable data to be learned, accuracy %.2f", model.Accuracy)
+	}
+
+	stale := model.Predict(&ai.ProcessFeatures{CPU: 0.2, MemoryMB: 700, AgeSeconds: 86400 * 2, Category: "User", UID: 1000})
+	busy := model.Predict(&ai.ProcessFeatures{CPU: 35, MemoryMB: 150, AgeSeconds: 300, Category: "User", UID: 1000})
+	if stale <= busy {
+		t.Errorf("stale score %.2f should exceed busy score %.2f", stale, busy)
+	}
+
+	path := filepath.Join(t.TempDir(), "model.json")
+	if err := model.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := ai.LoadLocalModel(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Samples != 80 {
+		t.Errorf("loaded model samples = %d, want 80", loaded.Samples)
+	}
+
+	if _, err := ai.TrainLocalModel(nil, 10, 0.1, 0); err == nil {
+		t.Error("expected error with no samples")
+	}
+}
+
+type stubDecider struct{ calls int }
+
+func (s *stubDecider) EvaluateProcess(_ context.Context, proc *monitor.ProcessInfo, _ *monitor.SystemMetrics) (*ai.DecisionResponse, error) {
+	s.calls++
+	return &ai.DecisionResponse{ProcessPID: proc.PID, Action: ai.ActionNotify, Source: "remote"}, nil
+}
+
+func TestScreenedDecider(t *testing.T) {
+	// A model with no weights scores every process at sigmoid(bias)
+	uncertain := &ai.LocalModel{
+		Weights: make([]float64, len(ai.FeatureNames)),
+		Mean:    make([]float64, len(ai.FeatureNames)),
+		Std:     ones(len(ai.FeatureNames)),
+	}
+	confidentKeep := *uncertain
+	confidentKeep.Bias = -5
+
+	proc := &monitor.ProcessInfo{PID: 42, Name: "worker", StartTime: time.Now()}
+	remote := &stubDecider{}
+
+	d := ai.NewScreenedDecider(&confidentKeep, remote, 0.2, 0.9)
+	got, _ := d.EvaluateProcess(context.Background(), proc, &monitor.SystemMetrics{})
+	if got.Action != ai.ActionKeep || got.Source != "local" || remote.calls != 0 {
+		t.Errorf("expected local keep without remote call, got %s/%s calls=%d", got.Action, got.Source, remote.calls)
+	}
+
+	d = ai.NewScreenedDecider(uncertain, remote, 0.2, 0.9)
+	got, _ = d.EvaluateProcess(context.Background(), proc, &monitor.SystemMetrics{})
+	if got.Source != "remote" || remote.calls != 1 {
+		t.Errorf("expected uncertain case to go remote, got %s calls=%d", got.Source, remote.calls)
+	}
+
+	d = ai.NewScreenedDecider(uncertain, nil, 0.2, 0.9)
+	got, _ = d.EvaluateProcess(context.Background(), proc, &monitor.SystemMetrics{})
+	if got.Action != ai.ActionKeep {
+		t.Errorf("expected keep without a remote decider, got %s", got.Action)
+	}
+}
+
+func ones(n int) []float64 {
+	v := make([]float64, n)
+	for i := range v {
+		v[i] = 1
+	}
+	return v
+}
+
+type fixedDecider struct{ action ai.Action }
+
+func (f fixedDecider) EvaluateProcess(_ context.Context, proc *monitor.ProcessInfo, _ *monitor.SystemMetrics) (*ai.DecisionResponse, error) {
+	return &ai.DecisionResponse{ProcessPID: proc.PID, ProcessName: proc.Name, Action: f.action}, nil
+}
+
+func TestShadowDecider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shadow.jsonl")
+	report, err := ai.NewShadowReport(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Shadow agrees on "keep" but would terminate what the primary keeps
+	primary := fixedDecider{action: ai.ActionKeep}
+	d := ai.NewShadowDecider(primary, fixedDecider{action: ai.ActionTerminate}, report, time.Second)
+
+	for pid := 1; pid <= 3; pid++ {
+		got, err := d.EvaluateProcess(context.Background(), &monitor.ProcessInfo{PID: 1000 + pid, Name: "worker"}, &monitor.SystemMetrics{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Action != ai.ActionKeep {
+			t.Fatalf("shadow mode must return the primary decision, got %s", got.Action)
+		}
+	}
+	if err := d.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := report.Stats()
+	if stats.Total != 3 || stats.Disagreed != 3 || stats.Pairs["keep->terminate"] != 3 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected 3 divergences and a summary, got %d lines", len(lines))
+	}
+	if !strings.Contains(lines[3], `"event":"summary"`) {
+		t.Errorf("last line should be the summary: %s", lines[3])
+	}
+}
@@ -1,6 +1,11 @@
 package tests
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -105,3 +110,181 @@ func TestClassifier(t *testing.T) {
 		})
 	}
 }
+
+func TestParseMemoryPressure(t *testing.T) {
+	psi := "some avg10=31.50 avg60=12.02 avg300=3.10 total=81234567\n" +
+		"full avg10=12.40 avg60=4.00 avg300=1.00 total=40123456\n"
+	meminfo := "MemTotal:       16384000 kB\nMemFree:          204800 kB\nMemAvailable:     819200 kB\n"
+
+	p := monitor.ParseMemoryPressure(psi, meminfo)
+	if !p.PSI || p.Some != 31.5 || p.Full != 12.4 {
+		t.Errorf("PSI = %v, some %.2f, full %.2f; want true, 31.50, 12.40", p.PSI, p.Some, p.Full)
+	}
+	if p.TotalMB != 16000 || p.AvailableMB != 800 || p.AvailablePercent() != 5 {
+		t.Errorf("memory = %.0f/%.0f MB (%.1f%%), want 800/16000 MB (5%%)", p.AvailableMB, p.TotalMB, p.AvailablePercent())
+	}
+	if want := "full 12.4%, some 31.5%, 800 MB (5.0%) available"; p.String() != want {
+		t.Errorf("String() = %q, want %q", p.String(), want)
+	}
+
+	// Kernels without PSI report available memory only
+	p = monitor.ParseMemoryPressure("", meminfo)
+	if p.PSI || p.Full != 0 || p.AvailableMB != 800 {
+		t.Errorf("without PSI: %+v", p)
+	}
+	if want := "800 MB (5.0%) available"; p.String() != want {
+		t.Errorf("String() = %q, want %q", p.String(), want)
+	}
+}
+
+func TestParseCgroupPath(t *testing.T) {
+	tests := []struct {
+		content string
+		path    string
+		unit    string
+	}{
+		{"0::/system.slice/nginx.service\n", "/system.slice/nginx.service", "nginx.service"},
+		{"12:cpu,cpuacct:/user.slice\n1:name=systemd:/user.slice/user-1000.slice/session-2.scope\n0::/\n", "/", ""},
+		{"12:cpu,cpuacct:/user.slice\n1:name=systemd:/user.slice/user-1000.slice/session-2.scope\n", "/user.slice/user-1000.slice/session-2.scope", "session-2.scope"},
+		{"0::/user.slice/user-1000.slice/user@1000.service/app.slice/app-firefox-42.scope\n",
+			"/user.slice/user-1000.slice/user@1000.service/app.slice/app-firefox-42.scope", "app-firefox-42.scope"},
+	}
+
+	for _, tt := range tests {
+		path := monitor.ParseCgroupPath(tt.content)
+		if path != tt.path {
+			t.Errorf("ParseCgroupPath(%q) = %q, want %q", tt.content, path, tt.path)
+		}
+		if unit := monitor.UnitFromCgroup(path); unit != tt.unit {
+			t.Errorf("UnitFromCgroup(%q) = %q, want %q", path, unit, tt.unit)
+		}
+	}
+}
+
+func TestContainerFromCgroup(t *testing.T) {
+	id := strings.Repeat("3f9c1a7e", 8)
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/system.slice/docker-" + id + ".scope", id},
+		{"/docker/" + id, id},
+		{"/machine.slice/libpod-" + id + ".scope/container", id},
+		{"/user.slice/user-1000.slice/user@1000.service/user.slice/libpod-" + id + ".scope", id},
+		{"/kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod1.slice/cri-containerd-" + id + ".scope", id},
+		{"/kubepods/burstable/pod1/" + id, id},
+		{"/machine.slice/libpod-conmon-" + id + ".scope", ""},
+		{"/system.slice/docker.service", ""},
+		{"/" + id[:12], ""},
+	}
+
+	for _, tt := range tests {
+		if got := monitor.ContainerFromCgroup(tt.path); got != tt.want {
+			t.Errorf("ContainerFromCgroup(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+
+	p := &monitor.ProcessInfo{Container: id}
+	if got := p.ContainerLabel(); got != id[:12] {
+		t.Errorf("ContainerLabel() without a name = %q, want the short ID", got)
+	}
+	p.ContainerName = "web"
+	if got := p.ContainerLabel(); got != "web" {
+		t.Errorf("ContainerLabel() = %q, want web", got)
+	}
+}
+
+func TestPinSet(t *testing.T) {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Skip("cannot resolve test executable")
+	}
+	data, err := os.ReadFile(exe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(data)
+
+	pins := monitor.NewPinSet([]monitor.Pin{
+		{Name: "sshd", Exe: exe, SHA256: hex.EncodeToString(sum[:])},
+		{Name: "dbus-daemon", Exe: "/usr/bin/dbus-daemon"},
+	})
+
+	tests := []struct {
+		name   string
+		proc   *monitor.ProcessInfo
+		status monitor.PinStatus
+	}{
+		{"verified by name, path and hash", &monitor.ProcessInfo{PID: os.Getpid(), Name: "sshd", Exe: exe}, monitor.PinVerified},
+		{"renamed pinned binary", &monitor.ProcessInfo{PID: os.Getpid(), Name: "innocent", Exe: exe}, monitor.PinVerified},
+		{"spoofed name", &monitor.ProcessInfo{PID: 4242, Name: "dbus-daemon", Exe: "/tmp/dbus-daemon"}, monitor.PinMismatch},
+		{"unreadable exe", &monitor.ProcessInfo{PID: 4243, Name: "dbus-daemon"}, monitor.PinUnverifiable},
+		{"not pinned", &monitor.ProcessInfo{PID: 4244, Name: "firefox", Exe: "/usr/bin/firefox"}, monitor.PinNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if status, why := pins.Verify(tt.proc); status != tt.status {
+				t.Errorf("Verify = %v (%s), want %v", status, why, tt.status)
+			}
+		})
+	}
+
+	tampered := monitor.NewPinSet([]monitor.Pin{{Name: "sshd", Exe: exe, SHA256: strings.Repeat("0", 64)}})
+	if status, _ := tampered.Verify(&monitor.ProcessInfo{PID: os.Getpid(), Name: "sshd", Exe: exe}); status != monitor.PinMismatch {
+		t.Errorf("hash mismatch: Verify = %v, want %v", status, monitor.PinMismatch)
+	}
+
+	c := monitor.NewClassifier([]string{"dbus-daemon"})
+	c.SetPins(pins)
+	spoof := &monitor.ProcessInfo{PID: 4242, Name: "dbus-daemon", Exe: "/tmp/dbus-daemon", Cmdline: "/tmp/dbus-daemon", UID: 1000}
+	if cat := c.Classify(spoof); cat != monitor.CategoryUser || spoof.Suspicious == "" {
+		t.Errorf("spoofed process classified %s, suspicious %q", cat, spoof.Suspicious)
+	}
+}
+
+func TestProcessSockets(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen: %v", err)
+	}
+	defer ln.Close()
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		if c, err := ln.Accept(); err == nil {
+			accepted <- c
+		}
+	}()
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	defer (<-accepted).Close()
+
+	port := ln.Addr().(*net.TCPAddr).Port
+	mon := monitor.NewProcessMonitor(time.Second, 100, nil)
+	var self *monitor.ProcessInfo
+	for _, p := range mon.Snapshot(10 * time.Millisecond) {
+		if p.PID == os.Getpid() {
+			self = p
+		}
+	}
+	if self == nil {
+		t.Fatal("own process not found in snapshot")
+	}
+
+	if !self.ListensOn([]int{port}) {
+		t.Errorf("listener on port %d not found in %v", port, self.Listeners)
+	}
+	if !self.ConnectedOn([]int{port}) {
+		t.Errorf("connection to port %d not found in %v", port, self.Connections)
+	}
+	// Both ends of the loopback connection belong to this process
+	if len(self.Connections) < 2 {
+		t.Errorf("expected 2 established connections, got %v", self.Connections)
+	}
+	if self.ListensOn([]int{port + 1}) {
+		t.Errorf("unexpected listener on port %d", port+1)
+	}
+}
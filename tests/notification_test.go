@@ -0,0 +1,188 @@
+package tests
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iamgilwell/aura/internal/ai"
+	"github.com/iamgilwell/aura/internal/monitor"
+	"github.com/iamgilwell/aura/internal/notification"
+	"github.com/iamgilwell/aura/internal/safety"
+)
+
+func TestAuditRoundTripAndSummary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	auditor, err := notification.NewAuditor(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	auditor.LogDecision(&ai.DecisionResponse{ProcessPID: 10, ProcessName: "node", Action: ai.ActionTerminate, Confidence: 0.9})
+	auditor.LogDecision(&ai.DecisionResponse{ProcessPID: 11, ProcessName: "vim", Action: ai.ActionKeep, Confidence: 0.5, FromCache: true})
+	id := auditor.LogTermination(&monitor.ProcessInfo{PID: 10, Name: "node"}, "idle dev server", 2.5)
+	auditor.LogTermination(&monitor.ProcessInfo{PID: 12, Name: "node"}, "idle dev server", 1.5)
+	auditor.LogContainerPause(&monitor.ProcessInfo{PID: 13, Name: "nginx", Container: "4f2a"}, notification.TerminationDetails{Scope: "container", Members: []int{14, 13}, ContainerAction: "pause"}, "container pause")
+	auditor.LogBlocked(1, "systemd", "protected process", "")
+	auditor.Close()
+
+	if id == "" {
+		t.Error("expected LogTermination to return an entry ID")
+	}
+
+	entries, err := notification.ReadAudit(path, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 6 {
+		t.Fatalf("expected 6 entries, got %d", len(entries))
+	}
+	if e := entries[4]; e.Event != "container_paused" || e.SavingsWatt != 0 || len(e.Members) != 2 {
+		t.Errorf("pause entry = %+v", e)
+	}
+
+	s := notification.Summarize(entries, time.Now().Add(-time.Hour), time.Now())
+	if s.Decisions != 2 || s.CachedDecisions != 1 {
+		t.Errorf("decisions=%d cached=%d", s.Decisions, s.CachedDecisions)
+	}
+	if s.Terminations != 2 || s.TotalSavedWatts != 4.0 {
+		t.Errorf("terminations=%d saved=%.1f", s.Terminations, s.TotalSavedWatts)
+	}
+	if len(s.TopTerminated) != 1 || s.TopTerminated[0].Name != "node" || s.TopTerminated[0].Count != 2 {
+		t.Errorf("unexpected top terminated: %+v", s.TopTerminated)
+	}
+	if s.Blocked != 1 {
+		t.Errorf("blocked=%d, want 1", s.Blocked)
+	}
+
+	out := s.String()
+	for _, want := range []string{"Terminations:   2", "Power saved:    4.00 W", "protected process"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("rendered summary missing %q:\n%s", want, out)
+		}
+	}
+
+	// Entries outside the window are dropped
+	entries, _ = notification.ReadAudit(path, time.Now().Add(time.Hour))
+	if len(entries) != 0 {
+		t.Errorf("expected no entries in the future window, got %d", len(entries))
+	}
+}
+
+func TestTrainingSamples(t *testing.T) {
+	features := &ai.ProcessFeatures{CPU: 1, Category: "User"}
+	entries := []notification.AuditEntry{
+		{Event: "ai_decision", Decision: &ai.DecisionResponse{Action: ai.ActionTerminate, Confidence: 0.9, Features: features}},
+		{Event: "ai_decision", Decision: &ai.DecisionResponse{Action: ai.ActionKeep, Confidence: 0.8, Features: features}},
+		{Event: "ai_decision", Decision: &ai.DecisionResponse{Action: ai.ActionKeep, Confidence: 0.8, Features: features, FromCache: true}},
+		{Event: "ai_decision", Decision: &ai.DecisionResponse{Action: ai.ActionKeep, Confidence: 0, Features: features}},
+		{Event: "ai_decision", Decision: &ai.DecisionResponse{Action: ai.ActionKeep, Confidence: 0.9}},
+		{Event: "termination", Reason: "manual termination", Features: features},
+		{Event: "termination", Reason: "AI said so", Features: features},
+	}
+
+	samples := notification.TrainingSamples(entries)
+	if len(samples) != 3 {
+		t.Fatalf("expected 3 samples, got %d", len(samples))
+	}
+	if samples[0].Label != 1 || samples[1].Label != 0 || samples[2].Label != 1 {
+		t.Errorf("unexpected labels: %v %v %v", samples[0].Label, samples[1].Label, samples[2].Label)
+	}
+}
+
+func TestTrainingSamplesUseOutcomes(t *testing.T) {
+	features := &ai.ProcessFeatures{CPU: 1, Category: "User"}
+	entries := []notification.AuditEntry{
+		{ID: "a", Event: "termination", Reason: "manual termination", Features: features},
+		{ID: "b", Event: "termination", Reason: "AI said so", Features: features},
+		{Event: "outcome", Ref: "a", Reason: "user-restarted"},
+		{Event: "outcome", Ref: "b", Reason: "stayed-dead"},
+	}
+
+	samples := notification.TrainingSamples(entries)
+	if len(samples) != 2 {
+		t.Fatalf("expected 2 samples, got %d", len(samples))
+	}
+	if samples[0].Label != 0 {
+		t.Error("a user-restarted termination should be labelled keep")
+	}
+	if samples[1].Label != 1 {
+		t.Error("a termination that stayed dead should be labelled terminate")
+	}
+}
+
+func TestAuditDryRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	auditor, err := notification.NewAuditor(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	auditor.SetDryRun(true)
+
+	proc := &monitor.ProcessInfo{PID: 10, Name: "node"}
+	auditor.LogTermination(proc, "idle dev server", 2.5)
+	auditor.LogAction("throttled", proc, "busy loop")
+	auditor.LogBlocked(1, "systemd", "protected process", "")
+	auditor.Close()
+
+	entries, err := notification.ReadAudit(path, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var events []string
+	for _, e := range entries {
+		events = append(events, e.Event)
+	}
+	if got := strings.Join(events, ","); got != "would_terminate,would_throttle,blocked" {
+		t.Errorf("events = %s", got)
+	}
+
+	s := notification.Summarize(entries, time.Now().Add(-time.Hour), time.Now())
+	if s.Terminations != 0 || s.TotalSavedWatts != 0 || s.WouldTerminate != 1 || s.WouldSaveWatts != 2.5 {
+		t.Errorf("dry-run terminations must be counted apart: %+v", s)
+	}
+	if out := s.String(); !strings.Contains(out, "1 would terminate, 2.50 W would be saved") {
+		t.Errorf("rendered summary missing dry run:\n%s", out)
+	}
+}
+
+func TestParkForApproval(t *testing.T) {
+	dir := t.TempDir()
+	auditor, err := notification.NewAuditor(filepath.Join(dir, "audit.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	queue := safety.NewPendingQueue(filepath.Join(dir, "pending.json"), time.Hour)
+	proc := &monitor.ProcessInfo{PID: 10, Name: "node", StartTime: time.Now()}
+	decision := &ai.DecisionResponse{Reason: "idle dev server", Confidence: 0.9}
+
+	first, added, err := notification.ParkForApproval(queue, auditor, proc, safety.ActionTerminate, decision, "yolo")
+	if err != nil || !added || first.Source != "yolo" || first.Confidence != 0.9 {
+		t.Fatalf("ParkForApproval = %+v, %v, %v", first, added, err)
+	}
+	again, added, err := notification.ParkForApproval(queue, auditor, proc, safety.ActionTerminate, decision, "interactive")
+	if err != nil || added || again.ID != first.ID {
+		t.Errorf("parking the same action again = %+v, %v, %v; want the waiting item", again, added, err)
+	}
+
+	// Items of a queue with a zero TTL expire at once
+	expiring := safety.NewPendingQueue(filepath.Join(dir, "expiring.json"), 0)
+	stale, _, _ := notification.ParkForApproval(expiring, auditor, proc, safety.ActionThrottle, decision, "yolo")
+	if expired := notification.ExpirePending(expiring, auditor); len(expired) != 1 || expired[0].ID != stale.ID {
+		t.Errorf("ExpirePending = %+v", expired)
+	}
+	auditor.Close()
+
+	entries, err := notification.ReadAudit(filepath.Join(dir, "audit.log"), time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var events []string
+	for _, e := range entries {
+		events = append(events, e.Event)
+	}
+	if got := strings.Join(events, ","); got != "approval_requested,approval_requested,approval_expired" {
+		t.Errorf("audited %s, want one request per queue and the expiry", got)
+	}
+}
@@ -56,6 +56,21 @@ func TestPowerMetrics(t *testing.T) {
 	}
 }
 
+func TestPowerMetricsDryRun(t *testing.T) {
+	m := power.NewMetrics()
+	m.RecordSaving("node", 10, 2.0, "real")
+	m.SetDryRun(true)
+	m.RecordSaving("node", 11, 3.0, "simulated")
+
+	if m.TotalSaved() != 2.0 || m.WouldSave() != 3.0 {
+		t.Errorf("saved=%.1f would=%.1f, want 2.0 and 3.0", m.TotalSaved(), m.WouldSave())
+	}
+	recent := m.RecentSavings(2)
+	if recent[0].DryRun || !recent[1].DryRun {
+		t.Errorf("entries should be marked by mode: %+v", recent)
+	}
+}
+
 func TestMonthlykWh(t *testing.T) {
 	// 1 watt for a month = 1 * 24 * 30 / 1000 = 0.72 kWh
 	kwh := power.MonthlykWh(1.0)
@@ -0,0 +1,1204 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/iamgilwell/aura/internal/monitor"
+	"github.com/iamgilwell/aura/internal/process"
+	"github.com/iamgilwell/aura/internal/safety"
+)
+
+func TestOutcomeTracker(t *testing.T) {
+	tracker := process.NewOutcomeTracker(time.Minute, time.Hour)
+
+	worker := &monitor.ProcessInfo{PID: 100, Name: "worker", Exe: "/usr/bin/worker", Cmdline: "/usr/bin/worker --serve", StartTime: time.Now().Add(-time.Hour)}
+	editor := &monitor.ProcessInfo{PID: 200, Name: "code", Exe: "/usr/bin/code", Cmdline: "/usr/bin/code .", StartTime: time.Now().Add(-time.Hour)}
+	tracker.Track("t1", worker)
+	tracker.Track("t2", editor)
+
+	// Nothing has come back yet
+	if events := tracker.Observe(nil); len(events) != 0 {
+		t.Fatalf("expected no outcomes yet, got %v", events)
+	}
+
+	shell := &monitor.ProcessInfo{PID: 300, Name: "bash", PPid: 250}
+	respawned := &monitor.ProcessInfo{PID: 101, PPid: 1, Name: "worker", Exe: "/usr/bin/worker", Cmdline: "/usr/bin/worker --serve", StartTime: time.Now()}
+	restarted := &monitor.ProcessInfo{PID: 201, PPid: 300, Name: "code", Exe: "/usr/bin/code", Cmdline: "/usr/bin/code .", StartTime: time.Now()}
+
+	events := tracker.Observe([]*monitor.ProcessInfo{shell, respawned, restarted})
+	if len(events) != 2 {
+		t.Fatalf("expected 2 outcomes, got %d", len(events))
+	}
+	got := map[string]process.Outcome{}
+	for _, ev := range events {
+		got[ev.TerminationID] = ev.Outcome
+	}
+	if got["t1"] != process.OutcomeRespawned {
+		t.Errorf("t1 outcome = %s, want respawned", got["t1"])
+	}
+	if got["t2"] != process.OutcomeUserRestarted {
+		t.Errorf("t2 outcome = %s, want user-restarted", got["t2"])
+	}
+
+	if ok, _ := tracker.Suppressed(respawned); !ok {
+		t.Error("respawned process should be suppressed")
+	}
+	if ok, _ := tracker.Suppressed(shell); ok {
+		t.Error("unrelated process should not be suppressed")
+	}
+	if tracker.Pending() != 0 {
+		t.Errorf("expected no pending terminations, got %d", tracker.Pending())
+	}
+}
+
+func TestOutcomeTrackerStayedDead(t *testing.T) {
+	tracker := process.NewOutcomeTracker(10*time.Millisecond, time.Hour)
+	tracker.Track("t1", &monitor.ProcessInfo{PID: 100, Name: "leak", Cmdline: "leak"})
+
+	time.Sleep(20 * time.Millisecond)
+	events := tracker.Observe(nil)
+	if len(events) != 1 || events[0].Outcome != process.OutcomeStayedDead {
+		t.Fatalf("expected stayed-dead outcome, got %v", events)
+	}
+}
+
+func TestTreeProtection(t *testing.T) {
+	self, parent := os.Getpid(), os.Getppid()
+	procs := []*monitor.ProcessInfo{
+		{PID: parent, PPid: 1, Name: "bash", Session: 500, TTY: 34816},
+		{PID: self, PPid: parent, Name: "aura", Session: 500, TTY: 34816},
+		{PID: 900, PPid: 1, Name: "sshd", Session: 900},
+		{PID: 901, PPid: 900, Name: "bash", Session: 901},
+		{PID: 902, PPid: 901, Name: "vim", Session: 901},
+		{PID: 950, PPid: 1, Name: "make", Session: 500},
+		{PID: 951, PPid: 1, Name: "watch", Session: 777, TTY: 34816},
+		{PID: 960, PPid: 1, Name: "firefox", Session: 960},
+	}
+
+	safetyMgr := safety.NewManager([]string{"sshd"}, nil, safety.ConsentAutomatic)
+	mgr := process.NewManager(safetyMgr, time.Second)
+	mgr.ProtectTrees([]string{"sshd"}, true)
+	mgr.UpdateProtection(procs)
+
+	want := map[int]bool{parent: false, self: false, 900: false, 901: false, 902: false, 950: false, 951: false, 960: true}
+	for _, p := range procs {
+		v := safetyMgr.Evaluate(p)
+		if v.Allowed != want[p.PID] {
+			t.Errorf("PID %d (%s): allowed=%v, want %v (%s)", p.PID, p.Name, v.Allowed, want[p.PID], v.Reason)
+		}
+	}
+
+	// No policy rule reaches into a protected subtree or Aura's session
+	policy, err := safety.NewPolicy([]safety.Rule{{Name: "anything", Effect: safety.EffectAllow, Match: safety.Match{Names: []string{"vim", "make"}}}})
+	if err != nil {
+		t.Fatalf("NewPolicy: %v", err)
+	}
+	safetyMgr.SetPolicy(policy)
+	if safetyMgr.Evaluate(procs[4]).Allowed || safetyMgr.Evaluate(procs[5]).Allowed {
+		t.Error("an allow rule should not override descendant or session protection")
+	}
+	safetyMgr.SetPolicy(nil)
+
+	// Without tree protection only Aura's own chain stays protected
+	mgr.ProtectTrees(nil, false)
+	mgr.UpdateProtection(procs)
+	if !safetyMgr.Evaluate(procs[4]).Allowed || !safetyMgr.Evaluate(procs[5]).Allowed {
+		t.Error("descendant and session protection should be disabled")
+	}
+	if safetyMgr.Evaluate(procs[0]).Allowed {
+		t.Error("Aura's parent should always be protected")
+	}
+}
+
+func TestRedactEnv(t *testing.T) {
+	env := process.RedactEnv([]string{"HOME=/home/dev", "GITHUB_TOKEN=ghp_x", "AWS_SECRET_ACCESS_KEY=abc", "DB_PASSWORD=hunter2", "PATH=/usr/bin"})
+	want := []string{"HOME=/home/dev", "GITHUB_TOKEN=" + process.Redacted, "AWS_SECRET_ACCESS_KEY=" + process.Redacted, "DB_PASSWORD=" + process.Redacted, "PATH=/usr/bin"}
+	for i := range want {
+		if env[i] != want[i] {
+			t.Errorf("env[%d] = %q, want %q", i, env[i], want[i])
+		}
+	}
+}
+
+func TestRestoreUndo(t *testing.T) {
+	dir := t.TempDir()
+	sleeper := exec.Command("sleep", "30")
+	sleeper.Dir = dir
+	if err := sleeper.Start(); err != nil {
+		t.Skipf("cannot start sleep: %v", err)
+	}
+	go sleeper.Wait()
+
+	// Start returns once exec has begun; the new command line appears
+	// shortly after
+	cmdline := fmt.Sprintf("/proc/%d/cmdline", sleeper.Process.Pid)
+	for i := 0; i < 100; i++ {
+		if data, _ := os.ReadFile(cmdline); len(data) > 0 {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	proc := &monitor.ProcessInfo{PID: sleeper.Process.Pid, Name: "sleep", UID: os.Geteuid(), Category: monitor.CategoryUser}
+	rec, err := process.CaptureRestore(proc)
+	if err != nil {
+		t.Fatalf("CaptureRestore: %v", err)
+	}
+	if rec.Command() != "sleep 30" || rec.Cwd != dir {
+		t.Errorf("captured argv %q cwd %q", rec.Command(), rec.Cwd)
+	}
+	if _, ok := rec.Limits["Max open files"]; !ok {
+		t.Errorf("limits not captured: %v", rec.Limits)
+	}
+
+	store := process.NewRestoreStore(filepath.Join(dir, "restore"), time.Hour)
+	mgr := process.NewManager(safety.NewManager(nil, nil, safety.ConsentAutomatic), time.Second)
+	mgr.SetRestoreStore(store)
+
+	if _, err := mgr.SafeTerminate(proc, true); err != nil {
+		t.Fatalf("SafeTerminate: %v", err)
+	}
+	if err := mgr.SaveRestore("t1", proc); err != nil {
+		t.Fatalf("SaveRestore: %v", err)
+	}
+	if recs, _ := store.List(); len(recs) != 1 || recs[0].ID != "t1" {
+		t.Fatalf("expected one record t1, got %v", recs)
+	}
+	if _, err := store.Load("../t1"); err == nil {
+		t.Error("IDs escaping the store must be rejected")
+	}
+
+	rec, pid, err := mgr.Undo("t1")
+	if err != nil {
+		t.Fatalf("Undo: %v", err)
+	}
+	defer syscall.Kill(pid, syscall.SIGKILL)
+	if rec.Name != "sleep" || pid <= 0 {
+		t.Errorf("unexpected relaunch of %s as %d", rec.Name, pid)
+	}
+	cwd, _ := os.Readlink(filepath.Join("/proc", strconv.Itoa(pid), "cwd"))
+	if cwd != dir {
+		t.Errorf("relaunched in %q, want %q", cwd, dir)
+	}
+	if _, err := store.Load("t1"); err == nil {
+		t.Error("record should be removed after undo")
+	}
+}
+
+func TestTerminationHooks(t *testing.T) {
+	dir := t.TempDir()
+	var posted map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&posted)
+	}))
+	defer srv.Close()
+
+	marker := filepath.Join(dir, "post")
+	hooks := []process.Hook{
+		{Name: "refuse", Stage: process.HookPre, Exec: []string{"sh", "-c", "echo busy; exit 3"}, Veto: true},
+		{Name: "slow", Stage: process.HookPre, Exec: []string{"sleep", "5"}, Timeout: 100 * time.Millisecond, Veto: true},
+		{Name: "notify", Stage: process.HookPre, URL: srv.URL},
+		{Name: "stop-itself", Stage: process.HookPre, Exec: []string{"kill", "-9", "{pid}"}},
+		{Name: "record", Stage: process.HookPost, Exec: []string{"sh", "-c", "echo $AURA_NAME > " + marker}},
+	}
+	for _, h := range hooks {
+		if err := h.Validate(); err != nil {
+			t.Fatalf("Validate(%s): %v", h.Name, err)
+		}
+	}
+	if err := (process.Hook{Name: "bad", Stage: process.HookPost, URL: srv.URL, Veto: true}).Validate(); err == nil {
+		t.Error("post hooks must not veto")
+	}
+
+	newManager := func(hookNames ...string) (*process.Manager, *[]process.HookResult) {
+		policy, err := safety.NewPolicy([]safety.Rule{
+			{Name: "sleepers", Effect: safety.EffectAllow, Hooks: hookNames, Match: safety.Match{Names: []string{"sleep"}}},
+		})
+		if err != nil {
+			t.Fatalf("NewPolicy: %v", err)
+		}
+		safetyMgr := safety.NewManager(nil, nil, safety.ConsentAutomatic)
+		safetyMgr.SetPolicy(policy)
+		mgr := process.NewManager(safetyMgr, time.Second)
+		mgr.SetHooks(hooks)
+		var results []process.HookResult
+		mgr.OnHookResult(func(r process.HookResult) { results = append(results, r) })
+		return mgr, &results
+	}
+
+	// A veto hook answering non-zero blocks the termination
+	proc := startSleeper(t, "sleep")
+	mgr, results := newManager("refuse", "record")
+	if _, err := mgr.SafeTerminate(proc, true); err == nil || !strings.Contains(err.Error(), "busy") {
+		t.Fatalf("expected veto, got %v", err)
+	}
+	if syscall.Kill(proc.PID, 0) != nil {
+		t.Fatal("vetoed process should still run")
+	}
+	if len(*results) != 1 || !(*results)[0].Vetoed || (*results)[0].Outcome() != "vetoed" {
+		t.Errorf("unexpected results %+v", *results)
+	}
+
+	// Nor is the vetoed process captured for undo
+	mgr.SetRestoreStore(process.NewRestoreStore(filepath.Join(dir, "restore"), time.Hour))
+	if _, err := mgr.SafeTerminate(proc, true); err == nil {
+		t.Fatal("expected veto")
+	}
+	if err := mgr.SaveRestore("vetoed", proc); err != nil {
+		t.Fatalf("SaveRestore: %v", err)
+	}
+	if recs, _ := mgr.Restores().List(); len(recs) != 0 {
+		t.Errorf("a vetoed termination left %d restore records", len(recs))
+	}
+
+	// Timeouts do not veto; HTTP hooks get the process as JSON; a pre hook
+	// may stop the process itself; post hooks run afterwards
+	mgr, results = newManager("slow", "notify", "stop-itself", "record")
+	if _, err := mgr.SafeTerminate(proc, false); err != nil {
+		t.Fatalf("SafeTerminate: %v", err)
+	}
+	if len(*results) != 4 {
+		t.Fatalf("expected 4 hook results, got %+v", *results)
+	}
+	if r := (*results)[0]; r.Vetoed || r.Err == nil {
+		t.Errorf("timed out hook should fail without veto: %+v", r)
+	}
+	if posted["pid"] != strconv.Itoa(proc.PID) || posted["stage"] != "pre" {
+		t.Errorf("unexpected HTTP body %v", posted)
+	}
+	if data, _ := os.ReadFile(marker); strings.TrimSpace(string(data)) != "sleep" {
+		t.Errorf("post hook output %q", data)
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	dir := t.TempDir()
+	proc := startSleeper(t, "sleep")
+
+	marker := filepath.Join(dir, "hook")
+	policy, err := safety.NewPolicy([]safety.Rule{
+		{Name: "sleepers", Effect: safety.EffectAllow, Hooks: []string{"touch"}, Match: safety.Match{Names: []string{"sleep"}},
+			Limits: safety.Limits{OpenFiles: 64}},
+		{Name: "keep-db", Effect: safety.EffectDeny, Match: safety.Match{Names: []string{"postgres"}}},
+	})
+	if err != nil {
+		t.Fatalf("NewPolicy: %v", err)
+	}
+	safetyMgr := safety.NewManager(nil, nil, safety.ConsentAutomatic)
+	safetyMgr.SetPolicy(policy)
+	mgr := process.NewManager(safetyMgr, time.Second)
+	mgr.SetHooks([]process.Hook{{Name: "touch", Stage: process.HookPre, Exec: []string{"touch", marker}}})
+	mgr.SetRestoreStore(process.NewRestoreStore(filepath.Join(dir, "restore"), time.Hour))
+	mgr.SetDryRun(true)
+
+	for _, action := range safety.Actions {
+		if err := mgr.SafeApply(proc, action); err != nil {
+			t.Fatalf("dry-run %s: %v", action, err)
+		}
+	}
+	if _, err := mgr.SafeTerminate(proc, true); err != nil {
+		t.Fatalf("dry-run SafeTerminate: %v", err)
+	}
+	if err := mgr.SaveRestore("t1", proc); err != nil {
+		t.Fatalf("SaveRestore: %v", err)
+	}
+
+	if syscall.Kill(proc.PID, 0) != nil {
+		t.Fatal("dry run must not terminate the process")
+	}
+	stat, _ := os.ReadFile(fmt.Sprintf("/proc/%d/stat", proc.PID))
+	if fields := strings.Fields(string(stat)); len(fields) > 18 && (fields[2] == "T" || fields[18] != "0") {
+		t.Errorf("dry run must not suspend or renice: state %s nice %s", fields[2], fields[18])
+	}
+	if soft, _ := procLimit(t, proc.PID, "Max open files"); soft == "64" || mgr.Limited(proc) != nil {
+		t.Error("dry run must not set limits")
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Error("dry run must not run hooks")
+	}
+	if recs, _ := mgr.Restores().List(); len(recs) != 0 {
+		t.Errorf("dry run must not keep restore records, got %v", recs)
+	}
+
+	// The safety pipeline still runs
+	db := &monitor.ProcessInfo{PID: proc.PID, Name: "postgres", Category: monitor.CategoryUser}
+	if _, err := mgr.SafeTerminate(db, false); err == nil || !strings.Contains(err.Error(), "keep-db") {
+		t.Errorf("dry run should still refuse protected processes, got %v", err)
+	}
+	if err := mgr.Terminate(1<<22+1, false); err == nil {
+		t.Error("dry run should report processes that do not exist")
+	}
+}
+
+func TestTerminationScopes(t *testing.T) {
+	self := os.Getpid()
+	procs := []*monitor.ProcessInfo{
+		{PID: 700, PPid: 1, Pgrp: 700, Session: 700, Name: "tmux", Cgroup: "/user.slice/dev.scope"},
+		{PID: 701, PPid: 700, Pgrp: 701, Session: 700, Name: "make", Cgroup: "/user.slice/dev.scope"},
+		{PID: 702, PPid: 701, Pgrp: 701, Session: 700, Name: "cc", Cgroup: "/user.slice/dev.scope/build"},
+		{PID: 703, PPid: 702, Pgrp: 701, Session: 700, Name: "as", Cgroup: "/user.slice/dev.scope/build"},
+		{PID: 710, PPid: 1, Pgrp: 710, Session: 710, Name: "node", Cgroup: "/user.slice/dev.scope-other"},
+		{PID: self, PPid: 1, Pgrp: self, Session: self, Name: "aura", Cgroup: "/"},
+	}
+	for _, p := range procs {
+		p.Category = monitor.CategoryUser
+	}
+	safetyMgr := safety.NewManager([]string{"node"}, nil, safety.ConsentAutomatic)
+	mgr := process.NewManager(safetyMgr, time.Second)
+	mgr.UpdateProtection(procs)
+
+	pids := func(members []*monitor.ProcessInfo) string {
+		return fmt.Sprint((&process.TerminationResult{Members: members}).PIDs())
+	}
+	for _, tc := range []struct {
+		target int
+		scope  safety.Scope
+		want   string
+	}{
+		{1, safety.ScopeProcess, "[701]"},
+		{1, safety.ScopeTree, "[703 702 701]"},
+		{2, safety.ScopeGroup, "[703 701 702]"},
+		{0, safety.ScopeSession, "[702 703 701 700]"},
+		{1, safety.ScopeCgroup, "[702 703 700 701]"},
+	} {
+		members, err := mgr.Members(procs[tc.target], tc.scope)
+		if err != nil || pids(members) != tc.want {
+			t.Errorf("Members(%d, %s) = %s, %v; want %s", procs[tc.target].PID, tc.scope, pids(members), err, tc.want)
+		}
+	}
+	if _, err := mgr.Members(procs[5], safety.ScopeCgroup); err == nil {
+		t.Error("the root cgroup should never be a termination scope")
+	}
+
+	// One protected member blocks the whole operation
+	mgr.SetScope(safety.ScopeSession)
+	procs[1].Name = "node"
+	if _, err := mgr.SafeTerminate(procs[0], false); err == nil || !strings.Contains(err.Error(), "PID 701") {
+		t.Errorf("SafeTerminate with a protected member = %v, want it blocked", err)
+	}
+	procs[1].Name = "make"
+
+	// So does one needing confirmation, though the target alone does not
+	confirm, err := safety.NewPolicy([]safety.Rule{{Name: "ask", Effect: safety.EffectConfirm, Match: safety.Match{Names: []string{"make"}}}})
+	if err != nil {
+		t.Fatalf("NewPolicy: %v", err)
+	}
+	safetyMgr.SetPolicy(confirm)
+	if v := safetyMgr.Evaluate(procs[0]); v.Confirm {
+		t.Fatal("tmux alone should not need confirmation")
+	}
+	if v := mgr.TerminationVerdict(procs[0]); !v.Allowed || !v.Confirm || !strings.Contains(v.Reason, "PID 701") {
+		t.Errorf("TerminationVerdict with a member needing confirmation = %+v, want Confirm", v)
+	}
+	procs[1].Name = "node"
+	if v := mgr.TerminationVerdict(procs[0]); v.Allowed || !strings.Contains(v.Reason, "PID 701") {
+		t.Errorf("TerminationVerdict with a protected member = %+v, want it blocked", v)
+	}
+	procs[1].Name = "make"
+
+	// A policy rule picks the scope: the shell and its background jobs go
+	// down together
+	cmd := exec.Command("sh", "-c", "sleep 30 & sleep 30 & wait")
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := cmd.Start(); err != nil {
+		t.Skipf("cannot start sh: %v", err)
+	}
+	go cmd.Wait()
+	defer syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+
+	mon := monitor.NewProcessMonitor(time.Second, 10, nil)
+	var group []*monitor.ProcessInfo
+	var shell *monitor.ProcessInfo
+	for i := 0; i < 50 && len(group) < 3; i++ {
+		time.Sleep(20 * time.Millisecond)
+		group, shell = nil, nil
+		live := mon.Snapshot(0)
+		for _, p := range live {
+			if p.Pgrp == cmd.Process.Pid {
+				group = append(group, p)
+			}
+			if p.PID == cmd.Process.Pid {
+				shell = p
+			}
+		}
+		mgr.UpdateProtection(live)
+	}
+	if len(group) < 3 || shell == nil {
+		t.Fatalf("expected the shell and two sleeps in process group %d, got %d processes", cmd.Process.Pid, len(group))
+	}
+
+	policy, err := safety.NewPolicy([]safety.Rule{{Name: "jobs", Effect: safety.EffectAllow, Scope: safety.ScopeGroup, Match: safety.Match{Names: []string{"sh"}}}})
+	if err != nil {
+		t.Fatalf("NewPolicy: %v", err)
+	}
+	safetyMgr.SetPolicy(policy)
+	mgr.SetScope(safety.ScopeProcess)
+
+	result, err := mgr.SafeTerminate(shell, false)
+	if err != nil {
+		t.Fatalf("SafeTerminate: %v", err)
+	}
+	if result.Scope != safety.ScopeGroup || len(result.Members) != len(group) || result.Target().PID != shell.PID {
+		t.Errorf("result = %s %v, want group of %d ending in %d", result.Scope, result.PIDs(), len(group), shell.PID)
+	}
+	for _, p := range group {
+		stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", p.PID))
+		if fields := strings.Fields(string(stat)); err == nil && len(fields) > 2 && fields[2] != "Z" {
+			t.Errorf("%s (PID %d) survived the group termination", p.Name, p.PID)
+		}
+	}
+
+	// A process joining the group after the scan was never validated, so
+	// only the members are signalled
+	cmd = exec.Command("sh", "-c", "sleep 30 & sleep 30 & wait")
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("cannot start sh: %v", err)
+	}
+	go cmd.Wait()
+	defer syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+
+	var live []*monitor.ProcessInfo
+	for i := 0; i < 50; i++ {
+		time.Sleep(20 * time.Millisecond)
+		live, group, shell = mon.Snapshot(0), nil, nil
+		for _, p := range live {
+			if p.Pgrp == cmd.Process.Pid {
+				group = append(group, p)
+			}
+			if p.PID == cmd.Process.Pid {
+				shell = p
+			}
+		}
+		if len(group) >= 3 && shell != nil {
+			break
+		}
+	}
+	if len(group) < 3 || shell == nil {
+		t.Fatalf("expected the shell and two sleeps in process group %d, got %d processes", cmd.Process.Pid, len(group))
+	}
+	joined := group[0]
+	if joined == shell {
+		joined = group[1]
+	}
+	var seen []*monitor.ProcessInfo
+	for _, p := range live {
+		if p != joined {
+			seen = append(seen, p)
+		}
+	}
+	mgr.UpdateProtection(seen)
+
+	if _, err := mgr.SafeTerminate(shell, false); err != nil {
+		t.Fatalf("SafeTerminate: %v", err)
+	}
+	stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", joined.PID))
+	if fields := strings.Fields(string(stat)); err != nil || len(fields) < 3 || fields[2] == "Z" {
+		t.Errorf("%s (PID %d) joined the group unvalidated but was signalled", joined.Name, joined.PID)
+	}
+
+	if _, err := safety.NewPolicy([]safety.Rule{{Name: "bad", Effect: safety.EffectAllow, Scope: "universe"}}); err == nil {
+		t.Error("an unknown scope should be rejected")
+	}
+}
+
+func TestEscalationLadder(t *testing.T) {
+	for _, tc := range []struct {
+		in, want string
+		kills    bool
+	}{
+		{"SIGINT 3s SIGTERM 10s SIGKILL", "SIGINT 3s SIGTERM 10s SIGKILL", true},
+		{"int, 3s, term, 10s, kill", "SIGINT 3s SIGTERM 10s SIGKILL", true},
+		{"TERM 30s", "SIGTERM 30s", false},
+		{"15 9", "SIGTERM SIGKILL", true},
+	} {
+		esc, err := safety.ParseEscalation(tc.in)
+		if err != nil || esc.String() != tc.want || esc.Kills() != tc.kills {
+			t.Errorf("ParseEscalation(%q) = %q kills=%v, %v; want %q kills=%v", tc.in, esc, esc.Kills(), err, tc.want, tc.kills)
+		}
+	}
+	for _, bad := range []string{"3s SIGTERM", "SIGTERM 1s 2s", "SIGSTOP", "SIGTERM -1s", "19"} {
+		if _, err := safety.ParseEscalation(bad); err == nil {
+			t.Errorf("ParseEscalation(%q) should fail", bad)
+		}
+	}
+	if _, err := safety.NewPolicy([]safety.Rule{{Name: "bad", Effect: safety.EffectAllow, Escalation: "SIGTERM 2s 2s"}}); err == nil {
+		t.Error("an invalid escalation should be rejected")
+	}
+
+	safetyMgr := safety.NewManager(nil, nil, safety.ConsentAutomatic)
+	mgr := process.NewManager(safetyMgr, time.Second)
+
+	// A ladder without SIGKILL leaves the survivor running
+	proc, wait := startStubborn(t)
+	defer wait()
+	policy, err := safety.NewPolicy([]safety.Rule{{Name: "gentle", Effect: safety.EffectAllow, Escalation: "SIGINT 100ms SIGTERM 200ms", Match: safety.Match{Names: []string{"sleep"}}}})
+	if err != nil {
+		t.Fatalf("NewPolicy: %v", err)
+	}
+	safetyMgr.SetPolicy(policy)
+	if _, err := mgr.SafeTerminate(proc, false); err == nil || !strings.Contains(err.Error(), "still running after SIGINT 100ms SIGTERM 200ms") {
+		t.Errorf("SafeTerminate without SIGKILL = %v, want the process left running", err)
+	}
+	if err := syscall.Kill(proc.PID, 0); err != nil {
+		t.Fatalf("process gone after a ladder without SIGKILL: %v", err)
+	}
+
+	// The manager's ladder applies without a rule, and reports the signal
+	// that worked and the zombie left behind
+	safetyMgr.SetPolicy(nil)
+	esc, _ := safety.ParseEscalation("SIGINT 100ms SIGKILL")
+	mgr.SetEscalation(esc)
+	result, err := mgr.SafeTerminate(proc, false)
+	if err != nil {
+		t.Fatalf("SafeTerminate: %v", err)
+	}
+	if result.Signal != syscall.SIGKILL || fmt.Sprint(result.Sent) != fmt.Sprint([]syscall.Signal{syscall.SIGINT, syscall.SIGKILL}) {
+		t.Errorf("exit = %s, sent %v; want SIGKILL after SIGINT", result.Exit, result.Sent)
+	}
+	if !result.Zombie || result.Elapsed < 100*time.Millisecond || result.Elapsed > time.Second {
+		t.Errorf("exit = %s (zombie=%v, elapsed %s), want an unreaped exit after about 100ms", result.Exit, result.Zombie, result.Elapsed)
+	}
+
+	// A process that honours the first signal never sees the others
+	esc, _ = safety.ParseEscalation("SIGINT 2s SIGKILL")
+	mgr.SetEscalation(esc)
+	result, err = mgr.SafeTerminate(startSleeper(t, "sleep"), false)
+	if err != nil {
+		t.Fatalf("SafeTerminate: %v", err)
+	}
+	if result.Signal != syscall.SIGINT || len(result.Sent) != 1 || result.Elapsed > time.Second {
+		t.Errorf("exit = %s, sent %v; want an exit after SIGINT alone", result.Exit, result.Sent)
+	}
+}
+
+// startSleeper runs "sleep 30", killed when the test ends, and describes it
+// as a user process called name.
+func startSleeper(t *testing.T, name string) *monitor.ProcessInfo {
+	t.Helper()
+	cmd := exec.Command("sleep", "30")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("cannot start sleep: %v", err)
+	}
+	go cmd.Wait()
+	t.Cleanup(func() { cmd.Process.Kill() })
+	return &monitor.ProcessInfo{PID: cmd.Process.Pid, Name: name, UID: os.Geteuid(), Category: monitor.CategoryUser}
+}
+
+// startStubborn runs a process that ignores SIGINT and SIGTERM. It is not
+// reaped until the returned function is called, so it turns into a zombie
+// when killed.
+func startStubborn(t *testing.T) (*monitor.ProcessInfo, func()) {
+	t.Helper()
+	cmd := exec.Command("sh", "-c", `trap "" INT TERM; exec sleep 30`)
+	if err := cmd.Start(); err != nil {
+		t.Skipf("cannot start sh: %v", err)
+	}
+	for i := 0; i < 100; i++ {
+		if data, _ := os.ReadFile(fmt.Sprintf("/proc/%d/comm", cmd.Process.Pid)); strings.TrimSpace(string(data)) == "sleep" {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	proc := &monitor.ProcessInfo{PID: cmd.Process.Pid, Name: "sleep", UID: os.Geteuid(), Category: monitor.CategoryUser}
+	return proc, func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}
+}
+
+func TestAsyncTermination(t *testing.T) {
+	mgr := process.NewManager(safety.NewManager(nil, nil, safety.ConsentAutomatic), time.Second)
+	esc, _ := safety.ParseEscalation("SIGTERM 10s SIGKILL")
+	mgr.SetEscalation(esc)
+
+	// StartTerminate returns at once and the handle follows the escalation
+	proc, wait := startStubborn(t)
+	defer wait()
+	begin := time.Now()
+	term, err := mgr.StartTerminate(context.Background(), proc, false)
+	if err != nil {
+		t.Fatalf("StartTerminate: %v", err)
+	}
+	if time.Since(begin) > 500*time.Millisecond {
+		t.Errorf("StartTerminate blocked for %s", time.Since(begin))
+	}
+	if mgr.Terminating(proc.PID) != term || len(mgr.Terminations()) != 1 {
+		t.Errorf("termination of PID %d not in flight", proc.PID)
+	}
+	if _, err := mgr.StartTerminate(context.Background(), proc, false); err == nil || !strings.Contains(err.Error(), "already being terminated") {
+		t.Errorf("second StartTerminate = %v, want it refused", err)
+	}
+	var stages []process.Stage
+	for ev := range term.Events() {
+		stages = append(stages, ev.Stage)
+		if ev.Stage == process.StageSignalled {
+			if ev.Signal != syscall.SIGTERM {
+				t.Errorf("signalled %s, want SIGTERM", ev.Signal)
+			}
+			term.Cancel()
+		}
+	}
+	if fmt.Sprint(stages) != "[started signalled done]" {
+		t.Errorf("stages = %v", stages)
+	}
+	if _, err := term.Wait(); !errors.Is(err, context.Canceled) || !strings.Contains(err.Error(), "cancelled after SIGTERM") {
+		t.Errorf("Wait after Cancel = %v, want a cancellation after SIGTERM", err)
+	}
+	if err := syscall.Kill(proc.PID, 0); err != nil {
+		t.Errorf("cancelled termination still killed the process: %v", err)
+	}
+	if len(mgr.Terminations()) != 0 {
+		t.Errorf("%d terminations still in flight", len(mgr.Terminations()))
+	}
+
+	// Terminations of different processes proceed concurrently
+	esc, _ = safety.ParseEscalation("SIGTERM 300ms SIGKILL")
+	mgr.SetEscalation(esc)
+	other, waitOther := startStubborn(t)
+	defer waitOther()
+	begin = time.Now()
+	var terms []*process.Termination
+	for _, p := range []*monitor.ProcessInfo{proc, other} {
+		term, err := mgr.StartTerminate(context.Background(), p, false)
+		if err != nil {
+			t.Fatalf("StartTerminate(%d): %v", p.PID, err)
+		}
+		terms = append(terms, term)
+	}
+	for _, term := range terms {
+		if result, err := term.Wait(); err != nil || result.Signal != syscall.SIGKILL {
+			t.Errorf("Wait(%d) = %v, %v; want an exit after SIGKILL", term.Proc.PID, result, err)
+		}
+	}
+	if d := time.Since(begin); d > 550*time.Millisecond {
+		t.Errorf("two 300ms escalations took %s, want them to run concurrently", d)
+	}
+}
+
+// fakeSystemctl answers systemctl for tests, acting on one real process:
+// stop kills it, kill sends it the requested signal.
+type fakeSystemctl struct {
+	mu      sync.Mutex
+	calls   []string
+	restart string
+	pid     int
+}
+
+func (f *fakeSystemctl) Run(_ context.Context, name string, args ...string) ([]byte, error) {
+	f.mu.Lock()
+	f.calls = append(f.calls, name+" "+strings.Join(args, " "))
+	f.mu.Unlock()
+	switch {
+	case slices.Contains(args, "show"):
+		return []byte(f.restart + "\n"), nil
+	case slices.Contains(args, "stop"):
+		return nil, syscall.Kill(f.pid, syscall.SIGKILL)
+	case slices.Contains(args, "kill"):
+		for _, a := range args {
+			if s, ok := strings.CutPrefix(a, "--signal="); ok {
+				sig, _ := safety.ParseEscalation(s)
+				return nil, syscall.Kill(f.pid, sig[0].Signal)
+			}
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeSystemctl) called(cmd string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Contains(f.calls, cmd)
+}
+
+func TestSystemdUnits(t *testing.T) {
+	for _, tc := range []struct {
+		restart string
+		sig     syscall.Signal
+		want    bool
+	}{
+		{"always", syscall.SIGKILL, true},
+		{"on-success", syscall.SIGTERM, true},
+		{"on-success", syscall.SIGKILL, false},
+		{"on-failure", syscall.SIGTERM, false},
+		{"on-failure", syscall.SIGKILL, true},
+		{"on-abort", syscall.SIGQUIT, true},
+		{"no", syscall.SIGKILL, false},
+	} {
+		if got := process.RestartsAfter(tc.restart, tc.sig); got != tc.want {
+			t.Errorf("RestartsAfter(%s, %s) = %v, want %v", tc.restart, safety.SignalName(tc.sig), got, tc.want)
+		}
+	}
+
+	web := &monitor.ProcessInfo{PID: 800, Name: "nginx", Unit: "web.service", Cgroup: "/system.slice/web.service"}
+	session := &monitor.ProcessInfo{PID: 801, Name: "bash", Unit: "session-3.scope", Cgroup: "/user.slice/user-1000.slice/session-3.scope"}
+	other := &monitor.ProcessInfo{PID: 802, Name: "syncthing", Unit: "syncthing.service",
+		Cgroup: fmt.Sprintf("/user.slice/user-%[1]d.slice/user@%[1]d.service/app.slice/syncthing.service", os.Geteuid()+1)}
+	plain := &monitor.ProcessInfo{PID: 803, Name: "vim", Cgroup: "/user.slice"}
+	procs := []*monitor.ProcessInfo{web, session, other, plain}
+	for _, p := range procs {
+		p.Category = monitor.CategoryUser
+	}
+	fake := &fakeSystemctl{}
+	mgr := process.NewManager(safety.NewManager(nil, nil, safety.ConsentAutomatic), time.Second)
+	mgr.SetRunner(fake)
+	mgr.UpdateProtection(procs)
+
+	for _, tc := range []struct {
+		proc    *monitor.ProcessInfo
+		restart string
+		ladder  string
+		want    safety.UnitAction
+	}{
+		{web, "always", "", safety.UnitStop},
+		{web, "no", "", safety.UnitSignal},
+		{web, "on-failure", "", safety.UnitStop}, // SIGKILL is a failure
+		{web, "on-failure", "SIGTERM 10s", safety.UnitSignal},
+		{session, "always", "", safety.UnitSignal}, // only services restart
+		{other, "always", "", safety.UnitSignal},   // unreachable user manager
+		{plain, "always", "", safety.UnitSignal},
+	} {
+		fake.restart = tc.restart
+		esc, _ := safety.ParseEscalation(tc.ladder)
+		mgr.SetEscalation(esc)
+		if got, _, err := mgr.UnitPlan(tc.proc); err != nil || got != tc.want {
+			t.Errorf("UnitPlan(%s, Restart=%s, %q) = %s, %v; want %s", tc.proc.Unit, tc.restart, tc.ladder, got, err, tc.want)
+		}
+	}
+	mgr.SetEscalation(nil)
+
+	for _, tc := range []struct {
+		proc   *monitor.ProcessInfo
+		action safety.UnitAction
+		want   string
+	}{
+		{other, safety.UnitStop, "another user's service manager"},
+		{session, safety.UnitRestart, "cannot be restarted"},
+		{plain, safety.UnitStop, "not owned by a systemd unit"},
+	} {
+		if _, err := mgr.StartUnitAction(context.Background(), tc.proc, tc.action); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("StartUnitAction(%s, %s) = %v, want %q", tc.proc.Name, tc.action, err, tc.want)
+		}
+	}
+
+	// A service systemd would restart is stopped instead of signalled
+	svc := startSleeper(t, "sleep")
+	svc.Unit, svc.Cgroup = "aura-test-sleep.service", "/system.slice/aura-test-sleep.service"
+	mgr.UpdateProtection([]*monitor.ProcessInfo{svc})
+	fake.restart, fake.pid = "always", svc.PID
+
+	result, err := mgr.SafeTerminate(svc, false)
+	if err != nil {
+		t.Fatalf("SafeTerminate: %v", err)
+	}
+	if result.UnitAction != safety.UnitStop || result.Scope != safety.ScopeUnit || !fake.called("systemctl stop aura-test-sleep.service") {
+		t.Errorf("result = %s %s, calls %v; want systemctl stop", result.Scope, result.UnitAction, fake.calls)
+	}
+	if !strings.HasPrefix(result.Outcome(), "stopped aura-test-sleep.service") || result.Verb() != "stop" {
+		t.Errorf("Outcome() = %q, Verb() = %q", result.Outcome(), result.Verb())
+	}
+
+	// Killing the unit climbs the ladder through systemctl kill
+	svc.PID = startSleeper(t, "sleep").PID
+	fake.pid = svc.PID
+	mgr.UpdateProtection([]*monitor.ProcessInfo{svc})
+
+	term, err := mgr.StartUnitAction(context.Background(), svc, safety.UnitKill)
+	if err != nil {
+		t.Fatalf("StartUnitAction: %v", err)
+	}
+	result, err = term.Wait()
+	if err != nil {
+		t.Fatalf("unit kill: %v", err)
+	}
+	if !fake.called("systemctl kill --signal=SIGTERM aura-test-sleep.service") || result.Signal != syscall.SIGTERM {
+		t.Errorf("exit %s, calls %v; want SIGTERM through systemctl kill", result.Exit, fake.calls)
+	}
+	if want := "systemd restarts it (Restart=always)"; !strings.Contains(result.Outcome(), want) {
+		t.Errorf("Outcome() = %q, want it to mention %q", result.Outcome(), want)
+	}
+
+	if _, err := safety.NewPolicy([]safety.Rule{{Name: "bad", Effect: safety.EffectAllow, UnitAction: "reload"}}); err == nil {
+		t.Error("an unknown unit action should be rejected")
+	}
+}
+
+// stubEngine serves the parts of the Docker engine API Aura uses on a unix
+// socket, for one container whose process stop kills.
+type stubEngine struct {
+	mu    sync.Mutex
+	calls []string
+	id    string
+	pid   int
+}
+
+func (e *stubEngine) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	e.calls = append(e.calls, r.Method+" "+r.URL.RequestURI())
+	e.mu.Unlock()
+	path, ok := strings.CutPrefix(r.URL.Path, "/containers/"+e.id+"/")
+	switch {
+	case !ok:
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintf(w, `{"message":"No such container: %s"}`, strings.Split(strings.TrimPrefix(r.URL.Path, "/containers/"), "/")[0])
+	case r.Method == http.MethodGet && path == "json":
+		fmt.Fprintf(w, `{"Id":%q,"Name":"/web-1","State":{"Status":"running"}}`, e.id)
+	case r.Method == http.MethodPost && path == "stop":
+		syscall.Kill(e.pid, syscall.SIGKILL)
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodPost && path == "pause":
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (e *stubEngine) called(call string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return slices.Contains(e.calls, call)
+}
+
+func TestContainerActions(t *testing.T) {
+	engine := &stubEngine{id: strings.Repeat("ab12", 16)}
+	sock := filepath.Join(t.TempDir(), "docker.sock")
+	ln, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Skipf("cannot listen on a unix socket: %v", err)
+	}
+	srv := httptest.NewUnstartedServer(engine)
+	srv.Listener = ln
+	srv.Start()
+	defer srv.Close()
+
+	ctr := startSleeper(t, "sleep")
+	engine.pid = ctr.PID
+
+	client := process.NewContainerClient(sock)
+	if name := client.Name(engine.id); name != "web-1" {
+		t.Errorf("Name() = %q, want web-1", name)
+	}
+	if _, err := client.Inspect(context.Background(), "gone"); err == nil || !strings.Contains(err.Error(), "No such container: gone") {
+		t.Errorf("Inspect of a missing container = %v, want the engine's message", err)
+	}
+
+	ctr.Container, ctr.ContainerName = engine.id, client.Name(engine.id)
+	ctr.Cgroup, ctr.Unit = "/system.slice/docker-"+engine.id+".scope", "docker-"+engine.id+".scope"
+	helper := &monitor.ProcessInfo{PID: 1 << 22, Name: "sh", Category: monitor.CategoryUser, Container: engine.id}
+	plain := startSleeper(t, "sleep")
+
+	policy, err := safety.NewPolicy([]safety.Rule{
+		{Name: "web", Effect: safety.EffectAllow, ContainerAction: safety.ContainerStop, Match: safety.Match{Containers: []string{"web-*"}}},
+	})
+	if err != nil {
+		t.Fatalf("NewPolicy: %v", err)
+	}
+	safetyMgr := safety.NewManager(nil, nil, safety.ConsentAutomatic)
+	safetyMgr.SetPolicy(policy)
+	mgr := process.NewManager(safetyMgr, time.Second)
+	mgr.UpdateProtection([]*monitor.ProcessInfo{helper, ctr, plain})
+
+	// Without a runtime, a container action cannot be carried out
+	if _, err := mgr.ContainerPlan(ctr); err == nil || !strings.Contains(err.Error(), "no container runtime") {
+		t.Errorf("ContainerPlan without a runtime = %v, want an error", err)
+	}
+	mgr.SetContainerClient(client)
+	if action, err := mgr.ContainerPlan(plain); err != nil || action != safety.ContainerSignal {
+		t.Errorf("ContainerPlan outside a container = %s, %v; want signal", action, err)
+	}
+	if _, err := mgr.StartContainerAction(context.Background(), plain, safety.ContainerStop); err == nil || !strings.Contains(err.Error(), "not running in a container") {
+		t.Errorf("StartContainerAction outside a container = %v, want it refused", err)
+	}
+
+	term, err := mgr.StartContainerAction(context.Background(), ctr, safety.ContainerPause)
+	if err != nil {
+		t.Fatalf("StartContainerAction: %v", err)
+	}
+	if result, err := term.Wait(); err != nil || result.Outcome() != "paused container web-1" || result.Verb() != "pause" || !result.Paused() {
+		t.Errorf("pause = %v, %v", result, err)
+	}
+	if !engine.called("POST /containers/" + engine.id + "/pause") {
+		t.Errorf("calls %v, want a pause", engine.calls)
+	}
+
+	// The policy rule stops the whole container rather than signalling
+	// the process
+	esc, _ := safety.ParseEscalation("SIGTERM 3s SIGKILL")
+	mgr.SetEscalation(esc)
+	result, err := mgr.SafeTerminate(ctr, false)
+	if err != nil {
+		t.Fatalf("SafeTerminate: %v", err)
+	}
+	if result.ContainerAction != safety.ContainerStop || result.Scope != safety.ScopeContainer || len(result.Members) != 2 || result.UnitAction != "" {
+		t.Errorf("result = %s %s %s %v, want the container stopped", result.Scope, result.ContainerAction, result.UnitAction, result.PIDs())
+	}
+	if !engine.called("POST /containers/" + engine.id + "/stop?t=3") {
+		t.Errorf("calls %v, want a stop with the ladder's 3s", engine.calls)
+	}
+	deadline := time.Now().Add(time.Second)
+	for syscall.Kill(ctr.PID, 0) == nil && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if syscall.Kill(ctr.PID, 0) == nil {
+		t.Error("the container's process survived the stop")
+	}
+
+	if _, err := safety.NewPolicy([]safety.Rule{{Name: "bad", Effect: safety.EffectAllow, ContainerAction: "kill"}}); err == nil {
+		t.Error("an unknown container action should be rejected")
+	}
+}
+
+// procLimit returns the soft and hard values of a /proc/[pid]/limits line.
+func procLimit(t *testing.T, pid int, name string) (string, string) {
+	t.Helper()
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/limits", pid))
+	if err != nil {
+		t.Fatalf("reading limits: %v", err)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if rest, ok := strings.CutPrefix(line, name); ok {
+			if f := strings.Fields(rest); len(f) >= 2 {
+				return f[0], f[1]
+			}
+		}
+	}
+	t.Fatalf("no %q in limits", name)
+	return "", ""
+}
+
+func TestLimits(t *testing.T) {
+	target, other := startSleeper(t, "sleep"), startSleeper(t, "other")
+
+	policy, err := safety.NewPolicy([]safety.Rule{{
+		Name: "sleepers", Effect: safety.EffectAllow, Match: safety.Match{Names: []string{"sleep"}},
+		Limits: safety.Limits{AddressSpaceMB: 512, CPUTime: 10 * time.Minute, OpenFiles: 64, OOMScoreAdj: 500},
+	}})
+	if err != nil {
+		t.Fatalf("NewPolicy: %v", err)
+	}
+	safetyMgr := safety.NewManager(nil, nil, safety.ConsentAutomatic)
+	safetyMgr.SetPolicy(policy)
+
+	dir := t.TempDir()
+	mgr := process.NewManager(safetyMgr, time.Second)
+	mgr.UpdateProtection([]*monitor.ProcessInfo{target, other})
+	mgr.SetLimitStore(process.NewLimitStore(dir))
+	mgr.SetThrottleLimits(safety.Limits{OpenFiles: 128})
+	var changes []process.LimitChange
+	mgr.OnLimits(func(c process.LimitChange) { changes = append(changes, c) })
+
+	if l := mgr.LimitsFor(target); l.OpenFiles != 64 || l.OOMScoreAdj != 500 {
+		t.Errorf("LimitsFor(sleep) = %+v, want the rule's", l)
+	}
+	if l := mgr.LimitsFor(other); l != (safety.Limits{OpenFiles: 128}) {
+		t.Errorf("LimitsFor(other) = %+v, want the throttle limits", l)
+	}
+
+	origFiles, hardFiles := procLimit(t, target.PID, "Max open files")
+	origAdj, _ := os.ReadFile(fmt.Sprintf("/proc/%d/oom_score_adj", target.PID))
+
+	// Dry run changes nothing
+	mgr.SetDryRun(true)
+	if rec, err := mgr.SafeLimit(target, mgr.LimitsFor(target)); err != nil || rec.Applied.OpenFiles != 64 {
+		t.Fatalf("dry-run SafeLimit = %+v, %v", rec, err)
+	}
+	if soft, _ := procLimit(t, target.PID, "Max open files"); soft != origFiles || mgr.Limited(target) != nil || len(changes) != 0 {
+		t.Errorf("dry run changed the limits: %s, %d changes", soft, len(changes))
+	}
+	mgr.SetDryRun(false)
+
+	rec, err := mgr.SafeLimit(target, mgr.LimitsFor(target))
+	if err != nil {
+		t.Fatalf("SafeLimit: %v", err)
+	}
+	if soft, hard := procLimit(t, target.PID, "Max open files"); soft != "64" || hard != hardFiles {
+		t.Errorf("open files = %s/%s, want 64/%s", soft, hard, hardFiles)
+	}
+	if soft, _ := procLimit(t, target.PID, "Max address space"); soft != strconv.Itoa(512<<20) {
+		t.Errorf("address space = %s, want 512 MB", soft)
+	}
+	if soft, _ := procLimit(t, target.PID, "Max cpu time"); soft != "600" {
+		t.Errorf("cpu time = %s, want 600 seconds more than the little sleep used", soft)
+	}
+	if adj, _ := os.ReadFile(fmt.Sprintf("/proc/%d/oom_score_adj", target.PID)); strings.TrimSpace(string(adj)) != "500" {
+		t.Errorf("oom_score_adj = %s, want 500", adj)
+	}
+
+	// Limiting again keeps the values from before the first time, and a
+	// later run of Aura finds them
+	if _, err := mgr.SafeLimit(target, safety.Limits{OpenFiles: 32}); err != nil {
+		t.Fatalf("second SafeLimit: %v", err)
+	}
+	later := process.NewManager(safetyMgr, time.Second)
+	later.SetLimitStore(process.NewLimitStore(dir))
+	later.OnLimits(func(c process.LimitChange) { changes = append(changes, c) })
+	if rec = later.Limited(target); rec == nil {
+		t.Fatal("the limit record did not persist")
+	}
+	if got := rec.Limits["Max open files"].Soft; strconv.FormatUint(got, 10) != origFiles && origFiles != "unlimited" {
+		t.Errorf("recorded open files = %d, want the original %s", got, origFiles)
+	}
+	if rec.Applied.OpenFiles != 32 || rec.Applied.OOMScoreAdj != 500 {
+		t.Errorf("applied = %+v, want the limits merged", rec.Applied)
+	}
+
+	if _, err := later.RevertLimits(target.PID); err != nil {
+		t.Fatalf("RevertLimits: %v", err)
+	}
+	if soft, _ := procLimit(t, target.PID, "Max open files"); soft != origFiles {
+		t.Errorf("open files after revert = %s, want %s", soft, origFiles)
+	}
+	if adj, _ := os.ReadFile(fmt.Sprintf("/proc/%d/oom_score_adj", target.PID)); string(adj) != string(origAdj) {
+		t.Errorf("oom_score_adj after revert = %s, want %s", adj, origAdj)
+	}
+	if _, err := later.RevertLimits(target.PID); err == nil {
+		t.Error("reverting twice should fail")
+	}
+	if len(changes) != 3 || !changes[2].Reverted {
+		t.Errorf("got %d limit changes, want two applied and one reverted", len(changes))
+	}
+
+	// An address space below what is mapped already would break the next
+	// allocation
+	if _, err := mgr.SafeLimit(target, safety.Limits{AddressSpaceMB: 1}); err == nil || !strings.Contains(err.Error(), "mapped already") {
+		t.Errorf("SafeLimit below VmSize = %v, want it refused", err)
+	}
+
+	// A CPU time limit ends in SIGXCPU: it needs the terminate action
+	guarded := safety.NewManager(nil, nil, safety.ConsentAutomatic)
+	matrix, err := safety.ParseMatrix(map[string]map[string]string{"user": {"terminate": "forbid"}})
+	if err != nil {
+		t.Fatalf("ParseMatrix: %v", err)
+	}
+	guarded.SetConsentMatrix(matrix)
+	guardedMgr := process.NewManager(guarded, time.Second)
+	guardedMgr.SetLimitStore(process.NewLimitStore(t.TempDir()))
+	if v := guardedMgr.LimitVerdict(target, safety.Limits{OpenFiles: 32}); !v.Allowed {
+		t.Errorf("open files limit refused where terminating is forbidden: %s", v.Reason)
+	}
+	if _, err := guardedMgr.SafeLimit(target, safety.Limits{CPUTime: time.Minute}); err == nil || !strings.Contains(err.Error(), "SIGXCPU") {
+		t.Errorf("cpu_time limit where terminating is forbidden = %v, want it blocked", err)
+	}
+
+	// Throttles apply the throttle limits too
+	if err := mgr.SafeThrottle(other); err != nil {
+		t.Fatalf("SafeThrottle: %v", err)
+	}
+	if soft, _ := procLimit(t, other.PID, "Max open files"); soft != "128" {
+		t.Errorf("open files after throttle = %s, want 128", soft)
+	}
+	if recs, err := mgr.LimitStore().List(); err != nil || len(recs) != 1 || recs[0].PID != other.PID {
+		t.Errorf("List = %v, %v; want the throttled process", recs, err)
+	}
+	syscall.Kill(other.PID, syscall.SIGKILL)
+	deadline := time.Now().Add(time.Second)
+	for mgr.Limited(other) != nil && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if recs, _ := mgr.LimitStore().List(); len(recs) != 0 {
+		t.Errorf("List kept %d records of exited processes", len(recs))
+	}
+
+	if _, err := safety.NewPolicy([]safety.Rule{{Name: "bad", Effect: safety.EffectAllow, Limits: safety.Limits{OOMScoreAdj: 2000}}}); err == nil {
+		t.Error("an out-of-range oom_score_adj should be rejected")
+	}
+}
+
+func TestOOMResponder(t *testing.T) {
+	start := func(name string, adj int) *monitor.ProcessInfo {
+		proc := startSleeper(t, name)
+		if err := os.WriteFile(fmt.Sprintf("/proc/%d/oom_score_adj", proc.PID), []byte(strconv.Itoa(adj)), 0); err != nil {
+			t.Skipf("cannot set oom_score_adj: %v", err)
+		}
+		return proc
+	}
+	// The kernel would pick the hog, but a policy rule protects it, and
+	// the respawner is skipped
+	hog, respawner, victim := start("hog", 1000), start("respawner", 1000), start("sleep", 800)
+	procs := []*monitor.ProcessInfo{hog, respawner, victim}
+
+	policy, err := safety.NewPolicy([]safety.Rule{{
+		Name: "keep-hog", Effect: safety.EffectDeny, Match: safety.Match{Names: []string{"hog"}},
+	}})
+	if err != nil {
+		t.Fatalf("NewPolicy: %v", err)
+	}
+	safetyMgr := safety.NewManager(nil, nil, safety.ConsentAutomatic)
+	safetyMgr.SetPolicy(policy)
+	mgr := process.NewManager(safetyMgr, time.Second)
+	mgr.UpdateProtection(procs)
+
+	r := process.NewOOMResponder(mgr,
+		process.OOMThreshold{AvailablePercent: 50},
+		process.OOMThreshold{Pressure: 40, AvailablePercent: 5},
+		func() []*monitor.ProcessInfo { return procs })
+	r.SetCooldown(time.Minute)
+	r.SetEscalation(safety.Escalation{{Signal: syscall.SIGKILL}})
+	r.SetSkip(func(p *monitor.ProcessInfo) (bool, string) { return p == respawner, "respawns" })
+	var events []process.OOMEvent
+	r.OnEvent(func(ev process.OOMEvent) { events = append(events, ev) })
+
+	victims := r.Victims()
+	if len(victims) != 1 || victims[0].Proc != victim || victims[0].OOMScore <= 800 {
+		t.Fatalf("Victims() = %+v, want only the sleep", victims)
+	}
+
+	pressure := func(full, available float64) monitor.MemoryPressure {
+		return monitor.MemoryPressure{PSI: true, Full: full, Some: full, AvailableMB: available, TotalMB: 100}
+	}
+	ctx := context.Background()
+	for _, p := range []monitor.MemoryPressure{pressure(0, 90), pressure(5, 30)} {
+		if term, err := r.Check(ctx, p); term != nil || err != nil {
+			t.Fatalf("Check(%s) = %v, %v; want nothing terminated", p, term, err)
+		}
+	}
+	if r.Level() != process.OOMWarn || len(events) != 1 || events[0].Level != process.OOMWarn {
+		t.Fatalf("level %s, events %+v; want one warn event", r.Level(), events)
+	}
+
+	// Either condition reaches the kill level; without PSI only available
+	// memory counts
+	noPSI := monitor.MemoryPressure{Full: 90, AvailableMB: 30, TotalMB: 100}
+	if r.LevelOf(noPSI) != process.OOMWarn || r.LevelOf(pressure(0, 4)) != process.OOMKill {
+		t.Errorf("LevelOf = %s, %s; want warn, kill", r.LevelOf(noPSI), r.LevelOf(pressure(0, 4)))
+	}
+
+	term, err := r.Check(ctx, pressure(45, 30))
+	if err != nil || term == nil || term.Proc != victim {
+		t.Fatalf("Check at kill level = %v, %v; want the sleep terminated", term, err)
+	}
+	result, err := term.Wait()
+	if err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+	if result.Signal != syscall.SIGKILL || !slices.Equal(result.Sent, []syscall.Signal{syscall.SIGKILL}) {
+		t.Errorf("exit %s, want the responder's ladder", result.Exit)
+	}
+	if len(events) != 3 || events[1].Level != process.OOMKill || events[2].Victim == nil || events[2].Termination != term {
+		t.Errorf("events %+v; want warn, kill and the termination", events)
+	}
+	if syscall.Kill(hog.PID, 0) != nil || syscall.Kill(respawner.PID, 0) != nil {
+		t.Error("the protected or skipped process was terminated")
+	}
+
+	// Nothing more within the cooldown
+	if term, err := r.Check(ctx, pressure(60, 1)); term != nil || err != nil {
+		t.Errorf("Check within the cooldown = %v, %v; want nothing", term, err)
+	}
+}
@@ -1,7 +1,13 @@
 package tests
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/iamgilwell/aura/internal/monitor"
 	"github.com/iamgilwell/aura/internal/safety"
@@ -134,3 +140,640 @@ func TestConsentNeedsConfirmation(t *testing.T) {
 		t.Error("level 3 should be monitor only")
 	}
 }
+
+func TestConsentMatrix(t *testing.T) {
+	userProc := &monitor.ProcessInfo{PID: 1000, Name: "firefox", Category: monitor.CategoryUser}
+	sysProc := &monitor.ProcessInfo{PID: 500, Name: "cron", Category: monitor.CategorySystem}
+	buildProc := &monitor.ProcessInfo{PID: 1200, Name: "cc1plus", Category: monitor.CategoryUser}
+
+	matrix, err := safety.ParseMatrix(map[string]map[string]string{
+		"user":      {"terminate": "confirm", "throttle": "auto", "suspend": "notify"},
+		"tag:build": {"suspend": "forbid"},
+	})
+	if err != nil {
+		t.Fatalf("ParseMatrix: %v", err)
+	}
+
+	for _, bad := range []map[string]map[string]string{
+		{"daemons": {"terminate": "auto"}},
+		{"user": {"explode": "auto"}},
+		{"user": {"terminate": "sometimes"}},
+	} {
+		if _, err := safety.ParseMatrix(bad); err == nil {
+			t.Errorf("ParseMatrix(%v) should fail", bad)
+		}
+	}
+
+	policy, err := safety.NewPolicy([]safety.Rule{
+		{Name: "compilers", Effect: safety.EffectAllow, Tags: []string{"build"}, Match: safety.Match{Names: []string{"cc1*"}}},
+	})
+	if err != nil {
+		t.Fatalf("NewPolicy: %v", err)
+	}
+
+	mgr := safety.NewManager(nil, nil, safety.ConsentAutomatic)
+	mgr.SetPolicy(policy)
+	mgr.SetConsentMatrix(matrix)
+
+	if v := mgr.EvaluateAction(userProc, safety.ActionThrottle); !v.Allowed || v.Confirm || v.Notify {
+		t.Errorf("user throttle should be automatic, got %+v", v)
+	}
+	if v := mgr.EvaluateAction(userProc, safety.ActionTerminate); !v.Allowed || !v.Confirm {
+		t.Errorf("user terminate should need confirmation, got %+v", v)
+	}
+	if !mgr.NeedsConfirmation(userProc) {
+		t.Error("NeedsConfirmation should follow the terminate cell")
+	}
+	if v := mgr.EvaluateAction(userProc, safety.ActionSuspend); !v.Allowed || !v.Notify {
+		t.Errorf("user suspend should notify, got %+v", v)
+	}
+	if v := mgr.EvaluateAction(sysProc, safety.ActionTerminate); !v.Allowed || v.Confirm {
+		t.Errorf("system terminate should keep the level 0 preset, got %+v", v)
+	}
+
+	// Tags from the matching policy rule take precedence over the category
+	if v := mgr.EvaluateAction(buildProc, safety.ActionSuspend); v.Allowed {
+		t.Error("suspending a build-tagged process should be forbidden")
+	}
+	if mode := mgr.Consent(buildProc, safety.ActionThrottle); mode != safety.ModeAuto {
+		t.Errorf("build throttle should fall back to the user row, got %s", mode)
+	}
+
+	// Monitor-only forbids everything, overrides included, but does not
+	// make processes protected
+	mgr.SetConsentLevel(safety.ConsentMonitorOnly)
+	if v := mgr.EvaluateAction(userProc, safety.ActionThrottle); v.Allowed {
+		t.Error("monitor-only should forbid throttling")
+	}
+	if mgr.IsProtected(userProc) {
+		t.Error("consent should not affect IsProtected")
+	}
+
+	// Presets reproduce the legacy levels
+	preset := safety.Preset(safety.ConsentNotifySystem)
+	if preset["system"][safety.ActionTerminate] != safety.ModeConfirm || preset["*"][safety.ActionTerminate] != safety.ModeAuto {
+		t.Errorf("unexpected level 1 preset: %v", preset)
+	}
+}
+
+func TestSafetyPolicy(t *testing.T) {
+	policy, err := safety.NewPolicy([]safety.Rule{
+		{Name: "keep-db", Effect: safety.EffectDeny, Match: safety.Match{Names: []string{"postgres*"}}},
+		{Name: "confirm-editors", Effect: safety.EffectConfirm, Match: safety.Match{Names: []string{"vim"}}},
+		{Name: "stale-node", Effect: safety.EffectAllow, Match: safety.Match{
+			Cmdline:  `node .*dev`,
+			UIDRange: []int{1000, 2000},
+			MinAge:   time.Hour,
+		}},
+		{Name: "unprotect-pipewire", Effect: safety.EffectAllow, Match: safety.Match{Units: []string{"pipewire.service"}}},
+	})
+	if err != nil {
+		t.Fatalf("NewPolicy: %v", err)
+	}
+
+	mgr := safety.NewManager([]string{"pipewire"}, []string{"systemd"}, safety.ConsentAutomatic)
+	mgr.SetPolicy(policy)
+
+	old := time.Now().Add(-2 * time.Hour)
+	tests := []struct {
+		name    string
+		proc    *monitor.ProcessInfo
+		allowed bool
+		confirm bool
+		rule    string
+	}{
+		{"deny rule", &monitor.ProcessInfo{PID: 100, Name: "postgres"}, false, false, "keep-db"},
+		{"confirm rule", &monitor.ProcessInfo{PID: 101, Name: "vim"}, true, true, "confirm-editors"},
+		{"allow rule", &monitor.ProcessInfo{PID: 102, Name: "node", Cmdline: "node server.js --dev", UID: 1000, StartTime: old}, true, false, "stale-node"},
+		{"too young", &monitor.ProcessInfo{PID: 103, Name: "node", Cmdline: "node server.js --dev", UID: 1000, StartTime: time.Now()}, true, false, ""},
+		{"allow overrides protected list", &monitor.ProcessInfo{PID: 104, Name: "pipewire", Unit: "pipewire.service"}, true, false, "unprotect-pipewire"},
+		{"never-terminate beats policy", &monitor.ProcessInfo{PID: 105, Name: "systemd", Unit: "pipewire.service"}, false, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := mgr.Evaluate(tt.proc)
+			if v.Allowed != tt.allowed || v.Confirm != tt.confirm || v.Rule != tt.rule {
+				t.Errorf("Evaluate = %+v, want allowed=%v confirm=%v rule=%q", v, tt.allowed, tt.confirm, tt.rule)
+			}
+			if tt.rule != "" && !strings.Contains(v.Reason, tt.rule) {
+				t.Errorf("reason %q does not name rule %q", v.Reason, tt.rule)
+			}
+			if mgr.NeedsConfirmation(tt.proc) != tt.confirm {
+				t.Errorf("NeedsConfirmation = %v, want %v", !tt.confirm, tt.confirm)
+			}
+		})
+	}
+}
+
+func TestSafetyPolicySockets(t *testing.T) {
+	policy, err := safety.NewPolicy([]safety.Rule{
+		{Name: "keep-ssh", Effect: safety.EffectDeny, Match: safety.Match{ListenPorts: []int{22}}},
+		{Name: "confirm-db-clients", Effect: safety.EffectConfirm, Match: safety.Match{ConnectionPorts: []int{5432}}},
+		{Name: "confirm-busy", Effect: safety.EffectConfirm, Match: safety.Match{Listening: true, MinConnections: 2}},
+	})
+	if err != nil {
+		t.Fatalf("NewPolicy: %v", err)
+	}
+	mgr := safety.NewManager(nil, nil, safety.ConsentAutomatic)
+	mgr.SetPolicy(policy)
+
+	tcp := func(local, remote int) monitor.Socket {
+		return monitor.Socket{Proto: "tcp", LocalPort: local, RemotePort: remote}
+	}
+	unix := monitor.Socket{Proto: "unix", Path: "/run/app.sock"}
+	tests := []struct {
+		name    string
+		proc    *monitor.ProcessInfo
+		allowed bool
+		confirm bool
+		rule    string
+	}{
+		{"ssh listener", &monitor.ProcessInfo{PID: 200, Name: "sshd", Listeners: []monitor.Socket{tcp(22, 0)}}, false, false, "keep-ssh"},
+		{"unix socket is not a port", &monitor.ProcessInfo{PID: 201, Name: "app", Listeners: []monitor.Socket{unix}}, true, false, ""},
+		{"database client", &monitor.ProcessInfo{PID: 202, Name: "worker", Connections: []monitor.Socket{tcp(40000, 5432)}}, true, true, "confirm-db-clients"},
+		{"busy server", &monitor.ProcessInfo{PID: 203, Name: "api", Listeners: []monitor.Socket{unix},
+			Connections: []monitor.Socket{tcp(8080, 50000), {Proto: "udp", LocalPort: 5353, RemotePort: 53}}}, true, true, "confirm-busy"},
+		{"unix connections are not traffic", &monitor.ProcessInfo{PID: 205, Name: "app", Listeners: []monitor.Socket{unix},
+			Connections: []monitor.Socket{tcp(8080, 50000), {Proto: "unix"}, {Proto: "unix"}}}, true, false, ""},
+		{"idle server", &monitor.ProcessInfo{PID: 204, Name: "api", Listeners: []monitor.Socket{tcp(8080, 0)},
+			Connections: []monitor.Socket{tcp(8080, 50000)}}, true, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := mgr.Evaluate(tt.proc)
+			if v.Allowed != tt.allowed || v.Confirm != tt.confirm || v.Rule != tt.rule {
+				t.Errorf("Evaluate = %+v, want allowed=%v confirm=%v rule=%q", v, tt.allowed, tt.confirm, tt.rule)
+			}
+		})
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	content := `rules:
+  - name: system-slice
+    effect: deny
+    match:
+      cgroup: "/system.slice/*"
+      category: system
+  - name: big
+    effect: confirm
+    match:
+      min_memory_mb: 1024
+      max_age: "30m"
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	policy, err := safety.LoadPolicy(path)
+	if err != nil {
+		t.Fatalf("LoadPolicy: %v", err)
+	}
+	if len(policy.Rules) != 2 || policy.Rules[1].Match.MaxAge != 30*time.Minute {
+		t.Fatalf("unexpected rules: %+v", policy.Rules)
+	}
+
+	proc := &monitor.ProcessInfo{PID: 300, Name: "cron", Category: monitor.CategorySystem, Cgroup: "/system.slice/cron.service"}
+	if rule := policy.Evaluate(proc); rule == nil || rule.Name != "system-slice" {
+		t.Errorf("Evaluate = %v, want system-slice", rule)
+	}
+
+	traces := policy.Trace(&monitor.ProcessInfo{PID: 301, Name: "firefox", MemoryMB: 100, StartTime: time.Now()})
+	if len(traces) != 2 || traces[0].Matched || traces[1].Matched || traces[1].Mismatch == "" {
+		t.Errorf("unexpected trace: %+v", traces)
+	}
+
+	if _, err := safety.NewPolicy([]safety.Rule{{Name: "bad", Effect: "kill"}}); err == nil {
+		t.Error("expected error for unknown effect")
+	}
+	if _, err := safety.NewPolicy([]safety.Rule{{Name: "bad", Effect: safety.EffectDeny, Match: safety.Match{Cmdline: "("}}}); err == nil {
+		t.Error("expected error for invalid cmdline pattern")
+	}
+}
+
+func TestSafetyPins(t *testing.T) {
+	mgr := safety.NewManager([]string{"sshd"}, []string{"systemd"}, safety.ConsentAutomatic)
+	mgr.SetPins(monitor.NewPinSet([]monitor.Pin{
+		{Name: "sshd", Exe: "/usr/sbin/sshd"},
+		{Name: "systemd", Exe: "/usr/lib/systemd/systemd"},
+	}))
+
+	tests := []struct {
+		name    string
+		proc    *monitor.ProcessInfo
+		allowed bool
+	}{
+		{"genuine sshd", &monitor.ProcessInfo{PID: 700, Name: "sshd", Exe: "/usr/sbin/sshd"}, false},
+		{"script named sshd", &monitor.ProcessInfo{PID: 701, Name: "sshd", Exe: "/home/eve/sshd"}, true},
+		{"renamed sshd", &monitor.ProcessInfo{PID: 702, Name: "notsshd", Exe: "/usr/sbin/sshd"}, false},
+		{"renamed systemd", &monitor.ProcessInfo{PID: 703, Name: "x", Exe: "/usr/lib/systemd/systemd"}, false},
+		{"unverifiable sshd", &monitor.ProcessInfo{PID: 704, Name: "sshd"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := mgr.Evaluate(tt.proc)
+			if v.Allowed != tt.allowed {
+				t.Errorf("Evaluate = %+v, want allowed=%v", v, tt.allowed)
+			}
+		})
+	}
+
+	if v := mgr.Evaluate(&monitor.ProcessInfo{PID: 701, Name: "sshd", Exe: "/home/eve/sshd"}); !strings.Contains(v.Reason, "suspicious") {
+		t.Errorf("spoofed reason %q does not flag the process as suspicious", v.Reason)
+	}
+}
+
+func TestSafetyBudget(t *testing.T) {
+	proc := func(pid int, user, exe string, memMB float64) *monitor.ProcessInfo {
+		return &monitor.ProcessInfo{PID: pid, Name: "worker", User: user, Exe: exe, MemoryMB: memMB}
+	}
+	var mgr *safety.Manager
+	charge := func(p *monitor.ProcessInfo) (bool, string) {
+		ok, reason := mgr.CheckAutomatic(p)
+		if ok {
+			mgr.ChargeAutomatic(p)
+		}
+		return ok, reason
+	}
+
+	mgr = safety.NewManager(nil, nil, safety.ConsentAutomatic)
+	mgr.SetBudget(safety.Budget{MaxPerMinute: 2})
+	for i := 0; i < 2; i++ {
+		if ok, reason := charge(proc(1000+i, "alice", "", 10)); !ok {
+			t.Fatalf("charge %d refused: %s", i, reason)
+		}
+	}
+	if ok, _ := charge(proc(1002, "alice", "", 10)); ok {
+		t.Fatal("third termination in a minute should exceed the budget")
+	}
+	if !mgr.IsMonitorOnly() || mgr.Tripped() == "" {
+		t.Error("exceeding a budget should trip into monitor-only mode")
+	}
+	// Scans re-evaluate the schedule; a trip outlasts them
+	if s, _ := mgr.ApplySchedule(time.Now(), 5); s.ConsentLevel != safety.ConsentMonitorOnly || !mgr.IsMonitorOnly() {
+		t.Errorf("ApplySchedule after a trip = %+v, want monitor-only", s)
+	}
+
+	// Checking alone does not count, and refunded terminations do not
+	mgr = safety.NewManager(nil, nil, safety.ConsentAutomatic)
+	mgr.SetBudget(safety.Budget{MaxPerMinute: 1})
+	for i := 0; i < 3; i++ {
+		if ok, reason := mgr.CheckAutomatic(proc(1000, "alice", "", 10)); !ok {
+			t.Fatalf("check %d refused: %s", i, reason)
+		}
+	}
+	mgr.ChargeAutomatic(proc(1000, "alice", "", 10))
+	mgr.RefundAutomatic(proc(1000, "alice", "", 10))
+	if ok, reason := charge(proc(1001, "alice", "", 10)); !ok {
+		t.Errorf("refunded termination still counted: %s", reason)
+	}
+
+	mgr = safety.NewManager(nil, nil, safety.ConsentAutomatic)
+	mgr.SetBudget(safety.Budget{MaxMemoryMB: 1000, MemoryWindow: time.Hour})
+	if ok, _ := charge(proc(1000, "alice", "", 800)); !ok {
+		t.Fatal("first charge should fit the memory budget")
+	}
+	if ok, reason := charge(proc(1001, "alice", "", 300)); ok || !strings.Contains(reason, "MB") {
+		t.Errorf("memory budget not enforced: ok=%v reason=%q", ok, reason)
+	}
+
+	mgr = safety.NewManager(nil, nil, safety.ConsentAutomatic)
+	mgr.SetBudget(safety.Budget{MaxExeShare: 0.5})
+	var procs []*monitor.ProcessInfo
+	for i := 0; i < 4; i++ {
+		procs = append(procs, proc(2000+i, "alice", "/usr/bin/worker", 10))
+	}
+	procs = append(procs, proc(3000, "bob", "/usr/bin/solo", 10))
+	mgr.ObserveProcesses(procs)
+	if ok, _ := charge(procs[4]); !ok {
+		t.Error("single-process executable should not hit the share limit")
+	}
+	if ok, _ := charge(procs[0]); !ok {
+		t.Error("first of four workers should be allowed")
+	}
+	mgr.ObserveProcesses(procs[1:4])
+	if ok, _ := charge(procs[1]); !ok {
+		t.Error("second of four workers should be allowed")
+	}
+	mgr.ObserveProcesses(procs[2:4])
+	if ok, _ := charge(procs[2]); ok {
+		t.Error("third of four workers exceeds a 50% share")
+	}
+
+	// Terminations within one scan are not counted twice
+	mgr = safety.NewManager(nil, nil, safety.ConsentAutomatic)
+	mgr.SetBudget(safety.Budget{MaxExeShare: 0.5})
+	mgr.ObserveProcesses(procs)
+	for i, want := range []bool{true, true, false} {
+		if ok, reason := charge(procs[i]); ok != want {
+			t.Errorf("worker %d of 4 in one scan: ok=%v (%s), want %v", i+1, ok, reason, want)
+		}
+	}
+}
+
+func TestPendingQueue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pending.json")
+	queue := safety.NewPendingQueue(path, time.Hour)
+
+	proc := &monitor.ProcessInfo{PID: 4242, Name: "node", Exe: "/usr/bin/node", Cmdline: "node dev", StartTime: time.Now().Add(-time.Hour).Truncate(time.Second)}
+	item, added, err := queue.Add(safety.NewPendingItem(proc, safety.ActionTerminate, "idle dev server"))
+	if err != nil || !added || item.ID == "" {
+		t.Fatalf("Add = %+v, %v, %v", item, added, err)
+	}
+	if again, added, _ := queue.Add(safety.NewPendingItem(proc, safety.ActionTerminate, "still idle")); added || again.ID != item.ID {
+		t.Error("the same process should not be queued twice")
+	}
+
+	// A second queue on the same file sees the item
+	other := safety.NewPendingQueue(path, time.Hour)
+	items, err := other.List()
+	if err != nil || len(items) != 1 || !items[0].Matches(proc) {
+		t.Fatalf("List = %+v, %v", items, err)
+	}
+
+	reused := *proc
+	reused.StartTime = time.Now()
+	if items[0].Matches(&reused) {
+		t.Error("a reused PID should not match the parked process")
+	}
+
+	if _, err := other.Take(item.ID); err != nil {
+		t.Fatalf("Take: %v", err)
+	}
+	if _, err := queue.Take(item.ID); err == nil {
+		t.Error("taking a resolved item should fail")
+	}
+
+	// An approval whose action failed goes back under its ID, once
+	if err := queue.Requeue(item); err != nil {
+		t.Fatalf("Requeue: %v", err)
+	}
+	queue.Requeue(item)
+	if items, _ := other.List(); len(items) != 1 || items[0].ID != item.ID || !items[0].ExpiresAt.Equal(item.ExpiresAt) {
+		t.Errorf("List after Requeue = %+v, want the item back", items)
+	}
+	if _, err := other.Take(item.ID); err != nil {
+		t.Fatalf("Take after Requeue: %v", err)
+	}
+
+	expiring := safety.NewPendingQueue(path, 0)
+	stale, _, _ := expiring.Add(safety.NewPendingItem(proc, safety.ActionTerminate, "stale"))
+	if _, err := expiring.Take(stale.ID); !errors.Is(err, safety.ErrPendingExpired) {
+		t.Errorf("Take of expired item = %v, want ErrPendingExpired", err)
+	}
+	expiring.Add(safety.NewPendingItem(proc, safety.ActionTerminate, "stale"))
+	if expired, _ := expiring.Expire(); len(expired) != 1 {
+		t.Errorf("Expire returned %d items, want 1", len(expired))
+	}
+}
+
+func TestSnoozeStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snooze.json")
+	store := safety.NewSnoozeStore(path)
+
+	proc := &monitor.ProcessInfo{PID: 4242, Name: "node", Exe: "/usr/bin/node", Cmdline: "node dev"}
+	kept, err := store.Add(safety.NewSnooze(proc, 0))
+	if err != nil || kept.ID == "" || !kept.Permanent() {
+		t.Fatalf("Add = %+v, %v", kept, err)
+	}
+
+	// A restart under a new PID is still covered; another command line is not
+	restarted := *proc
+	restarted.PID = 5151
+	other := *proc
+	other.Cmdline = "node build"
+
+	// A second store on the same file sees the snooze
+	snoozes, err := safety.NewSnoozeStore(path).List()
+	if err != nil || len(snoozes) != 1 {
+		t.Fatalf("List = %+v, %v", snoozes, err)
+	}
+	if _, ok := snoozes.Find(&restarted); !ok {
+		t.Error("a restarted process should stay kept")
+	}
+	if _, ok := snoozes.Find(&other); ok {
+		t.Error("another command line should not be kept")
+	}
+	if left := snoozes.Filter([]*monitor.ProcessInfo{proc, &restarted, &other}); len(left) != 1 || left[0] != &other {
+		t.Errorf("Filter left %d processes, want only the other command line", len(left))
+	}
+
+	// Snoozing the same process again replaces the snooze
+	snoozed, _ := store.Add(safety.NewSnooze(proc, 4*time.Hour))
+	if snoozes, _ := store.List(); len(snoozes) != 1 || snoozes[0].ID != snoozed.ID || snoozed.Permanent() {
+		t.Errorf("List after re-snooze = %+v", snoozes)
+	}
+
+	pattern, err := safety.NewPatternSnooze("/usr/*/node", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pattern.Matches(&other) {
+		t.Error("pattern should match the executable path")
+	}
+	if _, err := safety.NewPatternSnooze("[", time.Hour); err == nil {
+		t.Error("an invalid pattern should be rejected")
+	}
+
+	expired, _ := safety.NewPatternSnooze("python*", time.Hour)
+	expired.Until = time.Now().Add(-time.Minute)
+	store.Add(pattern)
+	store.Add(expired)
+	if snoozes, _ := store.List(); len(snoozes) != 2 {
+		t.Errorf("List returned %d snoozes, want 2 without the expired one", len(snoozes))
+	}
+
+	if removed, err := store.Remove("/usr/*/node"); err != nil || len(removed) != 1 {
+		t.Errorf("Remove(pattern) = %+v, %v", removed, err)
+	}
+	if removed, err := store.RemoveProcess(&restarted); err != nil || len(removed) != 1 {
+		t.Errorf("RemoveProcess = %+v, %v", removed, err)
+	}
+	if _, err := store.Remove("missing"); err == nil {
+		t.Error("removing an unknown snooze should fail")
+	}
+}
+
+func TestDataGuard(t *testing.T) {
+	dir := t.TempDir()
+
+	db, err := os.OpenFile(filepath.Join(dir, "data.db"), os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if err := syscall.Flock(int(db.Fd()), syscall.LOCK_EX); err != nil {
+		t.Skipf("flock unavailable: %v", err)
+	}
+	swap, err := os.OpenFile(filepath.Join(dir, ".notes.txt.swp"), os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer swap.Close()
+	logFile, err := os.OpenFile(filepath.Join(dir, "app.log"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logFile.Close()
+
+	self := &monitor.ProcessInfo{PID: os.Getpid(), Name: "tests", Category: monitor.CategoryUser}
+	guard := &safety.DataGuard{WriteRate: 1024 * 1024}
+	risk := guard.Inspect(self)
+
+	contains := func(list []string, substr string) bool {
+		for _, s := range list {
+			if strings.Contains(s, substr) {
+				return true
+			}
+		}
+		return false
+	}
+	if !contains(risk.Locks, "FLOCK WRITE "+db.Name()) {
+		t.Errorf("lock on %s not found in %v", db.Name(), risk.Locks)
+	}
+	if !contains(risk.Writable, db.Name()) {
+		t.Errorf("writable %s not found in %v", db.Name(), risk.Writable)
+	}
+	if contains(risk.Writable, "app.log") {
+		t.Errorf("append-only log should not count as writable: %v", risk.Writable)
+	}
+	if !contains(risk.SwapFiles, ".notes.txt.swp") {
+		t.Errorf("swap file not found in %v", risk.SwapFiles)
+	}
+
+	mgr := safety.NewManager(nil, nil, safety.ConsentAutomatic)
+	if v := mgr.Evaluate(self); !v.Allowed || v.Confirm {
+		t.Fatalf("without a guard termination is automatic, got %+v", v)
+	}
+	mgr.SetDataGuard(guard)
+
+	v := mgr.Evaluate(self)
+	if !v.Allowed || !v.Confirm || !strings.Contains(v.Reason, "possible data loss") {
+		t.Errorf("held files should require confirmation, got %+v", v)
+	}
+	if v := mgr.EvaluateAction(self, safety.ActionSuspend); v.Confirm {
+		t.Errorf("the guard only applies to terminations, got %+v", v)
+	}
+
+	self.IOWriteRate = 8 * 1024 * 1024
+	if v := mgr.Evaluate(self); v.Allowed || !strings.Contains(v.Reason, "data loss likely") {
+		t.Errorf("a process writing to held files should be refused, got %+v", v)
+	}
+}
+
+func TestCronSchedule(t *testing.T) {
+	for _, expr := range []string{"* * * *", "60 * * * *", "* 5-1 * * *", "*/0 * * * *", "* * * * funday"} {
+		if _, err := safety.ParseCron(expr, ""); err == nil {
+			t.Errorf("ParseCron(%q) should fail", expr)
+		}
+	}
+	if _, err := safety.ParseCron("* * * * *", "Nowhere/City"); err == nil {
+		t.Error("unknown time zone should fail")
+	}
+
+	utc := func(s string) time.Time {
+		ts, err := time.Parse("2006-01-02 15:04", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ts
+	}
+	tests := []struct {
+		expr string
+		at   string
+		want bool
+	}{
+		{"* 0-6 * * *", "2024-03-05 06:59", true},
+		{"* 0-6 * * *", "2024-03-05 07:00", false},
+		{"* * * * sat,sun", "2024-03-09 12:00", true}, // Saturday
+		{"* * * * 6-7", "2024-03-10 12:00", true},     // Sunday as 7
+		{"* * * * sat,sun", "2024-03-11 12:00", false},
+		{"*/15 9-17/2 * * *", "2024-03-11 11:30", true},
+		{"*/15 9-17/2 * * *", "2024-03-11 10:30", false},
+		{"5/15 * * * *", "2024-03-11 10:50", true}, // 5, 20, 35 and 50
+		{"5/15 * * * *", "2024-03-11 10:45", false},
+		{"* * 1 jan-mar *", "2024-02-01 00:00", true},
+		{"* * 13 * fri", "2024-03-13 08:00", true}, // day of month or weekday
+		{"* * 13 * fri", "2024-03-15 08:00", true},
+		{"* * 13 * fri", "2024-03-14 08:00", false},
+	}
+	for _, tt := range tests {
+		c, err := safety.ParseCron(tt.expr, "UTC")
+		if err != nil {
+			t.Fatalf("ParseCron(%q): %v", tt.expr, err)
+		}
+		if got := c.Matches(utc(tt.at)); got != tt.want {
+			t.Errorf("%q at %s = %v, want %v", tt.expr, tt.at, got, tt.want)
+		}
+	}
+
+	if _, err := time.LoadLocation("Asia/Tokyo"); err == nil {
+		c, _ := safety.ParseCron("* 9 * * *", "Asia/Tokyo")
+		if !c.Matches(utc("2024-03-05 00:30")) || c.Matches(utc("2024-03-05 09:30")) {
+			t.Error("cron should be evaluated in its time zone")
+		}
+	}
+}
+
+func TestScheduleWindows(t *testing.T) {
+	always, _ := safety.ParseCron("* * * * *", "UTC")
+	never, _ := safety.ParseCron("0 0 30 2 *", "UTC")
+	level := func(l int) *int { return &l }
+
+	if _, err := safety.NewSchedule([]safety.Window{{Name: "a", Cron: always}, {Name: "a", Cron: never}}); err == nil {
+		t.Error("duplicate window names should fail")
+	}
+	if _, err := safety.NewSchedule([]safety.Window{{Name: "a", Cron: always, ConsentLevel: level(4)}}); err == nil {
+		t.Error("consent level 4 should fail")
+	}
+
+	sched, err := safety.NewSchedule([]safety.Window{
+		{Name: "overnight", Cron: always, ConsentLevel: level(0), Aggressiveness: 8},
+		{Name: "deploy", Cron: always, ConsentLevel: level(3)},
+		{Name: "weekend", Cron: never, Aggressiveness: 2},
+	})
+	if err != nil {
+		t.Fatalf("NewSchedule: %v", err)
+	}
+	s := sched.Resolve(time.Now(), safety.Settings{ConsentLevel: 2, Aggressiveness: 5})
+	if s.ConsentLevel != 3 || s.Aggressiveness != 8 || strings.Join(s.Windows, ",") != "overnight,deploy" {
+		t.Errorf("overlapping windows should take the most conservative values, got %+v", s)
+	}
+
+	policy, err := safety.NewPolicy([]safety.Rule{
+		{Name: "deploy-freeze", Effect: safety.EffectDeny, Schedule: "deploy", Match: safety.Match{Names: []string{"node"}}},
+		{Name: "weekend-cleanup", Effect: safety.EffectAllow, Schedule: "weekend", Match: safety.Match{Names: []string{"java"}}},
+	})
+	if err != nil {
+		t.Fatalf("NewPolicy: %v", err)
+	}
+	mgr := safety.NewManager([]string{"java"}, nil, safety.ConsentAutomatic)
+	mgr.SetPolicy(policy)
+	mgr.SetSchedule(sched)
+
+	node := &monitor.ProcessInfo{PID: 300, Name: "node"}
+	java := &monitor.ProcessInfo{PID: 301, Name: "java"}
+	if v := mgr.Evaluate(node); v.Allowed || v.Rule != "deploy-freeze" {
+		t.Errorf("rule should apply inside its window, got %+v", v)
+	}
+	if v := mgr.Evaluate(java); v.Allowed || v.Rule != "" {
+		t.Errorf("rule should not apply outside its window, got %+v", v)
+	}
+
+	s, changed := mgr.ApplySchedule(time.Now(), 5)
+	if !changed || s.ConsentLevel != 3 || !mgr.IsMonitorOnly() {
+		t.Errorf("ApplySchedule = %+v, %v; want monitor-only", s, changed)
+	}
+	if _, changed := mgr.ApplySchedule(time.Now(), 5); changed {
+		t.Error("unchanged windows should not report a change")
+	}
+
+	mgr.SetSchedule(nil)
+	if s, changed := mgr.ApplySchedule(time.Now(), 5); !changed || s.ConsentLevel != 0 || mgr.ConsentLevel() != 0 {
+		t.Errorf("without windows the base level should return, got %+v", s)
+	}
+}